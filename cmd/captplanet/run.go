@@ -21,6 +21,7 @@ import (
 	"storj.io/storj/pkg/datarepair/checker"
 	"storj.io/storj/pkg/datarepair/repairer"
 	"storj.io/storj/pkg/discovery"
+	"storj.io/storj/pkg/expiration"
 	"storj.io/storj/pkg/inspector"
 	"storj.io/storj/pkg/kademlia"
 	"storj.io/storj/pkg/miniogw"
@@ -30,6 +31,8 @@ import (
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/satellite/satelliteweb"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/uptime"
 	"storj.io/storj/pkg/utils"
 	"storj.io/storj/satellite/satellitedb"
 )
@@ -54,6 +57,8 @@ type Satellite struct {
 	Discovery   discovery.Config
 	Tally       tally.Config
 	Rollup      rollup.Config
+	Expiration  expiration.Config
+	Uptime      uptime.Config
 }
 
 // StorageNode is for configuring storage nodes
@@ -74,6 +79,7 @@ var (
 		Satellite    Satellite
 		StorageNodes [storagenodeCount]StorageNode
 		Uplink       miniogw.Config
+		Transport    transport.Config
 	}
 )
 
@@ -85,6 +91,7 @@ func init() {
 func cmdRun(cmd *cobra.Command, args []string) (err error) {
 	ctx := process.Ctx(cmd)
 	defer mon.Task()(&ctx)(&err)
+	transport.Configure(runCfg.Transport)
 
 	errch := make(chan error, len(runCfg.StorageNodes)+2)
 	// start mini redis
@@ -129,16 +136,18 @@ func cmdRun(cmd *cobra.Command, args []string) (err error) {
 		errch <- runCfg.Satellite.Identity.Run(ctx,
 			grpcauth.NewAPIKeyInterceptor(),
 			runCfg.Satellite.Kademlia,
-			runCfg.Satellite.Audit,
-			runCfg.Satellite.Overlay,
+			&runCfg.Satellite.Audit,
+			&runCfg.Satellite.Overlay,
 			runCfg.Satellite.Discovery,
 			runCfg.Satellite.PointerDB,
-			runCfg.Satellite.Checker,
-			runCfg.Satellite.Repairer,
+			&runCfg.Satellite.Checker,
+			&runCfg.Satellite.Repairer,
 			runCfg.Satellite.BwAgreement,
 			runCfg.Satellite.Web,
 			runCfg.Satellite.Tally,
 			runCfg.Satellite.Rollup,
+			runCfg.Satellite.Expiration,
+			&runCfg.Satellite.Uptime,
 
 			// NB(dylan): Inspector is only used for local development and testing.
 			// It should not be added to the Satellite startup
@@ -147,8 +156,8 @@ func cmdRun(cmd *cobra.Command, args []string) (err error) {
 	}()
 
 	// start the storagenodes
-	for i, v := range runCfg.StorageNodes {
-		go func(i int, v StorageNode) {
+	for i := range runCfg.StorageNodes {
+		go func(i int, v *StorageNode) {
 			identity, err := v.Identity.Load()
 			if err != nil {
 				return
@@ -158,8 +167,8 @@ func cmdRun(cmd *cobra.Command, args []string) (err error) {
 			storagenode := fmt.Sprintf("%s:%s", identity.ID.String(), address)
 
 			_, _ = fmt.Printf("Starting storage node %d %s (kad on %s)\n", i, storagenode, address)
-			errch <- v.Identity.Run(ctx, nil, v.Kademlia, v.Storage)
-		}(i, v)
+			errch <- v.Identity.Run(ctx, nil, v.Kademlia, &v.Storage)
+		}(i, &runCfg.StorageNodes[i])
 	}
 
 	// start s3 uplink
@@ -12,6 +12,7 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/spf13/cobra"
@@ -87,6 +88,11 @@ var (
 		Short: "dump all nodes in the routing table",
 		RunE:  DumpNodes,
 	}
+	crawlStatsCmd = &cobra.Command{
+		Use:   "crawl-stats",
+		Short: "get the statistics of the crawls of the network",
+		RunE:  GetCrawlStats,
+	}
 	getStatsCmd = &cobra.Command{
 		Use:   "getstats <node_id>",
 		Short: "Get node stats",
@@ -223,6 +229,30 @@ func LookupNode(cmd *cobra.Command, args []string) (err error) {
 	return nil
 }
 
+// GetCrawlStats returns the statistics of the crawls of the network by discovery
+func GetCrawlStats(cmd *cobra.Command, args []string) (err error) {
+	i, err := NewInspector(*Addr)
+	if err != nil {
+		return ErrInspectorDial.Wrap(err)
+	}
+
+	stats, err := i.client.GetCrawlStats(context.Background(), &pb.GetCrawlStatsRequest{})
+	if err != nil {
+		return ErrRequest.Wrap(err)
+	}
+
+	lastCrawl := "never"
+	if stats.LastCrawlUnixSec != 0 {
+		lastCrawl = time.Unix(stats.LastCrawlUnixSec, 0).String()
+	}
+
+	fmt.Printf("Crawls ---------------- \n - Crawls: %+v\n - Last crawl: %s\n", stats.Crawls, lastCrawl)
+	fmt.Printf(" - Nodes queried: %+v\n - Queries failed: %+v\n - Nodes found: %+v\n",
+		stats.NodesQueried, stats.QueriesFailed, stats.NodesFound)
+	fmt.Printf(" - Nodes refreshed: %+v\n - Nodes evicted: %+v\n", stats.NodesRefreshed, stats.NodesEvicted)
+	return nil
+}
+
 // DumpNodes outputs a json list of every node in every bucket in the satellite
 func DumpNodes(cmd *cobra.Command, args []string) (err error) {
 	fmt.Println("querying for buckets and nodes, sit tight....")
@@ -475,6 +505,7 @@ func init() {
 	kadCmd.AddCommand(pingNodeCmd)
 	kadCmd.AddCommand(lookupNodeCmd)
 	kadCmd.AddCommand(dumpNodesCmd)
+	kadCmd.AddCommand(crawlStatsCmd)
 
 	statsCmd.AddCommand(getStatsCmd)
 	statsCmd.AddCommand(getCSVStatsCmd)
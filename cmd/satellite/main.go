@@ -24,6 +24,7 @@ import (
 	"storj.io/storj/pkg/datarepair/queue"
 	"storj.io/storj/pkg/datarepair/repairer"
 	"storj.io/storj/pkg/discovery"
+	"storj.io/storj/pkg/expiration"
 	"storj.io/storj/pkg/kademlia"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pb"
@@ -31,6 +32,8 @@ import (
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/uptime"
 	"storj.io/storj/satellite/satellitedb"
 	"storj.io/storj/storage/redis"
 )
@@ -73,6 +76,9 @@ var (
 		BwAgreement bwagreement.Config
 		Database    string `help:"satellite database connection string" default:"sqlite3://$CONFDIR/master.db"`
 		Discovery   discovery.Config
+		Expiration  expiration.Config
+		Uptime      uptime.Config
+		Transport   transport.Config
 	}
 	setupCfg struct {
 		CA        provider.CASetupConfig
@@ -113,6 +119,13 @@ func init() {
 
 func cmdRun(cmd *cobra.Command, args []string) (err error) {
 	ctx := process.Ctx(cmd)
+	transport.Configure(runCfg.Transport)
+
+	identity, err := runCfg.Identity.Load()
+	if err != nil {
+		return err
+	}
+	process.SetNodeID(cmd, identity.ID)
 
 	database, err := satellitedb.New(runCfg.Database)
 	if err != nil {
@@ -131,13 +144,15 @@ func cmdRun(cmd *cobra.Command, args []string) (err error) {
 		ctx,
 		grpcauth.NewAPIKeyInterceptor(),
 		runCfg.Kademlia,
-		runCfg.Overlay,
+		&runCfg.Overlay,
 		runCfg.PointerDB,
-		runCfg.Checker,
-		runCfg.Repairer,
-		runCfg.Audit,
+		&runCfg.Checker,
+		&runCfg.Repairer,
+		&runCfg.Audit,
 		runCfg.BwAgreement,
 		runCfg.Discovery,
+		runCfg.Expiration,
+		&runCfg.Uptime,
 	)
 }
 
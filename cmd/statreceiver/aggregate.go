@@ -0,0 +1,257 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"storj.io/storj/pkg/utils"
+)
+
+// aggregateAllInstances is the instance of the metrics aggregated across the
+// instances of an application
+const aggregateAllInstances = "all"
+
+// Aggregator is a MetricDest that aggregates the values of every key over
+// time windows. When a window closes, the aggregates are sent to m with the
+// name of the statistic appended to the key, e.g. key.max or key.p99.
+//
+// A window closes when a value of a later window arrives, or when no value
+// arrived for the length of the window.
+type Aggregator struct {
+	mtx     sync.Mutex
+	window  time.Duration
+	stats   []string
+	group   bool
+	m       MetricDest
+	windows map[aggregateKey]*aggregateWindow
+	stopped bool
+}
+
+type aggregateKey struct {
+	application, instance, key string
+}
+
+type aggregateWindow struct {
+	start   time.Time
+	updated time.Time
+	values  []float64
+	// first and last are the first and last values of every instance in
+	// the window, for the rate
+	first, last map[string]aggregateSample
+}
+
+type aggregateSample struct {
+	val float64
+	ts  time.Time
+}
+
+// NewAggregator creates an Aggregator of the metrics of every instance.
+// window is the length of the windows, like "1m". stats is a comma-separated
+// list of the statistics to send: min, max, mean, sum, count, rate (the
+// change per second) and percentiles like p50 or p99.9. Use Close to stop
+// the goroutine closing the idle windows.
+func NewAggregator(window, stats string, m MetricDest) *Aggregator {
+	return newAggregator(window, stats, false, m)
+}
+
+// NewApplicationAggregator creates an Aggregator like NewAggregator, except
+// that the metrics of all the instances of an application are aggregated
+// together. The aggregates are sent with the instance "all".
+func NewApplicationAggregator(window, stats string, m MetricDest) *Aggregator {
+	return newAggregator(window, stats, true, m)
+}
+
+func newAggregator(window, stats string, group bool, m MetricDest) *Aggregator {
+	duration, err := time.ParseDuration(window)
+	if err != nil || duration <= 0 {
+		panic(fmt.Sprintf("invalid aggregation window %q", window))
+	}
+
+	var statList []string
+	for _, stat := range strings.Split(stats, ",") {
+		stat = strings.TrimSpace(stat)
+		if _, ok := percentile(stat); !ok {
+			switch stat {
+			case "min", "max", "mean", "sum", "count", "rate":
+			default:
+				panic(fmt.Sprintf("unknown aggregation %q", stat))
+			}
+		}
+		statList = append(statList, stat)
+	}
+
+	rv := &Aggregator{
+		window:  duration,
+		stats:   statList,
+		group:   group,
+		m:       m,
+		windows: map[aggregateKey]*aggregateWindow{},
+	}
+	go rv.closeIdle()
+	return rv
+}
+
+// Metric implements MetricDest
+func (a *Aggregator) Metric(application, instance string,
+	key []byte, val float64, ts time.Time) error {
+	k := aggregateKey{application: application, instance: instance, key: string(key)}
+	if a.group {
+		k.instance = aggregateAllInstances
+	}
+	start := ts.Truncate(a.window)
+
+	a.mtx.Lock()
+	w, ok := a.windows[k]
+	var closed *aggregateWindow
+	if ok && start.After(w.start) {
+		closed, ok = w, false
+	}
+	if !ok {
+		w = &aggregateWindow{
+			start: start,
+			first: map[string]aggregateSample{},
+			last:  map[string]aggregateSample{},
+		}
+		a.windows[k] = w
+	}
+	// late values of the windows already closed count towards the current one
+	w.add(instance, val, ts)
+	a.mtx.Unlock()
+
+	if closed != nil {
+		return a.send(k, closed)
+	}
+	return nil
+}
+
+// Close stops the goroutine closing the idle windows
+func (a *Aggregator) Close() error {
+	a.mtx.Lock()
+	a.stopped = true
+	a.mtx.Unlock()
+	return nil
+}
+
+func (a *Aggregator) closeIdle() {
+	for {
+		time.Sleep(a.window / 2)
+
+		idle := time.Now().Add(-a.window)
+		closed := map[aggregateKey]*aggregateWindow{}
+
+		a.mtx.Lock()
+		if a.stopped {
+			a.mtx.Unlock()
+			return
+		}
+		for k, w := range a.windows {
+			if w.updated.Before(idle) {
+				closed[k] = w
+				delete(a.windows, k)
+			}
+		}
+		a.mtx.Unlock()
+
+		for k, w := range closed {
+			if err := a.send(k, w); err != nil {
+				log.Printf("failed sending aggregates: %v", err)
+			}
+		}
+	}
+}
+
+// send sends the aggregates of a closed window
+func (a *Aggregator) send(k aggregateKey, w *aggregateWindow) error {
+	sort.Float64s(w.values)
+	end := w.start.Add(a.window)
+
+	var errs utils.ErrorGroup
+	for _, stat := range a.stats {
+		val := w.aggregate(stat)
+		if math.IsNaN(val) {
+			continue
+		}
+		errs.Add(a.m.Metric(k.application, k.instance, []byte(k.key+"."+stat), val, end))
+	}
+	return errs.Finish()
+}
+
+func (w *aggregateWindow) add(instance string, val float64, ts time.Time) {
+	w.values = append(w.values, val)
+	w.updated = time.Now()
+
+	if first, ok := w.first[instance]; !ok || ts.Before(first.ts) {
+		w.first[instance] = aggregateSample{val: val, ts: ts}
+	}
+	if last, ok := w.last[instance]; !ok || !ts.Before(last.ts) {
+		w.last[instance] = aggregateSample{val: val, ts: ts}
+	}
+}
+
+// aggregate computes a statistic of the sorted values. It returns NaN if the
+// statistic is undefined.
+func (w *aggregateWindow) aggregate(stat string) float64 {
+	n := len(w.values)
+	switch stat {
+	case "count":
+		return float64(n)
+	case "min":
+		return w.values[0]
+	case "max":
+		return w.values[n-1]
+	case "sum", "mean":
+		var sum float64
+		for _, val := range w.values {
+			sum += val
+		}
+		if stat == "mean" {
+			return sum / float64(n)
+		}
+		return sum
+	case "rate":
+		// the rates of the instances add up
+		var rate float64
+		var defined bool
+		for instance, first := range w.first {
+			last := w.last[instance]
+			seconds := last.ts.Sub(first.ts).Seconds()
+			if seconds > 0 {
+				rate += (last.val - first.val) / seconds
+				defined = true
+			}
+		}
+		if !defined {
+			return math.NaN()
+		}
+		return rate
+	}
+
+	p, _ := percentile(stat)
+	// nearest rank
+	rank := int(math.Ceil(p / 100 * float64(n)))
+	if rank < 1 {
+		rank = 1
+	}
+	return w.values[rank-1]
+}
+
+// percentile parses a percentile statistic like p99.9
+func percentile(stat string) (float64, bool) {
+	if !strings.HasPrefix(stat, "p") {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(stat[1:], 64)
+	if err != nil || p < 0 || p > 100 {
+		return 0, false
+	}
+	return p, true
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingDest records the metrics sent to it
+type recordingDest struct {
+	mtx     sync.Mutex
+	metrics map[string]float64
+	times   map[string]time.Time
+}
+
+func newRecordingDest() *recordingDest {
+	return &recordingDest{metrics: map[string]float64{}, times: map[string]time.Time{}}
+}
+
+func (d *recordingDest) Metric(application, instance string, key []byte, val float64, ts time.Time) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	name := fmt.Sprintf("%s %s %s", application, instance, key)
+	d.metrics[name] = val
+	d.times[name] = ts
+	return nil
+}
+
+func TestAggregateWindow(t *testing.T) {
+	start := time.Unix(1000, 0)
+	w := &aggregateWindow{first: map[string]aggregateSample{}, last: map[string]aggregateSample{}}
+	for i, val := range []float64{4, 1, 3, 2, 10} {
+		w.add("a", val, start.Add(time.Duration(i)*time.Second))
+	}
+	// the rates of the instances add up
+	w.add("b", 100, start)
+	w.add("b", 120, start.Add(2*time.Second))
+	sort.Float64s(w.values)
+
+	for _, tt := range []struct {
+		stat     string
+		expected float64
+	}{
+		{"count", 7},
+		{"min", 1},
+		{"max", 120},
+		{"sum", 240},
+		{"mean", 240.0 / 7},
+		// (10-4)/4s + (120-100)/2s
+		{"rate", 11.5},
+		{"p0", 1},
+		{"p50", 4},
+		{"p99", 120},
+		{"p100", 120},
+	} {
+		assert.Equal(t, tt.expected, w.aggregate(tt.stat), tt.stat)
+	}
+
+	// the rate needs values at different times
+	single := &aggregateWindow{first: map[string]aggregateSample{}, last: map[string]aggregateSample{}}
+	single.add("a", 1, start)
+	assert.True(t, math.IsNaN(single.aggregate("rate")))
+}
+
+func TestAggregatorRollover(t *testing.T) {
+	dest := newRecordingDest()
+	// the idle windows aren't closed within the test
+	aggregator := NewAggregator("1h", "count,max,rate", dest)
+	defer func() { _ = aggregator.Close() }()
+
+	start := time.Date(2018, 12, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, aggregator.Metric("app", "a", []byte("key"), 1, start))
+	require.NoError(t, aggregator.Metric("app", "a", []byte("key"), 5, start.Add(30*time.Minute)))
+	require.NoError(t, aggregator.Metric("app", "b", []byte("key"), 7, start.Add(10*time.Minute)))
+	assert.Empty(t, dest.metrics)
+
+	// a value of a later window closes the window of its series only
+	require.NoError(t, aggregator.Metric("app", "a", []byte("key"), 2, start.Add(90*time.Minute)))
+	end := start.Add(time.Hour)
+	assert.Equal(t, map[string]float64{
+		"app a key.count": 2,
+		"app a key.max":   5,
+		"app a key.rate":  4.0 / (30 * 60),
+	}, dest.metrics)
+	assert.Equal(t, end, dest.times["app a key.max"])
+
+	// the late values count towards the current window
+	require.NoError(t, aggregator.Metric("app", "a", []byte("key"), 100, start.Add(20*time.Minute)))
+	require.NoError(t, aggregator.Metric("app", "a", []byte("key"), 3, start.Add(150*time.Minute)))
+	assert.Equal(t, float64(2), dest.metrics["app a key.count"])
+	assert.Equal(t, float64(100), dest.metrics["app a key.max"])
+	assert.Equal(t, end.Add(time.Hour), dest.times["app a key.max"])
+}
+
+func TestApplicationAggregator(t *testing.T) {
+	dest := newRecordingDest()
+	aggregator := NewApplicationAggregator("1m", "sum", dest)
+	defer func() { _ = aggregator.Close() }()
+
+	start := time.Date(2018, 12, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, aggregator.Metric("app", "a", []byte("key"), 1, start))
+	require.NoError(t, aggregator.Metric("app", "b", []byte("key"), 2, start))
+	require.NoError(t, aggregator.Metric("app", "a", []byte("key"), 4, start.Add(time.Minute)))
+
+	// the instances are aggregated together
+	assert.Equal(t, map[string]float64{"app all key.sum": 3}, dest.metrics)
+
+	assert.Panics(t, func() { NewAggregator("1m", "median", dest) })
+	assert.Panics(t, func() { NewAggregator("0s", "sum", dest) })
+}
@@ -0,0 +1,364 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Alert is a change of the state of an alerting rule for a metric
+type Alert struct {
+	Rule        string    `json:"rule"`
+	Firing      bool      `json:"firing"`
+	Application string    `json:"application"`
+	Instance    string    `json:"instance"`
+	Key         string    `json:"key"`
+	Value       float64   `json:"value"`
+	Time        time.Time `json:"time"`
+}
+
+func (a Alert) String() string {
+	state := "resolved"
+	if a.Firing {
+		state = "firing"
+	}
+	return fmt.Sprintf("%s %s: %s on %s %s %s = %v", a.Time.Format(time.RFC3339),
+		state, a.Rule, a.Application, a.Instance, a.Key, a.Value)
+}
+
+// AlertSink handles alerts. The alerting rules call the sinks with their
+// state locked, so the sinks have to be quick.
+type AlertSink interface {
+	Alert(alert Alert) error
+}
+
+type alertSeries struct {
+	application, instance, key string
+}
+
+// ThresholdAlert is a MetricDest that alerts the sink when the value of a
+// metric with a key matching a regular expression crosses a threshold, and
+// again when the value is back within the threshold. A failed alert is sent
+// again with the next value.
+type ThresholdAlert struct {
+	mtx       sync.Mutex
+	re        *regexp.Regexp
+	op        string
+	threshold float64
+	sink      AlertSink
+	firing    map[alertSeries]bool
+}
+
+// NewThresholdAlert creates a ThresholdAlert. op is one of >, >=, <, <=, ==
+// or != comparing the value to threshold, which alerts when true.
+func NewThresholdAlert(regex, op string, threshold float64, sink AlertSink) *ThresholdAlert {
+	switch op {
+	case ">", ">=", "<", "<=", "==", "!=":
+	default:
+		panic(fmt.Sprintf("unknown comparison %q", op))
+	}
+	return &ThresholdAlert{
+		re:        regexp.MustCompile(regex),
+		op:        op,
+		threshold: threshold,
+		sink:      sink,
+		firing:    map[alertSeries]bool{},
+	}
+}
+
+// Metric implements MetricDest
+func (t *ThresholdAlert) Metric(application, instance string,
+	key []byte, val float64, ts time.Time) error {
+	if !t.re.Match(key) {
+		return nil
+	}
+
+	var crossed bool
+	switch t.op {
+	case ">":
+		crossed = val > t.threshold
+	case ">=":
+		crossed = val >= t.threshold
+	case "<":
+		crossed = val < t.threshold
+	case "<=":
+		crossed = val <= t.threshold
+	case "==":
+		crossed = val == t.threshold
+	case "!=":
+		crossed = val != t.threshold
+	}
+
+	series := alertSeries{application: application, instance: instance, key: string(key)}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.firing[series] == crossed {
+		return nil
+	}
+	err := t.sink.Alert(Alert{
+		Rule:        fmt.Sprintf("%s %s %v", t.re, t.op, t.threshold),
+		Firing:      crossed,
+		Application: application,
+		Instance:    instance,
+		Key:         string(key),
+		Value:       val,
+		Time:        ts,
+	})
+	if err != nil {
+		return err
+	}
+
+	if crossed {
+		t.firing[series] = true
+	} else {
+		delete(t.firing, series)
+	}
+	return nil
+}
+
+// AbsenceAlert is a MetricDest that alerts the sink when no value of a
+// metric with a key matching a regular expression was received for a while,
+// and again when a value arrives. Only the metrics received at least once
+// are watched. A failed alert is sent again with the next check or value.
+type AbsenceAlert struct {
+	mtx      sync.Mutex
+	re       *regexp.Regexp
+	timeout  time.Duration
+	sink     AlertSink
+	lastSeen map[alertSeries]Alert
+	firing   map[alertSeries]bool
+	stopped  bool
+}
+
+// NewAbsenceAlert creates an AbsenceAlert alerting after timeout, like "5m".
+// Use Close to stop watching the metrics.
+func NewAbsenceAlert(regex, timeout string, sink AlertSink) *AbsenceAlert {
+	duration, err := time.ParseDuration(timeout)
+	if err != nil || duration <= 0 {
+		panic(fmt.Sprintf("invalid absence timeout %q", timeout))
+	}
+	rv := &AbsenceAlert{
+		re:       regexp.MustCompile(regex),
+		timeout:  duration,
+		sink:     sink,
+		lastSeen: map[alertSeries]Alert{},
+		firing:   map[alertSeries]bool{},
+	}
+	go rv.watch()
+	return rv
+}
+
+// Metric implements MetricDest
+func (a *AbsenceAlert) Metric(application, instance string,
+	key []byte, val float64, ts time.Time) error {
+	if !a.re.Match(key) {
+		return nil
+	}
+
+	series := alertSeries{application: application, instance: instance, key: string(key)}
+	alert := Alert{
+		Rule:        a.rule(),
+		Application: application,
+		Instance:    instance,
+		Key:         string(key),
+		Value:       val,
+		// the time the value was received, as the absence is checked
+		// against the clock
+		Time: time.Now(),
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.lastSeen[series] = alert
+	if !a.firing[series] {
+		return nil
+	}
+	if err := a.sink.Alert(alert); err != nil {
+		return err
+	}
+	delete(a.firing, series)
+	return nil
+}
+
+// Close stops watching the metrics
+func (a *AbsenceAlert) Close() error {
+	a.mtx.Lock()
+	a.stopped = true
+	a.mtx.Unlock()
+	return nil
+}
+
+func (a *AbsenceAlert) rule() string {
+	return fmt.Sprintf("%s absent for %v", a.re, a.timeout)
+}
+
+func (a *AbsenceAlert) watch() {
+	for {
+		time.Sleep(a.timeout / 4)
+		if !a.check(time.Now()) {
+			return
+		}
+	}
+}
+
+// check alerts about the metrics absent at now. It returns false once the
+// alert is closed.
+func (a *AbsenceAlert) check(now time.Time) bool {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.stopped {
+		return false
+	}
+	for series, last := range a.lastSeen {
+		if a.firing[series] || now.Sub(last.Time) < a.timeout {
+			continue
+		}
+		alert := last
+		alert.Firing = true
+		alert.Time = now
+		if err := a.sink.Alert(alert); err != nil {
+			log.Printf("failed sending alert: %v", err)
+			continue
+		}
+		a.firing[series] = true
+	}
+	return true
+}
+
+// webhookQueueSize is the number of alerts waiting to be posted
+const webhookQueueSize = 64
+
+// WebhookSink is an AlertSink that posts the alerts as JSON to a URL. The
+// alerts are queued and posted in the background, so that a slow webhook
+// doesn't hold up the metrics.
+type WebhookSink struct {
+	mtx     sync.Mutex
+	url     string
+	client  *http.Client
+	alerts  chan Alert
+	stopped bool
+}
+
+// NewWebhookSink creates a WebhookSink posting to url. Use Close to stop
+// posting.
+func NewWebhookSink(url string) *WebhookSink {
+	rv := &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		alerts: make(chan Alert, webhookQueueSize),
+	}
+	go rv.post()
+	return rv
+}
+
+// Alert implements AlertSink. It fails if the queue is full.
+func (w *WebhookSink) Alert(alert Alert) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.stopped {
+		return fmt.Errorf("webhook sink closed")
+	}
+	select {
+	case w.alerts <- alert:
+		return nil
+	default:
+		return fmt.Errorf("webhook %s queue full", w.url)
+	}
+}
+
+// Close stops posting the alerts once the queued ones are posted
+func (w *WebhookSink) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if !w.stopped {
+		w.stopped = true
+		close(w.alerts)
+	}
+	return nil
+}
+
+func (w *WebhookSink) post() {
+	for alert := range w.alerts {
+		if err := w.send(alert); err != nil {
+			log.Printf("failed posting alert: %v", err)
+		}
+	}
+}
+
+// send posts a single alert
+func (w *WebhookSink) send(alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if err := resp.Body.Close(); err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded %s", w.url, resp.Status)
+	}
+	return nil
+}
+
+// FileSink is an AlertSink that appends the alerts as JSON lines to a file
+type FileSink struct {
+	mtx  sync.Mutex
+	path string
+}
+
+// NewFileSink creates a FileSink appending to the file at path
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Alert implements AlertSink
+func (f *FileSink) Alert(alert Alert) error {
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	fh, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = fh.Write(append(line, '\n'))
+	if closeErr := fh.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// PrintSink is an AlertSink that prints the alerts to stdout
+type PrintSink struct{}
+
+// NewPrintSink creates a PrintSink
+func NewPrintSink() *PrintSink { return &PrintSink{} }
+
+// Alert implements AlertSink
+func (PrintSink) Alert(alert Alert) error {
+	_, err := fmt.Println(alert.String())
+	return err
+}
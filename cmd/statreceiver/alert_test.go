@@ -0,0 +1,178 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink records the alerts, or fails while fail is set
+type recordingSink struct {
+	alerts []Alert
+	fail   bool
+}
+
+func (s *recordingSink) Alert(alert Alert) error {
+	if s.fail {
+		return errors.New("sink failed")
+	}
+	s.alerts = append(s.alerts, alert)
+	return nil
+}
+
+// firing returns the firing states of the recorded alerts
+func (s *recordingSink) firing() []bool {
+	var states []bool
+	for _, alert := range s.alerts {
+		states = append(states, alert.Firing)
+	}
+	return states
+}
+
+func TestThresholdAlert(t *testing.T) {
+	sink := &recordingSink{}
+	alert := NewThresholdAlert(`^errors$`, ">", 10, sink)
+	ts := time.Unix(100, 0)
+
+	metric := func(key string, val float64) error {
+		return alert.Metric("app", "inst", []byte(key), val, ts)
+	}
+
+	require.NoError(t, metric("errors", 5))
+	require.NoError(t, metric("other", 50))
+	assert.Empty(t, sink.alerts)
+
+	// crossing the threshold fires once
+	require.NoError(t, metric("errors", 11))
+	require.NoError(t, metric("errors", 12))
+	require.Len(t, sink.alerts, 1)
+	assert.Equal(t, Alert{
+		Rule:        "^errors$ > 10",
+		Firing:      true,
+		Application: "app",
+		Instance:    "inst",
+		Key:         "errors",
+		Value:       11,
+		Time:        ts,
+	}, sink.alerts[0])
+
+	// a failed resolution is sent again with the next value
+	sink.fail = true
+	assert.Error(t, metric("errors", 10))
+	sink.fail = false
+	require.NoError(t, metric("errors", 9))
+	require.NoError(t, metric("errors", 8))
+	assert.Equal(t, []bool{true, false}, sink.firing())
+	assert.Equal(t, float64(9), sink.alerts[1].Value)
+
+	// the series are independent
+	require.NoError(t, alert.Metric("app", "other", []byte("errors"), 20, ts))
+	require.NoError(t, metric("errors", 20))
+	assert.Equal(t, []bool{true, false, true, true}, sink.firing())
+}
+
+func TestThresholdAlertComparisons(t *testing.T) {
+	for i, tt := range []struct {
+		op     string
+		val    float64
+		firing bool
+	}{
+		{">", 1, false}, {">", 2, true},
+		{">=", 0, false}, {">=", 1, true},
+		{"<", 1, false}, {"<", 0, true},
+		{"<=", 2, false}, {"<=", 1, true},
+		{"==", 2, false}, {"==", 1, true},
+		{"!=", 1, false}, {"!=", 2, true},
+	} {
+		sink := &recordingSink{}
+		alert := NewThresholdAlert(`.`, tt.op, 1, sink)
+		require.NoError(t, alert.Metric("app", "inst", []byte("key"), tt.val, time.Now()))
+		assert.Equal(t, tt.firing, len(sink.alerts) == 1, "Test case #%d", i)
+	}
+
+	assert.Panics(t, func() { NewThresholdAlert(`.`, "=>", 1, &recordingSink{}) })
+}
+
+func TestAbsenceAlert(t *testing.T) {
+	sink := &recordingSink{}
+	// the watching goroutine doesn't check within the test
+	alert := NewAbsenceAlert(`^uptime$`, "1h", sink)
+	defer func() { _ = alert.Close() }()
+
+	metric := func(key string) error {
+		return alert.Metric("app", "inst", []byte(key), 1, time.Unix(0, 0))
+	}
+
+	// only the metrics received are watched
+	require.NoError(t, metric("other"))
+	require.True(t, alert.check(time.Now().Add(2*time.Hour)))
+	assert.Empty(t, sink.alerts)
+
+	require.NoError(t, metric("uptime"))
+	require.True(t, alert.check(time.Now().Add(time.Minute)))
+	assert.Empty(t, sink.alerts)
+
+	// a failed alert is sent again with the next check
+	sink.fail = true
+	require.True(t, alert.check(time.Now().Add(2*time.Hour)))
+	sink.fail = false
+	absent := time.Now().Add(2 * time.Hour)
+	require.True(t, alert.check(absent))
+	require.True(t, alert.check(absent.Add(time.Hour)))
+	require.Len(t, sink.alerts, 1)
+	assert.True(t, sink.alerts[0].Firing)
+	assert.Equal(t, "^uptime$ absent for 1h0m0s", sink.alerts[0].Rule)
+	assert.Equal(t, absent, sink.alerts[0].Time)
+
+	// a failed resolution is sent again with the next value
+	sink.fail = true
+	assert.Error(t, metric("uptime"))
+	sink.fail = false
+	require.NoError(t, metric("uptime"))
+	require.NoError(t, metric("uptime"))
+	assert.Equal(t, []bool{true, false}, sink.firing())
+
+	// closing stops the checks
+	require.NoError(t, alert.Close())
+	assert.False(t, alert.check(time.Now()))
+}
+
+func TestWebhookSink(t *testing.T) {
+	received := make(chan Alert, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert Alert
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		received <- alert
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL)
+	alert := Alert{Rule: "rule", Firing: true, Key: "key", Value: 1, Time: time.Unix(100, 0).UTC()}
+	require.NoError(t, sink.Alert(alert))
+
+	select {
+	case posted := <-received:
+		assert.Equal(t, alert, posted)
+	case <-time.After(5 * time.Second):
+		t.Fatal("alert not posted")
+	}
+
+	require.NoError(t, sink.Close())
+	assert.Error(t, sink.Alert(alert))
+}
+
+func TestWebhookSinkQueueFull(t *testing.T) {
+	// without the posting goroutine the queue fills up
+	sink := &WebhookSink{url: "http://localhost", alerts: make(chan Alert, 1)}
+	require.NoError(t, sink.Alert(Alert{}))
+	assert.Error(t, sink.Alert(Alert{}))
+}
@@ -10,6 +10,18 @@ source = udpin("localhost:9000")
 --  * print() goes to stdout
 --  * db("sqlite3", path) goes to sqlite
 --  * db("postgres", connstring) goes to postgres
+--  * prometheus(address) serves the latest values on http://address/metrics
+--  * influx(url) goes to influxdb with the line protocol, where url is
+--    http://host:8086/write?db=database or udp://host:8089
+--
+-- metric stages
+--  * aggregate(window, stats, dest) sends the min, max, mean, sum, count, rate
+--    or percentiles like p99 of every key and instance over time windows
+--  * appaggregate(window, stats, dest) aggregates over all the instances of
+--    an application
+--  * threshold(keyregex, op, value, sink) alerts when a value crosses value
+--  * absence(keyregex, timeout, sink) alerts when a metric stops arriving
+-- alert sinks are webhook(url), alertfile(path) and alertprint()
 graphite_out = graphite("localhost:5555")
 db_out = mcopy(
   db("sqlite3", "db.db"),
@@ -27,6 +39,21 @@ metric_handlers = mcopy(
         "|hw\\.disk\\..*Avail" ..
         "|hw\\.network\\.stats\\..*\\.(tx|rx)_bytes\\.(deriv|val)",
       db_out)),
+  -- expose storagenode data to prometheus and send it to influxdb
+  appfilter("storagenode-prod",
+    mcopy(
+      prometheus("localhost:9090"),
+      influx("http://localhost:8086/write?db=storj"))),
+  -- aggregate the satellite request latencies over all the instances and
+  -- page the on-call when the p99 gets too high or the satellites go silent
+  appfilter("satellite-prod",
+    mcopy(
+      appaggregate("1m", "mean,p50,p99,max",
+        keyfilter("\\.success_times_recent$",
+          mcopy(
+            graphite("localhost:5555"),
+            threshold("\\.p99$", ">", 5, webhook("http://localhost:8080/page"))))),
+      absence("env\\.process\\.control", "5m", alertfile("alerts.log")))),
   -- just print uplink stuff
   appfilter("uplink-prod",
     print()))
@@ -0,0 +1,242 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// influxBatchSize is the number of lines sent in a single write
+	influxBatchSize = 5000
+	// influxFlushInterval is how often the partial batches are sent
+	influxFlushInterval = 5 * time.Second
+	// influxRetries is how many times a failed batch is retried
+	influxRetries = 3
+	// influxDatagramSize is the maximum size of the datagrams sent over UDP
+	influxDatagramSize = 1400
+)
+
+var (
+	influxMeasurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	influxTagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
+
+// InfluxDest is a MetricDest that writes metrics with the InfluxDB line
+// protocol, either over HTTP or UDP. The metric key is the measurement, with
+// the application and instance as tags and the value in the value field.
+// Metrics are sent in batches, and failed batches are retried a few times
+// before they are dropped. The batches are dropped as well when the sender
+// falls behind, rather than blocking the metrics.
+type InfluxDest struct {
+	mtx     sync.Mutex
+	address *url.URL
+	buf     bytes.Buffer
+	lines   int
+	batches chan []byte
+	dropped int
+	stopped bool
+	done    chan struct{}
+
+	client *http.Client
+	conn   net.Conn
+}
+
+// NewInfluxDest creates an InfluxDest writing to address, which is either
+// http(s)://host:port/write?db=database or udp://host:port. Like graphite,
+// the destination is sending the batches in the background, use Close to
+// stop it.
+func NewInfluxDest(address string) *InfluxDest {
+	u, err := url.Parse(address)
+	if err != nil {
+		panic(fmt.Sprintf("invalid influx address %q: %v", address, err))
+	}
+	switch u.Scheme {
+	case "http", "https":
+		if u.Path == "" {
+			u.Path = "/write"
+		}
+	case "udp":
+	default:
+		panic(fmt.Sprintf("influx scheme %q not supported", u.Scheme))
+	}
+
+	rv := &InfluxDest{
+		address: u,
+		batches: make(chan []byte, 16),
+		done:    make(chan struct{}),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+	go rv.flush()
+	go rv.send()
+	return rv
+}
+
+// Metric implements MetricDest
+func (d *InfluxDest) Metric(application, instance string,
+	key []byte, val float64, ts time.Time) error {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		// the line protocol can't represent these
+		return nil
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if d.stopped {
+		return fmt.Errorf("influx destination closed")
+	}
+
+	_, err := fmt.Fprintf(&d.buf, "%s,application=%s,instance=%s value=%s %d\n",
+		influxMeasurementEscaper.Replace(string(key)),
+		influxTagEscaper.Replace(application), influxTagEscaper.Replace(instance),
+		strconv.FormatFloat(val, 'g', -1, 64), ts.UnixNano())
+	if err != nil {
+		return err
+	}
+
+	d.lines++
+	if d.lines >= influxBatchSize {
+		d.queue()
+	}
+	return nil
+}
+
+// queue hands the buffered lines over to the sender, or drops them if the
+// sender is too far behind. d.mtx must be held.
+func (d *InfluxDest) queue() {
+	if d.lines == 0 {
+		return
+	}
+	batch := append([]byte(nil), d.buf.Bytes()...)
+	lines := d.lines
+	d.buf.Reset()
+	d.lines = 0
+
+	select {
+	case d.batches <- batch:
+	default:
+		d.dropped++
+		log.Printf("influx sender falling behind, dropped a batch of %d lines (%d batches dropped)",
+			lines, d.dropped)
+	}
+}
+
+// Close sends the buffered metrics and waits for the sender to finish
+func (d *InfluxDest) Close() error {
+	d.mtx.Lock()
+	if d.stopped {
+		d.mtx.Unlock()
+		return nil
+	}
+	d.queue()
+	d.stopped = true
+	close(d.batches)
+	d.mtx.Unlock()
+
+	<-d.done
+	return nil
+}
+
+func (d *InfluxDest) flush() {
+	for {
+		time.Sleep(influxFlushInterval)
+		d.mtx.Lock()
+		if d.stopped {
+			d.mtx.Unlock()
+			return
+		}
+		d.queue()
+		d.mtx.Unlock()
+	}
+}
+
+func (d *InfluxDest) send() {
+	defer close(d.done)
+	for batch := range d.batches {
+		var err error
+		for attempt := 0; attempt <= influxRetries; attempt++ {
+			if attempt > 0 {
+				time.Sleep(time.Duration(1<<uint(attempt-1)) * time.Second)
+			}
+			var retry bool
+			retry, err = d.write(batch)
+			if err == nil || !retry {
+				break
+			}
+		}
+		if err != nil {
+			log.Printf("failed sending influx batch: %v", err)
+		}
+	}
+	if d.conn != nil {
+		if err := d.conn.Close(); err != nil {
+			log.Printf("failed closing influx connection: %v", err)
+		}
+	}
+}
+
+// write writes a batch of lines and reports whether a failure is worth
+// retrying
+func (d *InfluxDest) write(batch []byte) (retry bool, err error) {
+	if d.address.Scheme == "udp" {
+		return true, d.writeUDP(batch)
+	}
+
+	resp, err := d.client.Post(d.address.String(), "text/plain", bytes.NewReader(batch))
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusOK {
+		return false, nil
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("influx responded %s: %s", resp.Status, bytes.TrimSpace(body))
+	// the malformed batches are rejected again on retries
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests, err
+}
+
+// writeUDP writes the batch in datagrams containing whole lines
+func (d *InfluxDest) writeUDP(batch []byte) error {
+	if d.conn == nil {
+		conn, err := net.Dial("udp", d.address.Host)
+		if err != nil {
+			return err
+		}
+		d.conn = conn
+	}
+
+	for len(batch) > 0 {
+		size := len(batch)
+		if size > influxDatagramSize {
+			size = bytes.LastIndexByte(batch[:influxDatagramSize], '\n') + 1
+			if size == 0 {
+				// a single line larger than a datagram
+				size = bytes.IndexByte(batch, '\n') + 1
+			}
+		}
+
+		_, err := d.conn.Write(batch[:size])
+		if err != nil {
+			return err
+		}
+		batch = batch[size:]
+	}
+	return nil
+}
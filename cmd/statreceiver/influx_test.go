@@ -0,0 +1,112 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfluxHTTP(t *testing.T) {
+	var mu sync.Mutex
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/write", r.URL.Path)
+		assert.Equal(t, "db=stats", r.URL.RawQuery)
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		mu.Lock()
+		body = append(body, data...)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dest := NewInfluxDest(server.URL + "?db=stats")
+	ts := time.Unix(10, 5)
+
+	// the measurements and the tags are escaped
+	require.NoError(t, dest.Metric("app", "inst", []byte("a.b"), 1.5, ts))
+	require.NoError(t, dest.Metric("my app", "x=1,y", []byte("a b,c=d"), 2, ts))
+	// the values the line protocol can't represent are skipped
+	require.NoError(t, dest.Metric("app", "inst", []byte("nan"), math.NaN(), ts))
+	require.NoError(t, dest.Metric("app", "inst", []byte("inf"), math.Inf(1), ts))
+
+	// closing sends the last batch
+	require.NoError(t, dest.Close())
+	require.NoError(t, dest.Close())
+	assert.Error(t, dest.Metric("app", "inst", []byte("a.b"), 1, ts))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, ""+
+		"a.b,application=app,instance=inst value=1.5 10000000005\n"+
+		`a\ b\,c=d,application=my\ app,instance=x\=1\,y value=2 10000000005`+"\n",
+		string(body))
+}
+
+func TestInfluxUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	dest := NewInfluxDest("udp://" + conn.LocalAddr().String())
+
+	var expected bytes.Buffer
+	ts := time.Unix(1, 0)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("metric%d", i)
+		require.NoError(t, dest.Metric("app", "inst", []byte(key), float64(i), ts))
+		_, _ = fmt.Fprintf(&expected, "%s,application=app,instance=inst value=%d 1000000000\n", key, i)
+	}
+	// a line larger than a datagram is sent on its own
+	long := strings.Repeat("x", influxDatagramSize)
+	require.NoError(t, dest.Metric("app", "inst", []byte(long), 1, ts))
+	_, _ = fmt.Fprintf(&expected, "%s,application=app,instance=inst value=1 1000000000\n", long)
+	require.NoError(t, dest.Close())
+
+	// the datagrams contain whole lines
+	var received []byte
+	buf := make([]byte, 2*influxDatagramSize)
+	for len(received) < expected.Len() {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+
+		datagram := buf[:n]
+		if !bytes.Contains(datagram, []byte(long)) {
+			assert.True(t, n <= influxDatagramSize, "datagram of %d bytes", n)
+		}
+		assert.Equal(t, byte('\n'), datagram[n-1])
+		received = append(received, datagram...)
+	}
+	assert.Equal(t, expected.String(), string(received))
+}
+
+func TestInfluxQueueOverflow(t *testing.T) {
+	// without a sender, the batches after the first one are dropped
+	dest := &InfluxDest{batches: make(chan []byte, 1)}
+	for i := 0; i < 3; i++ {
+		_, _ = fmt.Fprintf(&dest.buf, "line%d\n", i)
+		dest.lines++
+		dest.queue()
+	}
+
+	assert.Equal(t, 2, dest.dropped)
+	assert.Equal(t, "line0\n", string(<-dest.batches))
+	assert.Equal(t, 0, dest.buf.Len())
+	assert.Equal(t, 0, dest.lines)
+}
@@ -73,6 +73,15 @@ func Main(cmd *cobra.Command, args []string) error {
 		s.RegisterVal("sanitize", NewSanitizer),
 		s.RegisterVal("graphite", NewGraphiteDest),
 		s.RegisterVal("db", NewDBDest),
+		s.RegisterVal("prometheus", NewPrometheusDest),
+		s.RegisterVal("influx", NewInfluxDest),
+		s.RegisterVal("aggregate", NewAggregator),
+		s.RegisterVal("appaggregate", NewApplicationAggregator),
+		s.RegisterVal("threshold", NewThresholdAlert),
+		s.RegisterVal("absence", NewAbsenceAlert),
+		s.RegisterVal("webhook", NewWebhookSink),
+		s.RegisterVal("alertfile", NewFileSink),
+		s.RegisterVal("alertprint", NewPrintSink),
 		s.RegisterVal("pbufprep", NewPacketBufPrep),
 		s.RegisterVal("mbufprep", NewMetricBufPrep),
 	)
@@ -0,0 +1,160 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// prometheusStaleness is how long a metric is exposed after its last value
+// was received
+const prometheusStaleness = 10 * time.Minute
+
+// PrometheusDest is a MetricDest that keeps the latest value of every metric
+// and exposes them to Prometheus scrapes in the text exposition format. The
+// application and instance of a metric are exposed as labels.
+type PrometheusDest struct {
+	mtx    sync.Mutex
+	values map[prometheusSeries]prometheusValue
+	server *http.Server
+}
+
+type prometheusSeries struct {
+	name, application, instance string
+}
+
+type prometheusValue struct {
+	val float64
+	ts  time.Time
+}
+
+// NewPrometheusDest creates a PrometheusDest serving the scrapes on
+// http://address/metrics. Use Close to stop serving.
+func NewPrometheusDest(address string) *PrometheusDest {
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		panic(fmt.Sprintf("failed listening on %s: %v", address, err))
+	}
+
+	rv := &PrometheusDest{values: map[prometheusSeries]prometheusValue{}}
+
+	var mux http.ServeMux
+	mux.Handle("/metrics", rv)
+	rv.server = &http.Server{Handler: &mux}
+
+	go func() {
+		err := rv.server.Serve(ln)
+		if err != nil && err != http.ErrServerClosed {
+			log.Printf("failed serving prometheus metrics: %v", err)
+		}
+	}()
+	return rv
+}
+
+// Metric implements MetricDest
+func (d *PrometheusDest) Metric(application, instance string,
+	key []byte, val float64, ts time.Time) error {
+
+	series := prometheusSeries{
+		name:        prometheusName(key),
+		application: application,
+		instance:    instance,
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if latest, ok := d.values[series]; !ok || !ts.Before(latest.ts) {
+		d.values[series] = prometheusValue{val: val, ts: ts}
+	}
+	return nil
+}
+
+// ServeHTTP writes the latest values of the metrics
+func (d *PrometheusDest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	stale := time.Now().Add(-prometheusStaleness)
+
+	d.mtx.Lock()
+	series := make([]prometheusSeries, 0, len(d.values))
+	values := make(map[prometheusSeries]prometheusValue, len(d.values))
+	for s, v := range d.values {
+		if v.ts.Before(stale) {
+			delete(d.values, s)
+			continue
+		}
+		series = append(series, s)
+		values[s] = v
+	}
+	d.mtx.Unlock()
+
+	// the samples of a metric have to be grouped together
+	sort.Slice(series, func(i, k int) bool {
+		if series[i].name != series[k].name {
+			return series[i].name < series[k].name
+		}
+		if series[i].application != series[k].application {
+			return series[i].application < series[k].application
+		}
+		return series[i].instance < series[k].instance
+	})
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	buf := bufio.NewWriter(w)
+	for i, s := range series {
+		// the latest value of a metric can go up and down
+		if i == 0 || series[i-1].name != s.name {
+			_, _ = fmt.Fprintf(buf, "# TYPE %s gauge\n", s.name)
+		}
+		v := values[s]
+		_, _ = fmt.Fprintf(buf, "%s{application=\"%s\",instance=\"%s\"} %s %d\n",
+			s.name, prometheusLabelEscaper.Replace(s.application),
+			prometheusLabelEscaper.Replace(s.instance), prometheusValueString(v.val),
+			v.ts.UnixNano()/int64(time.Millisecond))
+	}
+	_ = buf.Flush()
+}
+
+// Close stops serving the scrapes
+func (d *PrometheusDest) Close() error {
+	return d.server.Close()
+}
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// prometheusName converts a metric key into a valid Prometheus metric name
+func prometheusName(key []byte) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(r >= '0' && r <= '9') || r == '_' || r == ':' {
+			return r
+		}
+		return '_'
+	}, string(key))
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+func prometheusValueString(val float64) string {
+	switch {
+	case math.IsNaN(val):
+		return "NaN"
+	case math.IsInf(val, 1):
+		return "+Inf"
+	case math.IsInf(val, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
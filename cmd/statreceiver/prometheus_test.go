@@ -0,0 +1,63 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"math"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusScrape(t *testing.T) {
+	dest := &PrometheusDest{values: map[prometheusSeries]prometheusValue{}}
+
+	now := time.Now().Truncate(time.Millisecond)
+	ms := strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10)
+
+	require.NoError(t, dest.Metric("uplink", "b", []byte("bytes.sent"), 1, now))
+	require.NoError(t, dest.Metric("uplink", "a", []byte("bytes.sent"), 2, now))
+	require.NoError(t, dest.Metric("satellite", "a", []byte("1-errors"), math.Inf(1), now))
+	// the labels are escaped
+	require.NoError(t, dest.Metric(`app"\`, "multi\nline", []byte("bytes.sent"), 3, now))
+	// only the latest value of a series is kept
+	require.NoError(t, dest.Metric("uplink", "b", []byte("bytes.sent"), 4, now.Add(-time.Second)))
+	require.NoError(t, dest.Metric("uplink", "a", []byte("bytes.sent"), 5, now))
+	// the stale series are dropped
+	require.NoError(t, dest.Metric("uplink", "a", []byte("old"), 6, now.Add(-2*prometheusStaleness)))
+
+	rec := httptest.NewRecorder()
+	dest.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, "text/plain; version=0.0.4", rec.Header().Get("Content-Type"))
+	// the samples are grouped by metric
+	assert.Equal(t, ""+
+		"# TYPE _1_errors gauge\n"+
+		`_1_errors{application="satellite",instance="a"} +Inf `+ms+"\n"+
+		"# TYPE bytes_sent gauge\n"+
+		`bytes_sent{application="app\"\\",instance="multi\nline"} 3 `+ms+"\n"+
+		`bytes_sent{application="uplink",instance="a"} 5 `+ms+"\n"+
+		`bytes_sent{application="uplink",instance="b"} 1 `+ms+"\n",
+		rec.Body.String())
+	assert.Len(t, dest.values, 4)
+}
+
+func TestPrometheusName(t *testing.T) {
+	for _, tt := range []struct {
+		key      string
+		expected string
+	}{
+		{"bytes_sent", "bytes_sent"},
+		{"piece.count", "piece_count"},
+		{"job:rate", "job:rate"},
+		{"2xx", "_2xx"},
+		{"", "_"},
+	} {
+		assert.Equal(t, tt.expected, prometheusName([]byte(tt.key)), tt.key)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/transport"
 )
 
 var (
@@ -48,9 +49,10 @@ var (
 	}
 
 	runCfg struct {
-		Identity provider.IdentityConfig
-		Kademlia kademlia.Config
-		Storage  psserver.Config
+		Identity  provider.IdentityConfig
+		Kademlia  kademlia.Config
+		Storage   psserver.Config
+		Transport transport.Config
 	}
 	setupCfg struct {
 		CA       provider.CASetupConfig
@@ -89,7 +91,15 @@ func init() {
 }
 
 func cmdRun(cmd *cobra.Command, args []string) (err error) {
-	return runCfg.Identity.Run(process.Ctx(cmd), nil, runCfg.Kademlia, runCfg.Storage)
+	transport.Configure(runCfg.Transport)
+
+	identity, err := runCfg.Identity.Load()
+	if err != nil {
+		return err
+	}
+	process.SetNodeID(cmd, identity.ID)
+
+	return runCfg.Identity.Run(process.Ctx(cmd), nil, runCfg.Kademlia, &runCfg.Storage)
 }
 
 func cmdSetup(cmd *cobra.Command, args []string) (err error) {
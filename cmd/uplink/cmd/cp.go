@@ -24,6 +24,7 @@ import (
 
 var (
 	progress *bool
+	compress *bool
 )
 
 func init() {
@@ -33,10 +34,25 @@ func init() {
 		RunE:  copyMain,
 	}, CLICmd)
 	progress = cpCmd.Flags().Bool("progress", true, "if true, show progress")
+	compress = cpCmd.Flags().Bool("compress", false, "if true, compress the uploaded object with gzip; if false, upload it uncompressed even if the bucket compresses by default")
+}
+
+// compressionOptions returns the upload options of the --compress flag. The
+// default compression of the bucket applies unless the flag is set.
+func compressionOptions(cmd *cobra.Command) storj.CreateObject {
+	createInfo := storj.CreateObject{}
+	if flag := cmd.Flags().Lookup("compress"); flag != nil && flag.Changed {
+		if *compress {
+			createInfo.Compression = storj.Gzip
+		} else {
+			createInfo.DisableCompression = true
+		}
+	}
+	return createInfo
 }
 
 // upload transfers src from local machine to s3 compatible object dst
-func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress bool) error {
+func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress bool, createInfo storj.CreateObject) error {
 	if !src.IsLocal() {
 		return fmt.Errorf("source must be local path: %s", src)
 	}
@@ -76,10 +92,6 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress
 		return err
 	}
 
-	createInfo := storj.CreateObject{
-		RedundancyScheme: cfg.GetRedundancyScheme(),
-		EncryptionScheme: cfg.GetEncryptionScheme(),
-	}
 	obj, err := metainfo.CreateObject(ctx, dst.Bucket(), dst.Path(), &createInfo)
 	if err != nil {
 		return convertError(err, dst)
@@ -102,9 +114,7 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress
 		bar.Finish()
 	}
 
-	fmt.Printf("Created %s\n", dst.String())
-
-	return nil
+	return printOperation(operationOutput{Operation: "upload", Source: src.String(), Destination: dst.String()}, "Created %s\n", dst.String())
 }
 
 func uploadStream(ctx context.Context, streams streams.Store, mutableObject storj.MutableObject, reader io.Reader) error {
@@ -170,6 +180,10 @@ func download(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgres
 
 	_, err = io.Copy(file, reader)
 	if err != nil {
+		if stream.ErrChecksum.Has(err) && file != os.Stdout {
+			// don't leave the corrupted content behind
+			err = utils.CombineErrors(err, os.Remove(dst.Path()))
+		}
 		return err
 	}
 
@@ -177,15 +191,15 @@ func download(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgres
 		bar.Finish()
 	}
 
-	if dst.Base() != "-" {
-		fmt.Printf("Downloaded %s to %s\n", src.String(), dst.String())
+	if dst.Base() == "-" {
+		return nil
 	}
 
-	return nil
+	return printOperation(operationOutput{Operation: "download", Source: src.String(), Destination: dst.String()}, "Downloaded %s to %s\n", src.String(), dst.String())
 }
 
 // copy copies s3 compatible object src to s3 compatible object dst
-func copy(ctx context.Context, src fpath.FPath, dst fpath.FPath) error {
+func copy(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress bool, createInfo storj.CreateObject) error {
 	if src.IsLocal() {
 		return fmt.Errorf("source must be Storj URL: %s", src)
 	}
@@ -209,7 +223,7 @@ func copy(ctx context.Context, src fpath.FPath, dst fpath.FPath) error {
 
 	var bar *progressbar.ProgressBar
 	var reader io.Reader
-	if *progress {
+	if showProgress {
 		bar = progressbar.New(int(readOnlyStream.Info().Size)).SetUnits(progressbar.U_BYTES)
 		bar.Start()
 		reader = bar.NewProxyReader(download)
@@ -222,10 +236,6 @@ func copy(ctx context.Context, src fpath.FPath, dst fpath.FPath) error {
 		dst = dst.Join(src.Base())
 	}
 
-	createInfo := storj.CreateObject{
-		RedundancyScheme: cfg.GetRedundancyScheme(),
-		EncryptionScheme: cfg.GetEncryptionScheme(),
-	}
 	obj, err := metainfo.CreateObject(ctx, dst.Bucket(), dst.Path(), &createInfo)
 	if err != nil {
 		return convertError(err, dst)
@@ -240,9 +250,7 @@ func copy(ctx context.Context, src fpath.FPath, dst fpath.FPath) error {
 		bar.Finish()
 	}
 
-	fmt.Printf("%s copied to %s\n", src.String(), dst.String())
-
-	return nil
+	return printOperation(operationOutput{Operation: "copy", Source: src.String(), Destination: dst.String()}, "%s copied to %s\n", src.String(), dst.String())
 }
 
 // copyMain is the function executed when cpCmd is called
@@ -256,6 +264,14 @@ func copyMain(cmd *cobra.Command, args []string) (err error) {
 
 	ctx := process.Ctx(cmd)
 
+	asJSON, err := jsonOutput()
+	if err != nil {
+		return err
+	}
+
+	// the progress bar would corrupt the JSON output
+	showProgress := *progress && !asJSON
+
 	src, err := fpath.New(args[0])
 	if err != nil {
 		return err
@@ -273,14 +289,14 @@ func copyMain(cmd *cobra.Command, args []string) (err error) {
 
 	// if uploading
 	if src.IsLocal() {
-		return upload(ctx, src, dst, *progress)
+		return upload(ctx, src, dst, showProgress, compressionOptions(cmd))
 	}
 
 	// if downloading
 	if dst.IsLocal() {
-		return download(ctx, src, dst, *progress)
+		return download(ctx, src, dst, showProgress)
 	}
 
 	// if copying from one remote location to another
-	return copy(ctx, src, dst)
+	return copy(ctx, src, dst, showProgress, compressionOptions(cmd))
 }
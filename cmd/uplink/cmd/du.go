@@ -0,0 +1,111 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storj"
+)
+
+func init() {
+	addCmd(&cobra.Command{
+		Use:   "du",
+		Short: "Summarize the size of the objects by prefix",
+		RunE:  diskUsage,
+	}, CLICmd)
+}
+
+// usageOutput is the JSON output of the size of the objects under a prefix
+type usageOutput struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	Objects int64  `json:"objects"`
+}
+
+func diskUsage(cmd *cobra.Command, args []string) error {
+	ctx := process.Ctx(cmd)
+
+	asJSON, err := jsonOutput()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("No bucket specified, use format sj://bucket/")
+	}
+
+	src, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+
+	if src.IsLocal() {
+		return fmt.Errorf("No bucket specified, use format sj://bucket/")
+	}
+
+	metainfo, _, err := cfg.Metainfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	usages, err := usageByPrefix(ctx, metainfo, src)
+	if err != nil {
+		return convertError(err, src)
+	}
+
+	if asJSON {
+		return printJSON(usages)
+	}
+
+	for _, usage := range usages {
+		fmt.Printf("%12v %8v %v\n", usage.Size, usage.Objects, usage.Path)
+	}
+
+	return nil
+}
+
+// usageByPrefix sums the size of the objects under src in each first level
+// prefix, sorted by path, followed by the total of all objects
+func usageByPrefix(ctx context.Context, metainfo storj.Metainfo, src fpath.FPath) ([]usageOutput, error) {
+	total := usageOutput{Path: src.String()}
+	prefixes := make(map[string]*usageOutput)
+
+	err := walkObjects(ctx, metainfo, src, true, func(object storj.Object) error {
+		total.Size += object.Size
+		total.Objects++
+
+		// sum the objects in the first level prefixes separately
+		i := strings.Index(object.Path, "/")
+		if i < 0 {
+			return nil
+		}
+		prefix := object.Path[:i+1]
+		usage, ok := prefixes[prefix]
+		if !ok {
+			usage = &usageOutput{Path: prefix}
+			prefixes[prefix] = usage
+		}
+		usage.Size += object.Size
+		usage.Objects++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]usageOutput, 0, len(prefixes)+1)
+	for _, usage := range prefixes {
+		usages = append(usages, *usage)
+	}
+	sort.Slice(usages, func(i, k int) bool { return usages[i].Path < usages[k].Path })
+	return append(usages, total), nil
+}
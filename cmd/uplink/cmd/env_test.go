@@ -0,0 +1,99 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vivint/infectious"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/encryption"
+	"storj.io/storj/pkg/metainfo/kvmetainfo"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/ec"
+	"storj.io/storj/pkg/storage/segments"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+)
+
+const (
+	testAPIKey = "test-api-key"
+	testEncKey = "test-encryption-key"
+	testBucket = "test-bucket"
+)
+
+// runPlanetTest runs the test against a bucket of a test network
+func runPlanetTest(t *testing.T, test func(ctx *testcontext.Context, metainfo storj.Metainfo, streams streams.Store, bucket storj.Bucket)) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 4, 1)
+	require.NoError(t, err)
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	// TODO(kaloyan): We should have a better way for configuring the Satellite's API Key
+	require.NoError(t, flag.Set("pointer-db.auth.api-key", testAPIKey))
+
+	oc, err := planet.Uplinks[0].DialOverlay(planet.Satellites[0])
+	require.NoError(t, err)
+
+	pdb, err := planet.Uplinks[0].DialPointerDB(planet.Satellites[0], testAPIKey)
+	require.NoError(t, err)
+
+	ec := ecclient.NewClient(planet.Uplinks[0].Identity, 0)
+	fc, err := infectious.NewFEC(2, 4)
+	require.NoError(t, err)
+
+	rs, err := eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, int(1*memory.KB)), 3, 4)
+	require.NoError(t, err)
+
+	segments := segments.NewSegmentStore(oc, ec, pdb, rs, int(8*memory.KB))
+
+	var key storj.Key
+	for i := range key {
+		key[i] = testEncKey[i%len(testEncKey)]
+	}
+	keys := encryption.NewKeyStore(&key, false)
+
+	streams, err := streams.NewStreamStore(segments, int64(64*memory.MB), keys, int(1*memory.KB), storj.AESGCM, 0, 0)
+	require.NoError(t, err)
+
+	metainfo := kvmetainfo.New(buckets.NewStore(streams), streams, segments, pdb, keys)
+
+	bucket, err := metainfo.CreateBucket(ctx, testBucket, &storj.Bucket{
+		PathCipher: storj.AESGCM,
+		EncryptionScheme: storj.EncryptionScheme{
+			Cipher:    storj.AESGCM,
+			BlockSize: 1 * memory.KB.Int32(),
+		},
+		RedundancyScheme: storj.RedundancyScheme{
+			Algorithm:      storj.ReedSolomon,
+			RequiredShares: 2,
+			RepairShares:   3,
+			OptimalShares:  4,
+			TotalShares:    4,
+			ShareSize:      1 * memory.KB.Int32(),
+		},
+	})
+	require.NoError(t, err)
+
+	test(ctx, metainfo, streams, bucket)
+}
+
+// uploadTestObject uploads the data as the object at path in the bucket
+func uploadTestObject(t *testing.T, ctx *testcontext.Context, metainfo storj.Metainfo, streams streams.Store, bucket storj.Bucket, path storj.Path, data []byte) {
+	obj, err := metainfo.CreateObject(ctx, bucket.Name, path, nil)
+	require.NoError(t, err)
+	require.NoError(t, uploadStream(ctx, streams, obj, bytes.NewReader(data)))
+	require.NoError(t, obj.Commit(ctx))
+}
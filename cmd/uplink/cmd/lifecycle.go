@@ -0,0 +1,140 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/pkg/metainfo/kvmetainfo"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storj"
+)
+
+var (
+	lifecycleExpire *[]string
+	lifecycleClear  *bool
+	lifecycleApply  *bool
+)
+
+func init() {
+	lifecycleCmd := addCmd(&cobra.Command{
+		Use:   "lifecycle",
+		Short: "Show or change the expiration rules of a bucket",
+		RunE:  bucketLifecycle,
+	}, CLICmd)
+	lifecycleExpire = lifecycleCmd.Flags().StringSlice("expire", nil, "expire the new objects under a prefix after a number of days, as prefix=days; replaces the existing rules")
+	lifecycleClear = lifecycleCmd.Flags().Bool("clear", false, "if true, remove all expiration rules")
+	lifecycleApply = lifecycleCmd.Flags().Bool("apply", false, "if true, delete the existing objects without expiration which are older than the rules allow")
+}
+
+// lifecycleOutput is the JSON output of the lifecycle command
+type lifecycleOutput struct {
+	Bucket  string                `json:"bucket"`
+	Rules   []storj.LifecycleRule `json:"rules"`
+	Deleted int                   `json:"deleted"`
+}
+
+// parseLifecycleRule parses an expiration rule in the format prefix=days
+func parseLifecycleRule(value string) (storj.LifecycleRule, error) {
+	i := strings.LastIndex(value, "=")
+	if i < 0 {
+		return storj.LifecycleRule{}, fmt.Errorf("Invalid expiration rule %q, use format prefix=days", value)
+	}
+
+	days, err := strconv.Atoi(value[i+1:])
+	if err != nil || days <= 0 {
+		return storj.LifecycleRule{}, fmt.Errorf("Invalid expiration rule %q, days must be a positive number", value)
+	}
+
+	return storj.LifecycleRule{Prefix: value[:i], Days: days}, nil
+}
+
+func bucketLifecycle(cmd *cobra.Command, args []string) error {
+	ctx := process.Ctx(cmd)
+
+	asJSON, err := jsonOutput()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("No bucket specified")
+	}
+
+	if *lifecycleClear && len(*lifecycleExpire) > 0 {
+		return fmt.Errorf("Use either --expire or --clear")
+	}
+
+	var rules []storj.LifecycleRule
+	for _, value := range *lifecycleExpire {
+		rule, err := parseLifecycleRule(value)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+
+	dst, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+
+	if dst.IsLocal() {
+		return fmt.Errorf("No bucket specified, use format sj://bucket/")
+	}
+
+	if dst.Path() != "" {
+		return fmt.Errorf("Nested buckets not supported, use format sj://bucket/")
+	}
+
+	metainfo, _, err := cfg.Metainfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	db, ok := metainfo.(*kvmetainfo.DB)
+	if !ok {
+		return fmt.Errorf("Bucket lifecycle is not supported by the metainfo implementation")
+	}
+
+	var bucket storj.Bucket
+	if *lifecycleClear || len(rules) > 0 {
+		bucket, err = db.SetBucketLifecycle(ctx, dst.Bucket(), rules)
+	} else {
+		bucket, err = db.GetBucket(ctx, dst.Bucket())
+	}
+	if err != nil {
+		return convertError(err, dst)
+	}
+
+	output := lifecycleOutput{Bucket: bucket.Name, Rules: bucket.Lifecycle}
+	if *lifecycleApply {
+		output.Deleted, err = db.ApplyBucketLifecycle(ctx, dst.Bucket(), time.Now())
+		if err != nil {
+			return convertError(err, dst)
+		}
+	}
+
+	if asJSON {
+		return printJSON(output)
+	}
+
+	if len(bucket.Lifecycle) == 0 {
+		fmt.Printf("Bucket %s has no expiration rules\n", bucket.Name)
+	}
+	for _, rule := range bucket.Lifecycle {
+		fmt.Printf("Objects under %q expire %d days after their creation\n", rule.Prefix, rule.Days)
+	}
+	if *lifecycleApply {
+		fmt.Printf("Deleted %d expired existing objects\n", output.Deleted)
+	}
+
+	return nil
+}
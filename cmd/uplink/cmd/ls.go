@@ -31,6 +31,11 @@ func init() {
 func list(cmd *cobra.Command, args []string) error {
 	ctx := process.Ctx(cmd)
 
+	asJSON, err := jsonOutput()
+	if err != nil {
+		return err
+	}
+
 	metainfo, _, err := cfg.Metainfo(ctx)
 	if err != nil {
 		return err
@@ -46,6 +51,14 @@ func list(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("No bucket specified, use format sj://bucket/")
 		}
 
+		if asJSON {
+			objects, err := listObjectsOutput(ctx, metainfo, src)
+			if err != nil {
+				return convertError(err, src)
+			}
+			return printJSON(objects)
+		}
+
 		err = listFiles(ctx, metainfo, src, false)
 
 		return convertError(err, src)
@@ -53,6 +66,7 @@ func list(cmd *cobra.Command, args []string) error {
 
 	startAfter := ""
 	noBuckets := true
+	buckets := []bucketOutput{}
 
 	for {
 		list, err := metainfo.ListBuckets(ctx, storj.BucketListOptions{Direction: storj.After, Cursor: startAfter})
@@ -62,12 +76,28 @@ func list(cmd *cobra.Command, args []string) error {
 		if len(list.Items) > 0 {
 			noBuckets = false
 			for _, bucket := range list.Items {
-				fmt.Println("BKT", formatTime(bucket.Created), bucket.Name)
+				var prefix fpath.FPath
 				if *recursiveFlag {
-					prefix, err := fpath.New(fmt.Sprintf("sj://%s/", bucket.Name))
+					prefix, err = fpath.New(fmt.Sprintf("sj://%s/", bucket.Name))
 					if err != nil {
 						return err
 					}
+				}
+
+				if asJSON {
+					output := newBucketOutput(bucket)
+					if *recursiveFlag {
+						output.Objects, err = listObjectsOutput(ctx, metainfo, prefix)
+						if err != nil {
+							return err
+						}
+					}
+					buckets = append(buckets, output)
+					continue
+				}
+
+				fmt.Println("BKT", formatTime(bucket.Created), bucket.Name)
+				if *recursiveFlag {
 					err = listFiles(ctx, metainfo, prefix, true)
 					if err != nil {
 						return err
@@ -81,6 +111,10 @@ func list(cmd *cobra.Command, args []string) error {
 		startAfter = list.Items[len(list.Items)-1].Name
 	}
 
+	if asJSON {
+		return printJSON(buckets)
+	}
+
 	if noBuckets {
 		fmt.Println("No buckets")
 	}
@@ -89,6 +123,32 @@ func list(cmd *cobra.Command, args []string) error {
 }
 
 func listFiles(ctx context.Context, metainfo storj.Metainfo, prefix fpath.FPath, prependBucket bool) error {
+	return walkObjects(ctx, metainfo, prefix, *recursiveFlag, func(object storj.Object) error {
+		path := object.Path
+		if prependBucket {
+			path = fmt.Sprintf("%s/%s", prefix.Bucket(), path)
+		}
+		if object.IsPrefix {
+			fmt.Println("PRE", path)
+		} else {
+			fmt.Printf("%v %v %12v %v\n", "OBJ", formatTime(object.Modified), object.Size, path)
+		}
+		return nil
+	})
+}
+
+// listObjectsOutput returns the JSON output of the objects under prefix
+func listObjectsOutput(ctx context.Context, metainfo storj.Metainfo, prefix fpath.FPath) ([]objectOutput, error) {
+	objects := []objectOutput{}
+	err := walkObjects(ctx, metainfo, prefix, *recursiveFlag, func(object storj.Object) error {
+		objects = append(objects, newObjectOutput(object))
+		return nil
+	})
+	return objects, err
+}
+
+// walkObjects calls handler for each object and prefix listed under prefix
+func walkObjects(ctx context.Context, metainfo storj.Metainfo, prefix fpath.FPath, recursive bool, handler func(storj.Object) error) error {
 	startAfter := ""
 
 	for {
@@ -96,21 +156,15 @@ func listFiles(ctx context.Context, metainfo storj.Metainfo, prefix fpath.FPath,
 			Direction: storj.After,
 			Cursor:    startAfter,
 			Prefix:    prefix.Path(),
-			Recursive: *recursiveFlag,
+			Recursive: recursive,
 		})
 		if err != nil {
 			return err
 		}
 
 		for _, object := range list.Items {
-			path := object.Path
-			if prependBucket {
-				path = fmt.Sprintf("%s/%s", prefix.Bucket(), path)
-			}
-			if object.IsPrefix {
-				fmt.Println("PRE", path)
-			} else {
-				fmt.Printf("%v %v %12v %v\n", "OBJ", formatTime(object.Modified), object.Size, path)
+			if err := handler(object); err != nil {
+				return err
 			}
 		}
 
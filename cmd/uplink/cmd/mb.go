@@ -5,6 +5,8 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -13,17 +15,68 @@ import (
 	"storj.io/storj/pkg/storj"
 )
 
+var (
+	mbRedundancy  *string
+	mbCompression *string
+)
+
 func init() {
-	addCmd(&cobra.Command{
+	mbCmd := addCmd(&cobra.Command{
 		Use:   "mb",
 		Short: "Create a new bucket",
 		RunE:  makeBucket,
 	}, CLICmd)
+	mbRedundancy = mbCmd.Flags().String("rs", "", "default redundancy of the objects of the bucket as k/m/o/n, the configured one if empty")
+	mbCompression = mbCmd.Flags().String("compression", "none", "default compression of the objects of the bucket, either none or gzip")
+}
+
+// parseRedundancy parses a redundancy scheme in the format k/m/o/n with the
+// configured share size
+func parseRedundancy(value string) (storj.RedundancyScheme, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 4 {
+		return storj.RedundancyScheme{}, fmt.Errorf("Invalid redundancy %q, use format k/m/o/n", value)
+	}
+
+	var shares [4]int16
+	for i, part := range parts {
+		v, err := strconv.ParseInt(part, 10, 16)
+		if err != nil || v <= 0 {
+			return storj.RedundancyScheme{}, fmt.Errorf("Invalid redundancy %q, use format k/m/o/n", value)
+		}
+		shares[i] = int16(v)
+	}
+
+	k, m, o, n := shares[0], shares[1], shares[2], shares[3]
+	if k > m || m > o || o > n {
+		return storj.RedundancyScheme{}, fmt.Errorf("Invalid redundancy %q, k <= m <= o <= n is required", value)
+	}
+
+	scheme := cfg.GetRedundancyScheme()
+	scheme.RequiredShares = k
+	scheme.RepairShares = m
+	scheme.OptimalShares = o
+	scheme.TotalShares = n
+	return scheme, nil
+}
+
+// parseCompression parses the name of a compression algorithm
+func parseCompression(value string) (storj.Compression, error) {
+	for _, compression := range []storj.Compression{storj.Uncompressed, storj.Gzip} {
+		if value == compression.String() {
+			return compression, nil
+		}
+	}
+	return storj.Uncompressed, fmt.Errorf("Invalid compression %q, use none or gzip", value)
 }
 
 func makeBucket(cmd *cobra.Command, args []string) error {
 	ctx := process.Ctx(cmd)
 
+	if _, err := jsonOutput(); err != nil {
+		return err
+	}
+
 	if len(args) == 0 {
 		return fmt.Errorf("No bucket specified for creation")
 	}
@@ -41,6 +94,24 @@ func makeBucket(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Nested buckets not supported, use format sj://bucket/")
 	}
 
+	redundancy := cfg.GetRedundancyScheme()
+	if *mbRedundancy != "" {
+		redundancy, err = parseRedundancy(*mbRedundancy)
+		if err != nil {
+			return err
+		}
+	}
+
+	compression, err := parseCompression(*mbCompression)
+	if err != nil {
+		return err
+	}
+
+	encryption := cfg.GetEncryptionScheme()
+	if int(redundancy.ShareSize)*int(redundancy.RequiredShares)%int(encryption.BlockSize) != 0 {
+		return fmt.Errorf("Encryption block size must be a divisor of the stripe size (share size * k)")
+	}
+
 	metainfo, _, err := cfg.Metainfo(ctx)
 	if err != nil {
 		return err
@@ -53,12 +124,17 @@ func makeBucket(cmd *cobra.Command, args []string) error {
 	if !storj.ErrBucketNotFound.Has(err) {
 		return err
 	}
-	_, err = metainfo.CreateBucket(ctx, dst.Bucket(), &storj.Bucket{PathCipher: storj.Cipher(cfg.Enc.PathType)})
+	_, err = metainfo.CreateBucket(ctx, dst.Bucket(), &storj.Bucket{
+		PathCipher:       storj.Cipher(cfg.Enc.PathType),
+		SegmentsSize:     cfg.Client.SegmentSize,
+		InlineThreshold:  cfg.Client.MaxInlineSize,
+		RedundancyScheme: redundancy,
+		EncryptionScheme: encryption,
+		Compression:      compression,
+	})
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Bucket %s created\n", dst.Bucket())
-
-	return nil
+	return printOperation(operationOutput{Operation: "create-bucket", Destination: dst.String()}, "Bucket %s created\n", dst.Bucket())
 }
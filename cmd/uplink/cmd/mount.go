@@ -9,9 +9,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hanwen/go-fuse/fuse"
@@ -28,12 +30,21 @@ import (
 	"storj.io/storj/pkg/utils"
 )
 
+var (
+	cacheDir      *string
+	readBlockSize *int64
+	readAhead     *int
+)
+
 func init() {
-	addCmd(&cobra.Command{
+	mountCmd := addCmd(&cobra.Command{
 		Use:   "mount",
 		Short: "Mount a bucket",
 		RunE:  mountBucket,
 	}, CLICmd)
+	cacheDir = mountCmd.Flags().String("cache-dir", "", "directory for the local copies of files being written, a temporary directory if empty")
+	readBlockSize = mountCmd.Flags().Int64("read-block-size", 1<<20, "size of the blocks fetched when reading files")
+	readAhead = mountCmd.Flags().Int("read-ahead", 4, "number of blocks fetched ahead of the one being read")
 }
 
 func mountBucket(cmd *cobra.Command, args []string) (err error) {
@@ -64,12 +75,21 @@ func mountBucket(cmd *cobra.Command, args []string) (err error) {
 		return convertError(err, src)
 	}
 
-	nfs := pathfs.NewPathNodeFs(newStorjFS(ctx, metainfo, streams, bucket), nil)
+	dir := *cacheDir
+	if dir == "" {
+		dir, err = ioutil.TempDir("", "uplink-mount")
+		if err != nil {
+			return err
+		}
+		defer func() { _ = os.RemoveAll(dir) }()
+	} else if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	nfs := pathfs.NewPathNodeFs(newStorjFS(ctx, metainfo, streams, bucket, dir, *readBlockSize, *readAhead), nil)
 	conn := nodefs.NewFileSystemConnector(nfs.Root(), nil)
 
-	// workaround to avoid async (unordered) reading
-	mountOpts := fuse.MountOptions{MaxBackground: 1}
-	server, err := fuse.NewServer(conn.RawFS(), args[1], &mountOpts)
+	server, err := fuse.NewServer(conn.RawFS(), args[1], &fuse.MountOptions{})
 	if err != nil {
 		return fmt.Errorf("Mount failed: %v", err)
 	}
@@ -90,23 +110,34 @@ func mountBucket(cmd *cobra.Command, args []string) (err error) {
 }
 
 type storjFS struct {
-	ctx          context.Context
-	metainfo     storj.Metainfo
-	streams      streams.Store
-	bucket       storj.Bucket
-	createdFiles map[string]*storjFile
-	nodeFS       *pathfs.PathNodeFs
+	ctx      context.Context
+	metainfo storj.Metainfo
+	streams  streams.Store
+	bucket   storj.Bucket
+	nodeFS   *pathfs.PathNodeFs
+
+	cacheDir      string
+	readBlockSize int64
+	readAhead     int
+
+	mu sync.Mutex
+	// openFiles are shared by all the handles opened for the same path
+	openFiles map[string]*storjFile
+
 	pathfs.FileSystem
 }
 
-func newStorjFS(ctx context.Context, metainfo storj.Metainfo, streams streams.Store, bucket storj.Bucket) *storjFS {
+func newStorjFS(ctx context.Context, metainfo storj.Metainfo, streams streams.Store, bucket storj.Bucket, cacheDir string, readBlockSize int64, readAhead int) *storjFS {
 	return &storjFS{
-		ctx:          ctx,
-		metainfo:     metainfo,
-		streams:      streams,
-		bucket:       bucket,
-		createdFiles: make(map[string]*storjFile),
-		FileSystem:   pathfs.NewDefaultFileSystem(),
+		ctx:           ctx,
+		metainfo:      metainfo,
+		streams:       streams,
+		bucket:        bucket,
+		cacheDir:      cacheDir,
+		readBlockSize: readBlockSize,
+		readAhead:     readAhead,
+		openFiles:     make(map[string]*storjFile),
+		FileSystem:    pathfs.NewDefaultFileSystem(),
 	}
 }
 
@@ -121,11 +152,10 @@ func (sf *storjFS) GetAttr(name string, context *fuse.Context) (*fuse.Attr, fuse
 		return &fuse.Attr{Mode: fuse.S_IFDIR | 0755}, fuse.OK
 	}
 
-	// special case for just created files e.g. while coping into directory
-	createdFile, ok := sf.createdFiles[name]
-	if ok {
+	// special case for open files, which may have local changes not uploaded yet
+	if file := sf.getOpenFile(name); file != nil {
 		attr := &fuse.Attr{}
-		status := createdFile.GetAttr(attr)
+		status := file.GetAttr(attr)
 		return attr, status
 	}
 
@@ -191,9 +221,7 @@ func (sf *storjFS) Mkdir(name string, mode uint32, context *fuse.Context) fuse.S
 	zap.S().Debug("Mkdir: ", name)
 
 	createInfo := storj.CreateObject{
-		ContentType:      "application/directory",
-		RedundancyScheme: cfg.GetRedundancyScheme(),
-		EncryptionScheme: cfg.GetEncryptionScheme(),
+		ContentType: "application/directory",
 	}
 	object, err := sf.metainfo.CreateObject(sf.ctx, sf.bucket.Name, name+"/", &createInfo)
 	if err != nil {
@@ -240,22 +268,219 @@ func (sf *storjFS) Rmdir(name string, context *fuse.Context) (code fuse.Status)
 
 func (sf *storjFS) Open(name string, flags uint32, context *fuse.Context) (file nodefs.File, code fuse.Status) {
 	zap.S().Debug("Open: ", name)
-	return newStorjFile(sf.ctx, name, sf.metainfo, sf.streams, sf.bucket, false, sf), fuse.OK
+
+	storjFile, err := sf.openFile(name, false)
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			return nil, fuse.ENOENT
+		}
+		zap.S().Errorf("error during opening file: %v", err)
+		return nil, fuse.EIO
+	}
+
+	if flags&uint32(os.O_TRUNC) != 0 {
+		if code := storjFile.Truncate(0); !code.Ok() {
+			storjFile.Release()
+			return nil, code
+		}
+	}
+
+	return storjFile, fuse.OK
 }
 
 func (sf *storjFS) Create(name string, flags uint32, mode uint32, context *fuse.Context) (file nodefs.File, code fuse.Status) {
 	zap.S().Debug("Create: ", name)
 
-	return sf.addCreatedFile(name, newStorjFile(sf.ctx, name, sf.metainfo, sf.streams, sf.bucket, true, sf)), fuse.OK
+	storjFile, err := sf.openFile(name, true)
+	if err != nil {
+		zap.S().Errorf("error during creating file: %v", err)
+		return nil, fuse.EIO
+	}
+
+	// the file may be open already, in which case it's truncated
+	if code := storjFile.Truncate(0); !code.Ok() {
+		storjFile.Release()
+		return nil, code
+	}
+
+	return storjFile, fuse.OK
+}
+
+func (sf *storjFS) Truncate(name string, size uint64, context *fuse.Context) (code fuse.Status) {
+	zap.S().Debug("Truncate: ", name)
+
+	file, err := sf.openFile(name, false)
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			return fuse.ENOENT
+		}
+		return fuse.EIO
+	}
+	defer file.Release()
+
+	if code := file.Truncate(size); !code.Ok() {
+		return code
+	}
+	return file.Flush()
 }
 
-func (sf *storjFS) addCreatedFile(name string, file *storjFile) *storjFile {
-	sf.createdFiles[name] = file
-	return file
+// Chmod is a no-op as objects don't have permissions
+func (sf *storjFS) Chmod(name string, mode uint32, context *fuse.Context) (code fuse.Status) {
+	return fuse.OK
 }
 
-func (sf *storjFS) removeCreatedFile(name string) {
-	delete(sf.createdFiles, name)
+// Chown is a no-op as objects don't have owners
+func (sf *storjFS) Chown(name string, uid uint32, gid uint32, context *fuse.Context) (code fuse.Status) {
+	return fuse.OK
+}
+
+func (sf *storjFS) Utimens(name string, atime *time.Time, mtime *time.Time, context *fuse.Context) (code fuse.Status) {
+	if file := sf.getOpenFile(name); file != nil {
+		return file.Utimens(atime, mtime)
+	}
+	return fuse.OK
+}
+
+func (sf *storjFS) Rename(oldName string, newName string, context *fuse.Context) (code fuse.Status) {
+	zap.S().Debug("Rename: ", oldName, " -> ", newName)
+
+	// upload local changes first, so they are moved too
+	if file := sf.getOpenFile(oldName); file != nil {
+		if code := file.Flush(); !code.Ok() {
+			return code
+		}
+	}
+
+	_, err := sf.metainfo.GetObject(sf.ctx, sf.bucket.Name, oldName)
+	if err == nil {
+		err = sf.moveObject(oldName, newName)
+	} else if storj.ErrObjectNotFound.Has(err) {
+		// file not found so maybe it's a prefix/directory
+		err = sf.listObjects(sf.ctx, oldName, true, func(items []storj.Object) error {
+			for _, item := range items {
+				err := sf.moveObject(storj.JoinPaths(oldName, item.Path), storj.JoinPaths(newName, item.Path))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		zap.S().Errorf("error during renaming: %v", err)
+		return fuse.EIO
+	}
+
+	return fuse.OK
+}
+
+// moveObject copies the object to the new path and deletes the old one
+func (sf *storjFS) moveObject(oldName, newName string) error {
+	readOnlyStream, err := sf.metainfo.GetObjectStream(sf.ctx, sf.bucket.Name, oldName)
+	if err != nil {
+		return err
+	}
+
+	info := readOnlyStream.Info()
+	createInfo := storj.CreateObject{
+		ContentType: info.ContentType,
+		Metadata:    info.Metadata,
+		Expires:     info.Expires,
+	}
+
+	download := stream.NewDownload(sf.ctx, readOnlyStream, sf.streams)
+	err = sf.uploadObject(newName, createInfo, download)
+	err = utils.CombineErrors(err, download.Close())
+	if err != nil {
+		return err
+	}
+
+	err = sf.metainfo.DeleteObject(sf.ctx, sf.bucket.Name, oldName)
+	if err != nil {
+		return err
+	}
+
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	if file, ok := sf.openFiles[oldName]; ok {
+		delete(sf.openFiles, oldName)
+		file.rename(newName)
+		sf.openFiles[newName] = file
+	}
+
+	return nil
+}
+
+// uploadObject uploads data as the object with the given name and commits it
+func (sf *storjFS) uploadObject(name string, createInfo storj.CreateObject, data io.Reader) error {
+	object, err := sf.metainfo.CreateObject(sf.ctx, sf.bucket.Name, name, &createInfo)
+	if err != nil {
+		return err
+	}
+
+	mutableStream, err := object.CreateStream(sf.ctx)
+	if err != nil {
+		return err
+	}
+
+	upload := stream.NewUpload(sf.ctx, mutableStream, sf.streams)
+
+	_, err = io.Copy(upload, data)
+	err = utils.CombineErrors(err, upload.Close())
+	if err != nil {
+		return err
+	}
+
+	return object.Commit(sf.ctx)
+}
+
+// openFile returns the file shared by all handles of name, which is
+// created if it's not open yet. Each call must be paired with a Release of
+// the returned file.
+func (sf *storjFS) openFile(name string, create bool) (*storjFile, error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	if file, ok := sf.openFiles[name]; ok {
+		file.refs++
+		return file, nil
+	}
+
+	file := newStorjFile(sf, name)
+	if !create {
+		object, err := sf.metainfo.GetObject(sf.ctx, sf.bucket.Name, name)
+		if err != nil {
+			return nil, err
+		}
+		file.object = object
+	}
+
+	file.refs = 1
+	sf.openFiles[name] = file
+	return file, nil
+}
+
+func (sf *storjFS) getOpenFile(name string) *storjFile {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	return sf.openFiles[name]
+}
+
+// releaseFile closes the file when its last handle is released
+func (sf *storjFS) releaseFile(file *storjFile) {
+	sf.mu.Lock()
+	file.refs--
+	last := file.refs == 0
+	if last && sf.openFiles[file.name] == file {
+		delete(sf.openFiles, file.name)
+	}
+	sf.mu.Unlock()
+
+	if last {
+		file.close()
+	}
 }
 
 func (sf *storjFS) listObjects(ctx context.Context, name string, recursive bool, handler func([]storj.Object) error) error {
@@ -301,162 +526,273 @@ func (sf *storjFS) Unlink(name string, context *fuse.Context) (code fuse.Status)
 	return fuse.OK
 }
 
+// storjFile is an object opened in the mount. Writes go to a local copy of
+// the object in the cache directory, which is uploaded on flush, while reads
+// of objects without local changes are served by a block cache.
 type storjFile struct {
-	ctx             context.Context
-	metainfo        storj.Metainfo
-	streams         streams.Store
-	bucket          storj.Bucket
-	created         bool
-	name            string
-	size            uint64
-	mtime           uint64
-	reader          io.ReadCloser
-	writer          io.WriteCloser
-	mutableObject   storj.MutableObject
-	predictedOffset int64
-	FS              *storjFS
+	fs *storjFS
+	// refs is the number of open handles, guarded by fs.mu
+	refs int
+
+	mu     sync.Mutex
+	name   string
+	object storj.Object
+	blocks *blockCache
+	local  *os.File
+	size   int64
+	dirty  bool
+	mtime  time.Time
 
 	nodefs.File
 }
 
-func newStorjFile(ctx context.Context, name string, metainfo storj.Metainfo, streams streams.Store, bucket storj.Bucket, created bool, FS *storjFS) *storjFile {
+func newStorjFile(fs *storjFS, name string) *storjFile {
 	return &storjFile{
-		name:     name,
-		ctx:      ctx,
-		metainfo: metainfo,
-		streams:  streams,
-		bucket:   bucket,
-		mtime:    uint64(time.Now().Unix()),
-		created:  created,
-		FS:       FS,
-		File:     nodefs.NewDefaultFile(),
+		fs:    fs,
+		name:  name,
+		mtime: time.Now(),
+		File:  nodefs.NewDefaultFile(),
 	}
 }
 
 func (f *storjFile) GetAttr(attr *fuse.Attr) fuse.Status {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	zap.S().Debug("GetAttr file: ", f.name)
 
-	if f.created {
-		attr.Mode = fuse.S_IFREG | 0644
-		if f.size != 0 {
-			attr.Size = f.size
-		}
-		attr.Mtime = f.mtime
-		return fuse.OK
+	attr.Owner = *fuse.CurrentOwner()
+	attr.Mode = fuse.S_IFREG | 0644
+	if f.local != nil {
+		attr.Size = uint64(f.size)
+		attr.Mtime = uint64(f.mtime.Unix())
+	} else {
+		attr.Size = uint64(f.object.Size)
+		attr.Mtime = uint64(f.object.Modified.Unix())
 	}
-	return fuse.ENOSYS
+	return fuse.OK
 }
 
 func (f *storjFile) Read(buf []byte, off int64) (res fuse.ReadResult, code fuse.Status) {
-	// Detect if offset was moved manually (e.g. stream rev/fwd)
-	if off != f.predictedOffset {
-		f.closeReader()
-	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
 
-	reader, err := f.getReader(off)
-	if err != nil {
-		if storj.ErrObjectNotFound.Has(err) {
-			return nil, fuse.ENOENT
+	var reader io.ReaderAt = f.local
+	if f.local == nil {
+		blocks, err := f.getBlocks()
+		if err != nil {
+			if storj.ErrObjectNotFound.Has(err) {
+				return nil, fuse.ENOENT
+			}
+			return nil, fuse.EIO
 		}
-		return nil, fuse.EIO
+		reader = blocks
 	}
 
-	n, err := io.ReadFull(reader, buf)
-	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+	n, err := reader.ReadAt(buf, off)
+	if err != nil && err != io.EOF {
+		zap.S().Errorf("error during reading: %v", err)
 		return nil, fuse.EIO
 	}
 
-	f.predictedOffset = off + int64(n)
-
 	return fuse.ReadResultData(buf[:n]), fuse.OK
 }
 
 func (f *storjFile) Write(data []byte, off int64) (uint32, fuse.Status) {
-	writer, err := f.getWriter(off)
-	if err != nil {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.loadLocal(); err != nil {
+		zap.S().Errorf("error during loading file: %v", err)
 		return 0, fuse.EIO
 	}
 
-	zap.S().Debug("Starts writting: ", f.name)
-
-	written, err := writer.Write(data)
+	written, err := f.local.WriteAt(data, off)
 	if err != nil {
 		return 0, fuse.EIO
 	}
 
-	f.size += uint64(written)
-
-	zap.S().Debug("Stops writting: ", f.name)
+	if end := off + int64(written); end > f.size {
+		f.size = end
+	}
+	f.dirty = true
+	f.mtime = time.Now()
 
 	return uint32(written), fuse.OK
 }
 
-func (f *storjFile) getReader(off int64) (io.ReadCloser, error) {
-	if f.reader == nil {
-		readOnlyStream, err := f.metainfo.GetObjectStream(f.ctx, f.bucket.Name, f.name)
-		if err != nil {
-			return nil, err
-		}
+func (f *storjFile) Truncate(size uint64) fuse.Status {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 
-		download := stream.NewDownload(f.ctx, readOnlyStream, f.streams)
-		_, err = download.Seek(off, io.SeekStart)
-		if err != nil {
-			return nil, err
-		}
+	var err error
+	if size == 0 && f.local == nil {
+		// no need to download the content which is dropped anyway
+		err = f.createLocal()
+	} else {
+		err = f.loadLocal()
+	}
+	if err != nil {
+		zap.S().Errorf("error during loading file: %v", err)
+		return fuse.EIO
+	}
 
-		f.reader = download
+	if err := f.local.Truncate(int64(size)); err != nil {
+		return fuse.EIO
 	}
-	return f.reader, nil
+
+	f.size = int64(size)
+	f.dirty = true
+	f.mtime = time.Now()
+
+	return fuse.OK
 }
 
-func (f *storjFile) getWriter(off int64) (io.Writer, error) {
-	if off == 0 {
-		f.size = 0
-		f.closeWriter()
+// Chmod is a no-op as objects don't have permissions
+func (f *storjFile) Chmod(perms uint32) fuse.Status {
+	return fuse.OK
+}
 
-		createInfo := storj.CreateObject{
-			RedundancyScheme: cfg.GetRedundancyScheme(),
-			EncryptionScheme: cfg.GetEncryptionScheme(),
-		}
-		var err error
-		f.mutableObject, err = f.metainfo.CreateObject(f.ctx, f.bucket.Name, f.name, &createInfo)
-		if err != nil {
-			return nil, err
-		}
+// Chown is a no-op as objects don't have owners
+func (f *storjFile) Chown(uid uint32, gid uint32) fuse.Status {
+	return fuse.OK
+}
 
-		mutableStream, err := f.mutableObject.CreateStream(f.ctx)
-		if err != nil {
-			return nil, err
-		}
+func (f *storjFile) Utimens(atime *time.Time, mtime *time.Time) fuse.Status {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 
-		f.writer = stream.NewUpload(f.ctx, mutableStream, f.streams)
+	if mtime != nil {
+		f.mtime = *mtime
 	}
-	return f.writer, nil
+	return fuse.OK
 }
 
 func (f *storjFile) Flush() fuse.Status {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	zap.S().Debug("Flush: ", f.name)
 
-	f.closeReader()
-	f.closeWriter()
+	if err := f.upload(); err != nil {
+		zap.S().Errorf("error during uploading file: %v", err)
+		return fuse.EIO
+	}
 	return fuse.OK
 }
 
-func (f *storjFile) closeReader() {
-	if f.reader != nil {
-		utils.LogClose(f.reader)
-		f.reader = nil
-	}
+func (f *storjFile) Fsync(flags int) fuse.Status {
+	return f.Flush()
+}
+
+func (f *storjFile) Release() {
+	f.fs.releaseFile(f)
 }
 
-func (f *storjFile) closeWriter() {
-	if f.writer != nil {
-		utils.LogClose(f.writer)
-		f.FS.removeCreatedFile(f.name)
-		err := f.mutableObject.Commit(f.ctx)
+// getBlocks returns the block cache for reading the remote object
+func (f *storjFile) getBlocks() (*blockCache, error) {
+	if f.blocks == nil {
+		rr, _, err := f.fs.streams.Get(f.fs.ctx, storj.JoinPaths(f.fs.bucket.Name, f.name), f.fs.bucket.PathCipher)
 		if err != nil {
-			zap.S().Errorf("error during commiting data: %v", err)
+			return nil, err
 		}
-		f.writer = nil
+		f.blocks = newBlockCache(f.fs.ctx, rr, f.fs.readBlockSize, f.fs.readAhead)
+	}
+	return f.blocks, nil
+}
+
+// createLocal creates an empty local copy of the file
+func (f *storjFile) createLocal() (err error) {
+	f.local, err = ioutil.TempFile(f.fs.cacheDir, "file")
+	if err != nil {
+		return err
+	}
+	f.size = 0
+	f.blocks = nil
+	return nil
+}
+
+// loadLocal downloads the remote object into the local copy of the file,
+// unless there is a local copy already
+func (f *storjFile) loadLocal() error {
+	if f.local != nil {
+		return nil
+	}
+
+	if err := f.createLocal(); err != nil {
+		return err
+	}
+
+	// files created in the mount don't exist remotely yet
+	if f.object.Path == "" {
+		return nil
+	}
+
+	readOnlyStream, err := f.fs.metainfo.GetObjectStream(f.fs.ctx, f.fs.bucket.Name, f.name)
+	if err != nil {
+		f.removeLocal()
+		return err
+	}
+
+	download := stream.NewDownload(f.fs.ctx, readOnlyStream, f.fs.streams)
+	f.size, err = io.Copy(f.local, download)
+	err = utils.CombineErrors(err, download.Close())
+	if err != nil {
+		f.removeLocal()
+		return err
+	}
+
+	return nil
+}
+
+// upload uploads the local copy of the file if it was changed
+func (f *storjFile) upload() error {
+	if !f.dirty {
+		return nil
+	}
+
+	createInfo := storj.CreateObject{
+		ContentType: f.object.ContentType,
+		Metadata:    f.object.Metadata,
+		Expires:     f.object.Expires,
+	}
+	err := f.fs.uploadObject(f.name, createInfo, io.NewSectionReader(f.local, 0, f.size))
+	if err != nil {
+		return err
+	}
+	f.dirty = false
+
+	f.object, err = f.fs.metainfo.GetObject(f.fs.ctx, f.fs.bucket.Name, f.name)
+	return err
+}
+
+func (f *storjFile) rename(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.name = name
+	f.blocks = nil
+}
+
+// close uploads pending changes and removes the local copy of the file
+func (f *storjFile) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.upload(); err != nil {
+		zap.S().Errorf("error during uploading file: %v", err)
+	}
+	f.removeLocal()
+}
+
+func (f *storjFile) removeLocal() {
+	if f.local == nil {
+		return
+	}
+	utils.LogClose(f.local)
+	if err := os.Remove(f.local.Name()); err != nil {
+		zap.S().Errorf("error during removing cached file: %v", err)
 	}
+	f.local = nil
+	f.size = 0
 }
@@ -0,0 +1,152 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin netbsd freebsd openbsd
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hanwen/go-fuse/fuse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/stream"
+)
+
+func runMountTest(t *testing.T, test func(ctx *testcontext.Context, sf *storjFS)) {
+	runPlanetTest(t, func(ctx *testcontext.Context, metainfo storj.Metainfo, streams streams.Store, bucket storj.Bucket) {
+		sf := newStorjFS(ctx, metainfo, streams, bucket, ctx.Dir("cache"), 4, 2)
+		test(ctx, sf)
+	})
+}
+
+// readObject downloads the object with the given name
+func readObject(t *testing.T, ctx *testcontext.Context, sf *storjFS, name string) string {
+	readOnlyStream, err := sf.metainfo.GetObjectStream(ctx, sf.bucket.Name, name)
+	require.NoError(t, err)
+
+	download := stream.NewDownload(ctx, readOnlyStream, sf.streams)
+	defer ctx.Check(download.Close)
+
+	data, err := ioutil.ReadAll(download)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func assertNoObject(t *testing.T, ctx *testcontext.Context, sf *storjFS, name string) {
+	_, err := sf.metainfo.GetObject(ctx, sf.bucket.Name, name)
+	assert.True(t, storj.ErrObjectNotFound.Has(err), name)
+}
+
+func assertCacheEmpty(t *testing.T, sf *storjFS) {
+	files, err := ioutil.ReadDir(sf.cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestMountWriteBack(t *testing.T) {
+	runMountTest(t, func(ctx *testcontext.Context, sf *storjFS) {
+		file, code := sf.Create("file.txt", 0, 0644, nil)
+		require.True(t, code.Ok())
+		f := file.(*storjFile)
+
+		// writes at arbitrary offsets stay local until flushed
+		_, code = f.Write([]byte("hello"), 0)
+		require.True(t, code.Ok())
+		_, code = f.Write([]byte("world"), 10)
+		require.True(t, code.Ok())
+
+		var attr fuse.Attr
+		require.True(t, f.GetAttr(&attr).Ok())
+		assert.Equal(t, uint64(15), attr.Size)
+		assertNoObject(t, ctx, sf, "file.txt")
+
+		// fsync uploads the dirty file
+		require.True(t, f.Fsync(0).Ok())
+		assert.Equal(t, "hello\x00\x00\x00\x00\x00world", readObject(t, ctx, sf, "file.txt"))
+
+		// closing the last handle uploads the pending changes and removes
+		// the local copy
+		_, code = f.Write([]byte("HELLO"), 0)
+		require.True(t, code.Ok())
+		f.Release()
+		assert.Equal(t, "HELLO\x00\x00\x00\x00\x00world", readObject(t, ctx, sf, "file.txt"))
+		assert.Nil(t, sf.getOpenFile("file.txt"))
+		assertCacheEmpty(t, sf)
+
+		// the reads of a file without local changes go through the block cache
+		file, code = sf.Open("file.txt", uint32(os.O_RDONLY), nil)
+		require.True(t, code.Ok())
+		f = file.(*storjFile)
+		defer f.Release()
+
+		buf := make([]byte, 5)
+		res, code := f.Read(buf, 10)
+		require.True(t, code.Ok())
+		data, code := res.Bytes(buf)
+		require.True(t, code.Ok())
+		assert.Equal(t, "world", string(data))
+		assert.NotNil(t, f.blocks)
+		assert.Nil(t, f.local)
+	})
+}
+
+func TestMountTruncate(t *testing.T) {
+	runMountTest(t, func(ctx *testcontext.Context, sf *storjFS) {
+		require.NoError(t, sf.uploadObject("file.txt", storj.CreateObject{}, strings.NewReader("0123456789")))
+
+		// truncating keeps the beginning of the remote content
+		require.True(t, sf.Truncate("file.txt", 4, nil).Ok())
+		assert.Equal(t, "0123", readObject(t, ctx, sf, "file.txt"))
+
+		// opening with O_TRUNC drops the content
+		file, code := sf.Open("file.txt", uint32(os.O_WRONLY|os.O_TRUNC), nil)
+		require.True(t, code.Ok())
+		file.Release()
+		assert.Equal(t, "", readObject(t, ctx, sf, "file.txt"))
+		assertCacheEmpty(t, sf)
+
+		assert.Equal(t, fuse.ENOENT, sf.Truncate("missing.txt", 0, nil))
+	})
+}
+
+func TestMountRename(t *testing.T) {
+	runMountTest(t, func(ctx *testcontext.Context, sf *storjFS) {
+		file, code := sf.Create("a.txt", 0, 0644, nil)
+		require.True(t, code.Ok())
+		f := file.(*storjFile)
+		_, code = f.Write([]byte("data"), 0)
+		require.True(t, code.Ok())
+
+		// the local changes of an open file are uploaded and moved
+		require.True(t, sf.Rename("a.txt", "b.txt", nil).Ok())
+		assert.Equal(t, "data", readObject(t, ctx, sf, "b.txt"))
+		assertNoObject(t, ctx, sf, "a.txt")
+
+		// the open file follows the rename
+		assert.Nil(t, sf.getOpenFile("a.txt"))
+		assert.Equal(t, f, sf.getOpenFile("b.txt"))
+		_, code = f.Write([]byte("DATA"), 0)
+		require.True(t, code.Ok())
+		f.Release()
+		assert.Equal(t, "DATA", readObject(t, ctx, sf, "b.txt"))
+		assertNoObject(t, ctx, sf, "a.txt")
+
+		// renaming a directory moves the objects under it
+		require.NoError(t, sf.uploadObject("dir/x", storj.CreateObject{}, strings.NewReader("x")))
+		require.NoError(t, sf.uploadObject("dir/sub/y", storj.CreateObject{}, strings.NewReader("y")))
+		require.True(t, sf.Rename("dir", "moved", nil).Ok())
+		assert.Equal(t, "x", readObject(t, ctx, sf, "moved/x"))
+		assert.Equal(t, "y", readObject(t, ctx, sf, "moved/sub/y"))
+		assertNoObject(t, ctx, sf, "dir/x")
+		assertNoObject(t, ctx, sf, "dir/sub/y")
+	})
+}
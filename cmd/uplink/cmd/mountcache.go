@@ -0,0 +1,147 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin netbsd freebsd openbsd
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"storj.io/storj/pkg/ranger"
+)
+
+// blockCache reads a remote object in fixed size blocks, which are kept in
+// memory, and fetches the blocks following the one being read ahead of time.
+// Reading at arbitrary offsets doesn't restart the download of the object.
+type blockCache struct {
+	ctx       context.Context
+	ranger    ranger.Ranger
+	blockSize int64
+	readAhead int64
+
+	mu     sync.Mutex
+	blocks map[int64]*cachedBlock
+}
+
+// cachedBlock is a block which is being fetched or was fetched already
+type cachedBlock struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+func newBlockCache(ctx context.Context, rr ranger.Ranger, blockSize int64, readAhead int) *blockCache {
+	if blockSize <= 0 {
+		blockSize = 1 << 20
+	}
+	if readAhead < 0 {
+		readAhead = 0
+	}
+	return &blockCache{
+		ctx:       ctx,
+		ranger:    rr,
+		blockSize: blockSize,
+		readAhead: int64(readAhead),
+		blocks:    make(map[int64]*cachedBlock),
+	}
+}
+
+// Size returns the size of the object
+func (cache *blockCache) Size() int64 {
+	return cache.ranger.Size()
+}
+
+// ReadAt implements io.ReaderAt
+func (cache *blockCache) ReadAt(p []byte, off int64) (n int, err error) {
+	size := cache.ranger.Size()
+	for n < len(p) {
+		if off >= size {
+			return n, io.EOF
+		}
+
+		index := off / cache.blockSize
+		block := cache.getBlock(index)
+
+		<-block.done
+		if block.err != nil {
+			cache.forget(index)
+			return n, block.err
+		}
+
+		copied, _ := bytes.NewReader(block.data).ReadAt(p[n:], off-index*cache.blockSize)
+		if copied == 0 {
+			return n, io.ErrUnexpectedEOF
+		}
+		n += copied
+		off += int64(copied)
+	}
+	return n, nil
+}
+
+// getBlock returns the block with the given index, starts fetching it if
+// it's not cached and fetches the next blocks ahead of time. Blocks outside
+// of the read window are dropped from the cache.
+func (cache *blockCache) getBlock(index int64) *cachedBlock {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for cached := range cache.blocks {
+		if cached < index-1 || cached > index+cache.readAhead {
+			delete(cache.blocks, cached)
+		}
+	}
+
+	lastBlock := (cache.ranger.Size() - 1) / cache.blockSize
+	for ahead := index + 1; ahead <= index+cache.readAhead && ahead <= lastBlock; ahead++ {
+		cache.fetch(ahead)
+	}
+
+	return cache.fetch(index)
+}
+
+// fetch starts fetching the block with the given index unless it's cached
+// already. It must be called with the mutex held.
+func (cache *blockCache) fetch(index int64) *cachedBlock {
+	if block, ok := cache.blocks[index]; ok {
+		return block
+	}
+
+	block := &cachedBlock{done: make(chan struct{})}
+	cache.blocks[index] = block
+
+	go func() {
+		defer close(block.done)
+
+		offset := index * cache.blockSize
+		length := cache.blockSize
+		if offset+length > cache.ranger.Size() {
+			length = cache.ranger.Size() - offset
+		}
+
+		reader, err := cache.ranger.Range(cache.ctx, offset, length)
+		if err != nil {
+			block.err = err
+			return
+		}
+		block.data, block.err = ioutil.ReadAll(reader)
+		if err := reader.Close(); err != nil && block.err == nil {
+			block.err = err
+		}
+	}()
+
+	return block
+}
+
+// forget removes the block from the cache, so it's fetched again on the next
+// read
+func (cache *blockCache) forget(index int64) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	delete(cache.blocks, index)
+}
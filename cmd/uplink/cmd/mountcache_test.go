@@ -0,0 +1,128 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin netbsd freebsd openbsd
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/ranger"
+)
+
+// recordingRanger records the ranges fetched from it
+type recordingRanger struct {
+	ranger.Ranger
+
+	mu     sync.Mutex
+	ranges [][2]int64
+	fail   bool
+}
+
+func (rr *recordingRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	rr.mu.Lock()
+	rr.ranges = append(rr.ranges, [2]int64{offset, length})
+	fail := rr.fail
+	rr.mu.Unlock()
+
+	if fail {
+		return nil, errs.New("fetch failed")
+	}
+	return rr.Ranger.Range(ctx, offset, length)
+}
+
+func (rr *recordingRanger) fetched() [][2]int64 {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	return append([][2]int64{}, rr.ranges...)
+}
+
+func cachedIndexes(cache *blockCache) []int64 {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	var indexes []int64
+	for index := range cache.blocks {
+		indexes = append(indexes, index)
+	}
+	return indexes
+}
+
+// waitBlocks waits until the blocks being fetched are done
+func waitBlocks(cache *blockCache) {
+	cache.mu.Lock()
+	var blocks []*cachedBlock
+	for _, block := range cache.blocks {
+		blocks = append(blocks, block)
+	}
+	cache.mu.Unlock()
+	for _, block := range blocks {
+		<-block.done
+	}
+}
+
+func TestBlockCache(t *testing.T) {
+	ctx := context.Background()
+	data := []byte("0123456789abcdefgh")
+	rr := &recordingRanger{Ranger: ranger.ByteRanger(data)}
+	cache := newBlockCache(ctx, rr, 4, 2)
+	assert.Equal(t, int64(len(data)), cache.Size())
+
+	// a read across blocks fetches them and the blocks after them
+	p := make([]byte, 6)
+	n, err := cache.ReadAt(p, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "56789a", string(p[:n]))
+	waitBlocks(cache)
+	// the last block is shorter than the block size
+	assert.ElementsMatch(t, [][2]int64{{4, 4}, {8, 4}, {12, 4}, {16, 2}}, rr.fetched())
+
+	// a read of cached blocks doesn't fetch them again, and drops the blocks
+	// out of the read window
+	n, err = cache.ReadAt(p, 16)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "gh", string(p[:n]))
+	assert.Len(t, rr.fetched(), 4)
+	assert.ElementsMatch(t, []int64{3, 4}, cachedIndexes(cache))
+
+	// a read going back fetches the dropped blocks again
+	n, err = cache.ReadAt(p[:2], 0)
+	require.NoError(t, err)
+	assert.Equal(t, "01", string(p[:n]))
+	waitBlocks(cache)
+	assert.Contains(t, rr.fetched(), [2]int64{0, 4})
+	assert.ElementsMatch(t, []int64{0, 1, 2}, cachedIndexes(cache))
+
+	// a read past the end
+	n, err = cache.ReadAt(p, int64(len(data)))
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestBlockCacheError(t *testing.T) {
+	ctx := context.Background()
+	rr := &recordingRanger{Ranger: ranger.ByteRanger([]byte("0123456789")), fail: true}
+	cache := newBlockCache(ctx, rr, 4, 0)
+
+	p := make([]byte, 4)
+	_, err := cache.ReadAt(p, 0)
+	require.Error(t, err)
+
+	// the failed block isn't cached, so it's fetched again
+	assert.Empty(t, cachedIndexes(cache))
+	rr.mu.Lock()
+	rr.fail = false
+	rr.mu.Unlock()
+
+	n, err := cache.ReadAt(p, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "0123", string(p[:n]))
+	assert.Equal(t, [][2]int64{{0, 4}, {0, 4}}, rr.fetched())
+}
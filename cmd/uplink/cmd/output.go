@@ -0,0 +1,202 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"storj.io/storj/pkg/storj"
+)
+
+var (
+	outputFlag *string
+)
+
+func init() {
+	outputFlag = CLICmd.PersistentFlags().String("output", "text", "output format, either text or json")
+}
+
+// jsonOutput returns whether the results should be printed as JSON
+func jsonOutput() (bool, error) {
+	switch *outputFlag {
+	case "text", "":
+		return false, nil
+	case "json":
+		return true, nil
+	default:
+		return false, fmt.Errorf("Invalid output format %q, use text or json", *outputFlag)
+	}
+}
+
+// printJSON prints v as indented JSON to the standard output
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printOperation prints the result of an operation as JSON when requested,
+// otherwise the text formatted with format and args
+func printOperation(output operationOutput, format string, args ...interface{}) error {
+	if asJSON, _ := jsonOutput(); asJSON {
+		return printJSON(output)
+	}
+	fmt.Printf(format, args...)
+	return nil
+}
+
+// operationOutput is the JSON output of the commands changing buckets or objects
+type operationOutput struct {
+	Operation   string `json:"operation"`
+	Source      string `json:"source,omitempty"`
+	Destination string `json:"destination"`
+}
+
+// bucketOutput is the JSON output of a bucket
+type bucketOutput struct {
+	Name       string         `json:"name"`
+	Created    time.Time      `json:"created"`
+	PathCipher string         `json:"path_cipher"`
+	Objects    []objectOutput `json:"objects,omitempty"`
+}
+
+func newBucketOutput(bucket storj.Bucket) bucketOutput {
+	return bucketOutput{
+		Name:       bucket.Name,
+		Created:    bucket.Created,
+		PathCipher: cipherName(bucket.PathCipher),
+	}
+}
+
+// objectOutput is the JSON output of an object
+type objectOutput struct {
+	Path        string            `json:"path"`
+	IsPrefix    bool              `json:"is_prefix,omitempty"`
+	Size        int64             `json:"size"`
+	ContentType string            `json:"content_type,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Created     *time.Time        `json:"created,omitempty"`
+	Modified    *time.Time        `json:"modified,omitempty"`
+	Expires     *time.Time        `json:"expires,omitempty"`
+	Checksum    string            `json:"checksum,omitempty"`
+	ETag        string            `json:"etag,omitempty"`
+
+	SegmentCount     int64             `json:"segment_count,omitempty"`
+	FixedSegmentSize int64             `json:"fixed_segment_size,omitempty"`
+	Redundancy       *redundancyOutput `json:"redundancy,omitempty"`
+	Encryption       *encryptionOutput `json:"encryption,omitempty"`
+	Compression      string            `json:"compression,omitempty"`
+	Segments         []segmentOutput   `json:"segments,omitempty"`
+}
+
+func newObjectOutput(object storj.Object) objectOutput {
+	if object.IsPrefix {
+		return objectOutput{Path: object.Path, IsPrefix: true}
+	}
+
+	output := objectOutput{
+		Path:             object.Path,
+		Size:             object.Size,
+		ContentType:      object.ContentType,
+		Metadata:         object.Metadata,
+		Created:          optionalTime(object.Created),
+		Modified:         optionalTime(object.Modified),
+		Expires:          optionalTime(object.Expires),
+		Checksum:         hex.EncodeToString(object.Checksum),
+		ETag:             object.ETag,
+		SegmentCount:     object.SegmentCount,
+		FixedSegmentSize: object.FixedSegmentSize,
+		Compression:      object.Compression.String(),
+	}
+
+	if !object.RedundancyScheme.IsZero() {
+		output.Redundancy = &redundancyOutput{
+			ShareSize:      object.RedundancyScheme.ShareSize,
+			RequiredShares: object.RedundancyScheme.RequiredShares,
+			RepairShares:   object.RedundancyScheme.RepairShares,
+			OptimalShares:  object.RedundancyScheme.OptimalShares,
+			TotalShares:    object.RedundancyScheme.TotalShares,
+		}
+	}
+
+	if !object.EncryptionScheme.IsZero() {
+		output.Encryption = &encryptionOutput{
+			Cipher:    cipherName(object.EncryptionScheme.Cipher),
+			BlockSize: object.EncryptionScheme.BlockSize,
+		}
+	}
+
+	return output
+}
+
+// redundancyOutput is the JSON output of a redundancy scheme
+type redundancyOutput struct {
+	ShareSize      int32 `json:"share_size"`
+	RequiredShares int16 `json:"required_shares"`
+	RepairShares   int16 `json:"repair_shares"`
+	OptimalShares  int16 `json:"optimal_shares"`
+	TotalShares    int16 `json:"total_shares"`
+}
+
+// encryptionOutput is the JSON output of an encryption scheme
+type encryptionOutput struct {
+	Cipher    string `json:"cipher"`
+	BlockSize int32  `json:"block_size"`
+}
+
+// segmentOutput is the JSON output of a segment
+type segmentOutput struct {
+	Index    int64         `json:"index"`
+	Size     int64         `json:"size"`
+	Checksum string        `json:"checksum,omitempty"`
+	Inline   bool          `json:"inline"`
+	PieceID  string        `json:"piece_id,omitempty"`
+	Pieces   []pieceOutput `json:"pieces,omitempty"`
+}
+
+func newSegmentOutput(segment storj.Segment) segmentOutput {
+	output := segmentOutput{
+		Index:    segment.Index,
+		Size:     segment.Size,
+		Checksum: hex.EncodeToString(segment.Checksum),
+		Inline:   len(segment.PieceID) == 0,
+		PieceID:  hex.EncodeToString(segment.PieceID),
+	}
+	for _, piece := range segment.Pieces {
+		output.Pieces = append(output.Pieces, pieceOutput{Number: piece.Number, Node: piece.Location})
+	}
+	return output
+}
+
+// pieceOutput is the JSON output of the location of a piece
+type pieceOutput struct {
+	Number byte         `json:"number"`
+	Node   storj.NodeID `json:"node"`
+}
+
+func cipherName(cipher storj.Cipher) string {
+	switch cipher {
+	case storj.Unencrypted:
+		return "none"
+	case storj.AESGCM:
+		return "aesgcm"
+	case storj.SecretBox:
+		return "secretbox"
+	case storj.XChaCha20Poly1305:
+		return "xchacha20poly1305"
+	default:
+		return fmt.Sprintf("unknown(%d)", cipher)
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/teststorj"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+)
+
+func assertJSON(t *testing.T, expected string, v interface{}) {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(data))
+}
+
+func TestJSONOutputFlag(t *testing.T) {
+	old := *outputFlag
+	defer func() { *outputFlag = old }()
+
+	for i, tt := range []struct {
+		flag   string
+		asJSON bool
+		valid  bool
+	}{
+		{"", false, true},
+		{"text", false, true},
+		{"json", true, true},
+		{"yaml", false, false},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		*outputFlag = tt.flag
+		asJSON, err := jsonOutput()
+		assert.Equal(t, tt.asJSON, asJSON, errTag)
+		assert.Equal(t, tt.valid, err == nil, errTag)
+	}
+}
+
+func TestObjectOutputSchema(t *testing.T) {
+	created := time.Date(2018, 12, 1, 10, 0, 0, 0, time.UTC)
+	modified := created.Add(time.Hour)
+
+	assertJSON(t, `{
+		"path": "dir/file.txt",
+		"size": 2048,
+		"content_type": "text/plain",
+		"metadata": {"mtime": "1"},
+		"created": "2018-12-01T10:00:00Z",
+		"modified": "2018-12-01T11:00:00Z",
+		"checksum": "0102ff",
+		"etag": "etag",
+		"segment_count": 2,
+		"fixed_segment_size": 1024,
+		"redundancy": {
+			"share_size": 256,
+			"required_shares": 2,
+			"repair_shares": 3,
+			"optimal_shares": 4,
+			"total_shares": 5
+		},
+		"encryption": {"cipher": "aesgcm", "block_size": 512},
+		"compression": "none"
+	}`, newObjectOutput(storj.Object{
+		Path:        "dir/file.txt",
+		ContentType: "text/plain",
+		Metadata:    map[string]string{"mtime": "1"},
+		Created:     created,
+		Modified:    modified,
+		Stream: storj.Stream{
+			Size:             2048,
+			Checksum:         []byte{1, 2, 255},
+			ETag:             "etag",
+			SegmentCount:     2,
+			FixedSegmentSize: 1024,
+			RedundancyScheme: storj.RedundancyScheme{
+				Algorithm:      storj.ReedSolomon,
+				ShareSize:      256,
+				RequiredShares: 2,
+				RepairShares:   3,
+				OptimalShares:  4,
+				TotalShares:    5,
+			},
+			EncryptionScheme: storj.EncryptionScheme{
+				Cipher:    storj.AESGCM,
+				BlockSize: 512,
+			},
+		},
+	}))
+
+	// the optional fields are omitted
+	assertJSON(t, `{"path": "empty", "size": 0, "compression": "none"}`,
+		newObjectOutput(storj.Object{Path: "empty"}))
+
+	// only the path of prefixes is printed
+	assertJSON(t, `{"path": "dir/", "is_prefix": true, "size": 0}`,
+		newObjectOutput(storj.Object{Path: "dir/", IsPrefix: true, Stream: storj.Stream{Size: 10}}))
+}
+
+func TestSegmentOutputSchema(t *testing.T) {
+	node := teststorj.NodeIDFromString("node")
+
+	assertJSON(t, fmt.Sprintf(`{
+		"index": 1,
+		"size": 1024,
+		"checksum": "abcd",
+		"inline": false,
+		"piece_id": "0102",
+		"pieces": [{"number": 3, "node": %q}]
+	}`, node.String()), newSegmentOutput(storj.Segment{
+		Index:    1,
+		Size:     1024,
+		Checksum: []byte{0xab, 0xcd},
+		PieceID:  storj.PieceID{1, 2},
+		Pieces:   []storj.Piece{{Number: 3, Location: node}},
+	}))
+
+	// segments without a piece ID are inline
+	assertJSON(t, `{"index": 0, "size": 10, "inline": true}`,
+		newSegmentOutput(storj.Segment{Size: 10}))
+}
+
+func TestBucketAndOperationOutputSchema(t *testing.T) {
+	created := time.Date(2018, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	assertJSON(t, `{"name": "bucket", "created": "2018-12-01T10:00:00Z", "path_cipher": "secretbox"}`,
+		newBucketOutput(storj.Bucket{Name: "bucket", Created: created, PathCipher: storj.SecretBox}))
+
+	assertJSON(t, `{"operation": "copy", "source": "a.txt", "destination": "sj://bucket/a.txt"}`,
+		operationOutput{Operation: "copy", Source: "a.txt", Destination: "sj://bucket/a.txt"})
+	assertJSON(t, `{"operation": "remove", "destination": "sj://bucket/a.txt"}`,
+		operationOutput{Operation: "remove", Destination: "sj://bucket/a.txt"})
+
+	assertJSON(t, `{"path": "dir/", "size": 10, "objects": 2}`,
+		usageOutput{Path: "dir/", Size: 10, Objects: 2})
+}
+
+func TestUsageByPrefix(t *testing.T) {
+	runPlanetTest(t, func(ctx *testcontext.Context, metainfo storj.Metainfo, streams streams.Store, bucket storj.Bucket) {
+		for path, size := range map[storj.Path]int{
+			"a":          1,
+			"dir1/b":     2,
+			"dir1/sub/c": 3,
+			"dir2/d":     4,
+		} {
+			uploadTestObject(t, ctx, metainfo, streams, bucket, path, make([]byte, size))
+		}
+
+		root, err := fpath.New("sj://" + testBucket + "/")
+		require.NoError(t, err)
+		usages, err := usageByPrefix(ctx, metainfo, root)
+		require.NoError(t, err)
+		// the objects are summed by first level prefix and in the total
+		assert.Equal(t, []usageOutput{
+			{Path: "dir1/", Size: 5, Objects: 2},
+			{Path: "dir2/", Size: 4, Objects: 1},
+			{Path: root.String(), Size: 10, Objects: 4},
+		}, usages)
+
+		// the prefixes are relative to the listed prefix
+		dir, err := fpath.New("sj://" + testBucket + "/dir1/")
+		require.NoError(t, err)
+		usages, err = usageByPrefix(ctx, metainfo, dir)
+		require.NoError(t, err)
+		assert.Equal(t, []usageOutput{
+			{Path: "sub/", Size: 3, Objects: 1},
+			{Path: dir.String(), Size: 5, Objects: 2},
+		}, usages)
+
+		// an empty prefix only has the total
+		empty, err := fpath.New("sj://" + testBucket + "/missing/")
+		require.NoError(t, err)
+		usages, err = usageByPrefix(ctx, metainfo, empty)
+		require.NoError(t, err)
+		assert.Equal(t, []usageOutput{{Path: empty.String()}}, usages)
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"storj.io/storj/internal/fpath"
 	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storj"
 )
 
 func init() {
@@ -28,6 +29,10 @@ func putMain(cmd *cobra.Command, args []string) (err error) {
 
 	ctx := process.Ctx(cmd)
 
+	if _, err := jsonOutput(); err != nil {
+		return err
+	}
+
 	dst, err := fpath.New(args[0])
 	if err != nil {
 		return err
@@ -42,5 +47,5 @@ func putMain(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
-	return upload(ctx, src, dst, false)
+	return upload(ctx, src, dst, false, storj.CreateObject{})
 }
@@ -24,6 +24,10 @@ func init() {
 func deleteBucket(cmd *cobra.Command, args []string) error {
 	ctx := process.Ctx(cmd)
 
+	if _, err := jsonOutput(); err != nil {
+		return err
+	}
+
 	if len(args) == 0 {
 		return fmt.Errorf("No bucket specified for deletion")
 	}
@@ -60,7 +64,5 @@ func deleteBucket(cmd *cobra.Command, args []string) error {
 		return convertError(err, dst)
 	}
 
-	fmt.Printf("Bucket %s deleted\n", dst.Bucket())
-
-	return nil
+	return printOperation(operationOutput{Operation: "delete-bucket", Destination: dst.String()}, "Bucket %s deleted\n", dst.Bucket())
 }
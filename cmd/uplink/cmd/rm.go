@@ -23,6 +23,10 @@ func init() {
 func deleteObject(cmd *cobra.Command, args []string) error {
 	ctx := process.Ctx(cmd)
 
+	if _, err := jsonOutput(); err != nil {
+		return err
+	}
+
 	if len(args) == 0 {
 		return fmt.Errorf("No object specified for deletion")
 	}
@@ -46,7 +50,5 @@ func deleteObject(cmd *cobra.Command, args []string) error {
 		return convertError(err, dst)
 	}
 
-	fmt.Printf("Deleted %s\n", dst)
-
-	return nil
+	return printOperation(operationOutput{Operation: "delete", Destination: dst.String()}, "Deleted %s\n", dst)
 }
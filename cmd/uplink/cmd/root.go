@@ -14,11 +14,13 @@ import (
 	"storj.io/storj/pkg/miniogw"
 	"storj.io/storj/pkg/storage/streams"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/transport"
 )
 
 // Config is miniogw.Config configuration
 type Config struct {
 	miniogw.Config
+	Transport transport.Config
 }
 
 var cfg Config
@@ -46,6 +48,13 @@ func addCmd(cmd *cobra.Command, root *cobra.Command) *cobra.Command {
 	}
 
 	cfgstruct.Bind(cmd.Flags(), &cfg, cfgstruct.ConfDir(defaultConfDir))
+
+	// configure the connections to the nodes once the configuration is loaded
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		transport.Configure(cfg.Transport)
+		return run(cmd, args)
+	}
 	return cmd
 }
 
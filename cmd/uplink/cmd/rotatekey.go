@@ -0,0 +1,153 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/pkg/metainfo/kvmetainfo"
+	"storj.io/storj/pkg/miniogw"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storj"
+)
+
+var (
+	newKeyFlag    *string
+	newSaltFlag   *string
+	bucketKeyFlag *bool
+)
+
+func init() {
+	rotateKeyCmd := addCmd(&cobra.Command{
+		Use:   "rotate-key [sj://BUCKET]",
+		Short: "Re-encrypt the keys of all buckets with a new root key, or of one bucket with its own key",
+		RunE:  rotateKey,
+	}, CLICmd)
+	newKeyFlag = rotateKeyCmd.Flags().String("new-key", "", "the new passphrase")
+	newSaltFlag = rotateKeyCmd.Flags().String("new-salt", "", "hex encoded salt for the new passphrase, generated if empty")
+	bucketKeyFlag = rotateKeyCmd.Flags().Bool("bucket-key", false, "if true, the new passphrase is used as a bucket specific key")
+}
+
+func rotateKey(cmd *cobra.Command, args []string) error {
+	ctx := process.Ctx(cmd)
+
+	if *newKeyFlag == "" {
+		return fmt.Errorf("No new key specified, use --new-key")
+	}
+
+	var dst fpath.FPath
+	var bucket string
+	if len(args) > 0 {
+		var err error
+		dst, err = fpath.New(args[0])
+		if err != nil {
+			return err
+		}
+
+		if dst.IsLocal() {
+			return fmt.Errorf("No bucket specified, use format sj://bucket/")
+		}
+
+		if dst.Path() != "" {
+			return fmt.Errorf("Nested buckets not supported, use format sj://bucket/")
+		}
+
+		bucket = dst.Bucket()
+	}
+
+	if *bucketKeyFlag && bucket == "" {
+		return fmt.Errorf("No bucket specified for the bucket key, use format sj://bucket/")
+	}
+
+	if !*bucketKeyFlag && bucket != "" {
+		// the other buckets would become unreadable with the new root key
+		return fmt.Errorf("A new root key rotates every bucket, use --bucket-key to rotate only %s", bucket)
+	}
+
+	newEnc := miniogw.EncryptionConfig{
+		Key:              *newKeyFlag,
+		Salt:             *newSaltFlag,
+		DeriveBucketKeys: cfg.Enc.DeriveBucketKeys && !*bucketKeyFlag,
+	}
+
+	var err error
+	if *bucketKeyFlag {
+		// bucket keys are derived with the salt of the root key
+		newEnc.Salt = cfg.Enc.Salt
+	} else {
+		// the bucket specific keys are derived again with the new salt
+		newEnc.BucketKeys = cfg.Enc.BucketKeys
+		if newEnc.Salt == "" {
+			newEnc.Salt, err = generateSalt()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	newKeys, err := newEnc.KeyStore()
+	if err != nil {
+		return err
+	}
+
+	metainfo, _, err := cfg.Metainfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	db, ok := metainfo.(*kvmetainfo.DB)
+	if !ok {
+		return fmt.Errorf("Key rotation is not supported by the metainfo implementation")
+	}
+
+	if *bucketKeyFlag {
+		rotated, err := db.RotateBucketKey(ctx, bucket, newKeys)
+		if err != nil {
+			return convertError(err, dst)
+		}
+
+		fmt.Printf("Rotated keys of %d objects in bucket %s\n", rotated, bucket)
+		fmt.Printf("Add %s=<new key> to enc.bucket-keys to access the bucket\n", bucket)
+		return nil
+	}
+
+	buckets, err := listBucketNames(ctx, metainfo)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range buckets {
+		rotated, err := db.RotateBucketKey(ctx, name, newKeys)
+		if err != nil {
+			fmt.Printf("Rotating bucket %s failed, run again with --new-salt %s to resume\n", name, newEnc.Salt)
+			return err
+		}
+		fmt.Printf("Rotated keys of %d objects in bucket %s\n", rotated, name)
+	}
+
+	fmt.Printf("Set enc.key to the new key and enc.salt to %s to access the buckets\n", newEnc.Salt)
+	return nil
+}
+
+// listBucketNames returns the names of all buckets
+func listBucketNames(ctx context.Context, metainfo storj.Metainfo) (names []string, err error) {
+	startAfter := ""
+	for {
+		list, err := metainfo.ListBuckets(ctx, storj.BucketListOptions{Direction: storj.After, Cursor: startAfter})
+		if err != nil {
+			return nil, err
+		}
+		for _, bucket := range list.Items {
+			names = append(names, bucket.Name)
+		}
+		if !list.More || len(list.Items) == 0 {
+			return names, nil
+		}
+		startAfter = list.Items[len(list.Items)-1].Name
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestListBucketNames(t *testing.T) {
+	runPlanetTest(t, func(ctx *testcontext.Context, metainfo storj.Metainfo, streams streams.Store, bucket storj.Bucket) {
+		for _, name := range []string{"a-bucket", "z-bucket"} {
+			_, err := metainfo.CreateBucket(ctx, name, &bucket)
+			require.NoError(t, err)
+		}
+
+		names, err := listBucketNames(ctx, metainfo)
+		require.NoError(t, err)
+		// the root key rotation covers every bucket
+		assert.Equal(t, []string{"a-bucket", testBucket, "z-bucket"}, names)
+	})
+}
@@ -5,6 +5,7 @@ package cmd
 
 import (
 	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -112,6 +113,11 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
+	encSalt, err := generateSalt()
+	if err != nil {
+		return err
+	}
+
 	o := map[string]interface{}{
 		"identity.cert-path":     setupCfg.Identity.CertPath,
 		"identity.key-path":      setupCfg.Identity.KeyPath,
@@ -121,11 +127,23 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 		"minio.access-key":       accessKey,
 		"minio.secret-key":       secretKey,
 		"enc.key":                setupCfg.EncKey,
+		"enc.salt":               encSalt,
 	}
 
 	return process.SaveConfig(runCmd.Flags(), filepath.Join(setupDir, "config.yaml"), o)
 }
 
+// generateSalt returns a random salt for deriving the root key from the
+// passphrase
+func generateSalt() (salt string, err error) {
+	var buf [32]byte
+	_, err = rand.Read(buf[:])
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
+
 func generateAWSKey() (key string, err error) {
 	var buf [20]byte
 	_, err = rand.Read(buf[:])
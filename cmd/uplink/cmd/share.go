@@ -0,0 +1,137 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/pkg/linksharing"
+	"storj.io/storj/pkg/process"
+)
+
+var (
+	shareSecret    *string
+	shareExpires   *time.Duration
+	shareBaseURL   *string
+	linkSecret     *string
+	linkServerAddr *string
+	linkBuckets    *[]string
+)
+
+func init() {
+	shareCmd := addCmd(&cobra.Command{
+		Use:   "share",
+		Short: "Create a read-only link sharing an object or prefix",
+		RunE:  shareLink,
+	}, CLICmd)
+	shareSecret = shareCmd.Flags().String("secret", "", "hex encoded secret of the link sharing server")
+	shareExpires = shareCmd.Flags().Duration("expires", 0, "how long the link is valid, forever if zero")
+	shareBaseURL = shareCmd.Flags().String("base-url", "http://localhost:8081", "URL of the link sharing server")
+
+	linkSharingCmd := addCmd(&cobra.Command{
+		Use:   "linksharing",
+		Short: "Serve objects of shared links over HTTP",
+		Long: "Serve objects of shared links over HTTP. The links don't carry any credentials: " +
+			"the server reads the objects with the API key and encryption key of this uplink " +
+			"and checks the bucket, prefix and expiration of the links itself. Anyone with the " +
+			"secret can share any object of the served buckets.",
+		RunE: serveLinkSharing,
+	}, CLICmd)
+	linkSecret = linkSharingCmd.Flags().String("secret", "", "hex encoded secret for signing the links")
+	linkServerAddr = linkSharingCmd.Flags().String("addr", "localhost:8081", "address to serve the shared links on")
+	linkBuckets = linkSharingCmd.Flags().StringSlice("buckets", nil, "buckets whose objects can be shared")
+}
+
+func decodeLinkSecret(secret string) ([]byte, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("No secret specified, use --secret")
+	}
+	decoded, err := hex.DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid secret: %v", err)
+	}
+	return decoded, nil
+}
+
+func shareLink(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("No object or prefix specified for sharing")
+	}
+
+	src, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+	if src.IsLocal() {
+		return fmt.Errorf("No bucket specified, use format sj://bucket/")
+	}
+
+	secret, err := decodeLinkSecret(*shareSecret)
+	if err != nil {
+		return err
+	}
+
+	access := linksharing.Access{
+		Bucket: src.Bucket(),
+		Prefix: strings.TrimSuffix(src.Path(), "/"),
+	}
+	if *shareExpires > 0 {
+		access.Expires = time.Now().Add(*shareExpires)
+	}
+
+	// the handler isn't serving anything, it only creates the link
+	link, err := linksharing.NewHandler(nil, nil, secret, nil, zap.L()).Link(access, src.Path())
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(strings.TrimSuffix(*shareBaseURL, "/") + link)
+	return nil
+}
+
+func serveLinkSharing(cmd *cobra.Command, args []string) error {
+	ctx := process.Ctx(cmd)
+
+	secret, err := decodeLinkSecret(*linkSecret)
+	if err != nil {
+		return err
+	}
+
+	if len(*linkBuckets) == 0 {
+		return fmt.Errorf("No buckets specified, use --buckets")
+	}
+
+	metainfo, streams, err := cfg.Metainfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", *linkServerAddr)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Handler: linksharing.NewHandler(metainfo, streams, secret, *linkBuckets, zap.L())}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	fmt.Printf("Serving shared links on http://%s/\n", listener.Addr())
+
+	err = server.Serve(listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
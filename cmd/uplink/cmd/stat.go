@@ -0,0 +1,116 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/pkg/process"
+)
+
+func init() {
+	addCmd(&cobra.Command{
+		Use:   "stat",
+		Short: "Show detailed information about an object",
+		RunE:  statObject,
+	}, CLICmd)
+}
+
+func statObject(cmd *cobra.Command, args []string) error {
+	ctx := process.Ctx(cmd)
+
+	asJSON, err := jsonOutput()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("No object specified")
+	}
+
+	src, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+
+	if src.IsLocal() {
+		return fmt.Errorf("No bucket specified, use format sj://bucket/")
+	}
+
+	metainfo, _, err := cfg.Metainfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	readOnlyStream, err := metainfo.GetObjectStream(ctx, src.Bucket(), src.Path())
+	if err != nil {
+		return convertError(err, src)
+	}
+
+	output := newObjectOutput(readOnlyStream.Info())
+
+	for index, more := int64(0), true; more; {
+		segments, hasMore, err := readOnlyStream.Segments(ctx, index, 0)
+		if err != nil {
+			return convertError(err, src)
+		}
+		for _, segment := range segments {
+			output.Segments = append(output.Segments, newSegmentOutput(segment))
+		}
+		index += int64(len(segments))
+		more = hasMore && len(segments) > 0
+	}
+
+	if asJSON {
+		return printJSON(output)
+	}
+
+	fmt.Printf("Path:           %s\n", src)
+	fmt.Printf("Size:           %d\n", output.Size)
+	fmt.Printf("Content type:   %s\n", output.ContentType)
+	if output.Created != nil {
+		fmt.Printf("Created:        %s\n", formatTime(*output.Created))
+	}
+	if output.Modified != nil {
+		fmt.Printf("Modified:       %s\n", formatTime(*output.Modified))
+	}
+	if output.Expires != nil {
+		fmt.Printf("Expires:        %s\n", formatTime(*output.Expires))
+	}
+	fmt.Printf("Checksum:       %s\n", output.Checksum)
+	fmt.Printf("ETag:           %s\n", output.ETag)
+	for key, value := range output.Metadata {
+		fmt.Printf("Metadata:       %s=%s\n", key, value)
+	}
+	if output.Redundancy != nil {
+		fmt.Printf("Redundancy:     %d/%d/%d/%d, share size %d\n",
+			output.Redundancy.RequiredShares, output.Redundancy.RepairShares,
+			output.Redundancy.OptimalShares, output.Redundancy.TotalShares,
+			output.Redundancy.ShareSize)
+	}
+	if output.Encryption != nil {
+		fmt.Printf("Encryption:     %s, block size %d\n", output.Encryption.Cipher, output.Encryption.BlockSize)
+	}
+	fmt.Printf("Compression:    %s\n", output.Compression)
+	fmt.Printf("Segments:       %d\n", output.SegmentCount)
+
+	for _, segment := range output.Segments {
+		if segment.Inline {
+			fmt.Printf("  %4d %12d inline\n", segment.Index, segment.Size)
+			continue
+		}
+
+		var pieces []string
+		for _, piece := range segment.Pieces {
+			pieces = append(pieces, fmt.Sprintf("%d:%s", piece.Number, piece.Node))
+		}
+		fmt.Printf("  %4d %12d %s %s\n", segment.Index, segment.Size, segment.PieceID, strings.Join(pieces, " "))
+	}
+
+	return nil
+}
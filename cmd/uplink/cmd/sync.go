@@ -0,0 +1,432 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/stream"
+	"storj.io/storj/pkg/utils"
+)
+
+// mtimeMetadataKey is the object metadata key storing the modification time
+// of the local file, in nanoseconds since the epoch
+const mtimeMetadataKey = "mtime"
+
+var (
+	syncDelete      *bool
+	syncDryRun      *bool
+	syncChecksum    *bool
+	syncInclude     *[]string
+	syncExclude     *[]string
+	syncParallelism *int
+)
+
+func init() {
+	syncCmd := addCmd(&cobra.Command{
+		Use:   "sync",
+		Short: "Synchronizes a local directory with a Storj prefix in either direction",
+		RunE:  syncMain,
+	}, CLICmd)
+	syncDelete = syncCmd.Flags().Bool("delete", false, "if true, delete files in the destination which don't exist in the source")
+	syncDryRun = syncCmd.Flags().Bool("dry-run", false, "if true, only print what would be transferred or deleted")
+	syncChecksum = syncCmd.Flags().Bool("checksum", false, "if true, compare the content checksums instead of modification times")
+	syncInclude = syncCmd.Flags().StringSlice("include", nil, "glob patterns of the files to synchronize, all files if empty")
+	syncExclude = syncCmd.Flags().StringSlice("exclude", nil, "glob patterns of the files not to synchronize")
+	syncParallelism = syncCmd.Flags().Int("parallelism", 4, "number of files transferred in parallel")
+}
+
+// syncEntry is a file or an object found while synchronizing
+type syncEntry struct {
+	size     int64
+	mtime    time.Time
+	checksum []byte
+}
+
+// syncMain is the function executed when syncCmd is called
+func syncMain(cmd *cobra.Command, args []string) (err error) {
+	if len(args) == 0 {
+		return fmt.Errorf("No source specified for sync")
+	}
+	if len(args) == 1 {
+		return fmt.Errorf("No destination specified")
+	}
+
+	ctx := process.Ctx(cmd)
+
+	src, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+
+	dst, err := fpath.New(args[1])
+	if err != nil {
+		return err
+	}
+
+	if src.IsLocal() == dst.IsLocal() {
+		return fmt.Errorf("sync is only supported between a local directory and a Storj URL")
+	}
+
+	metainfo, streams, err := cfg.Metainfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	if src.IsLocal() {
+		return syncUpload(ctx, metainfo, streams, src, dst)
+	}
+	return syncDownload(ctx, metainfo, streams, src, dst)
+}
+
+// syncUpload uploads the changed files of the local directory src to the
+// Storj prefix dst
+func syncUpload(ctx context.Context, metainfo storj.Metainfo, streams streams.Store, src fpath.FPath, dst fpath.FPath) error {
+	local, err := listLocalFiles(src.Path())
+	if err != nil {
+		return err
+	}
+
+	remote, err := listRemoteObjects(ctx, metainfo, dst)
+	if err != nil {
+		return convertError(err, dst)
+	}
+
+	var tasks []func() error
+	for _, name := range sortedNames(local) {
+		name, file := name, local[name]
+		if object, ok := remote[name]; ok && !syncChanged(file, object, filepath.Join(src.Path(), filepath.FromSlash(name))) {
+			continue
+		}
+
+		tasks = append(tasks, func() error {
+			target := dst.Join(name)
+			if *syncDryRun {
+				fmt.Printf("Would upload %s\n", target)
+				return nil
+			}
+			if err := syncUploadFile(ctx, metainfo, streams, filepath.Join(src.Path(), filepath.FromSlash(name)), target, file); err != nil {
+				return convertError(err, target)
+			}
+			fmt.Printf("Uploaded %s\n", target)
+			return nil
+		})
+	}
+
+	if *syncDelete {
+		for _, name := range sortedNames(remote) {
+			if _, ok := local[name]; ok {
+				continue
+			}
+			name := name
+
+			tasks = append(tasks, func() error {
+				target := dst.Join(name)
+				if *syncDryRun {
+					fmt.Printf("Would delete %s\n", target)
+					return nil
+				}
+				if err := metainfo.DeleteObject(ctx, target.Bucket(), target.Path()); err != nil {
+					return convertError(err, target)
+				}
+				fmt.Printf("Deleted %s\n", target)
+				return nil
+			})
+		}
+	}
+
+	return runParallel(*syncParallelism, tasks)
+}
+
+// syncDownload downloads the changed objects of the Storj prefix src to the
+// local directory dst
+func syncDownload(ctx context.Context, metainfo storj.Metainfo, streams streams.Store, src fpath.FPath, dst fpath.FPath) error {
+	remote, err := listRemoteObjects(ctx, metainfo, src)
+	if err != nil {
+		return convertError(err, src)
+	}
+
+	local, err := listLocalFiles(dst.Path())
+	if err != nil {
+		return err
+	}
+
+	var tasks []func() error
+	for _, name := range sortedNames(remote) {
+		name, object := name, remote[name]
+		target := filepath.Join(dst.Path(), filepath.FromSlash(name))
+		if file, ok := local[name]; ok && !syncChanged(file, object, target) {
+			continue
+		}
+
+		tasks = append(tasks, func() error {
+			if *syncDryRun {
+				fmt.Printf("Would download %s\n", target)
+				return nil
+			}
+			source := src.Join(name)
+			if err := syncDownloadObject(ctx, metainfo, streams, source, target, object); err != nil {
+				return convertError(err, source)
+			}
+			fmt.Printf("Downloaded %s\n", target)
+			return nil
+		})
+	}
+
+	if *syncDelete {
+		for _, name := range sortedNames(local) {
+			if _, ok := remote[name]; ok {
+				continue
+			}
+			target := filepath.Join(dst.Path(), filepath.FromSlash(name))
+
+			tasks = append(tasks, func() error {
+				if *syncDryRun {
+					fmt.Printf("Would delete %s\n", target)
+					return nil
+				}
+				if err := os.Remove(target); err != nil {
+					return err
+				}
+				fmt.Printf("Deleted %s\n", target)
+				return nil
+			})
+		}
+	}
+
+	return runParallel(*syncParallelism, tasks)
+}
+
+// syncChanged returns whether the local file differs from the object. The
+// checksum of the local file at localPath is computed only if requested.
+func syncChanged(file, object syncEntry, localPath string) bool {
+	if file.size != object.size {
+		return true
+	}
+
+	if *syncChecksum && len(object.checksum) > 0 {
+		checksum, err := fileChecksum(localPath)
+		if err != nil {
+			return true
+		}
+		return !bytes.Equal(checksum, object.checksum)
+	}
+
+	return !file.mtime.Equal(object.mtime)
+}
+
+func syncUploadFile(ctx context.Context, metainfo storj.Metainfo, streams streams.Store, src string, dst fpath.FPath, file syncEntry) error {
+	reader, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(reader)
+
+	createInfo := storj.CreateObject{
+		Metadata: map[string]string{
+			mtimeMetadataKey: strconv.FormatInt(file.mtime.UnixNano(), 10),
+		},
+	}
+	obj, err := metainfo.CreateObject(ctx, dst.Bucket(), dst.Path(), &createInfo)
+	if err != nil {
+		return err
+	}
+
+	err = uploadStream(ctx, streams, obj, reader)
+	if err != nil {
+		return err
+	}
+
+	return obj.Commit(ctx)
+}
+
+func syncDownloadObject(ctx context.Context, metainfo storj.Metainfo, streams streams.Store, src fpath.FPath, dst string, object syncEntry) (err error) {
+	readOnlyStream, err := metainfo.GetObjectStream(ctx, src.Bucket(), src.Path())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	download := stream.NewDownload(ctx, readOnlyStream, streams)
+	defer utils.LogClose(download)
+
+	file, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, download)
+	err = utils.CombineErrors(err, file.Close())
+	if err != nil {
+		// don't leave partial or corrupted content behind
+		return utils.CombineErrors(err, os.Remove(dst))
+	}
+
+	return os.Chtimes(dst, object.mtime, object.mtime)
+}
+
+// listLocalFiles returns the regular files under root matching the include
+// and exclude patterns, keyed by their slash separated path relative to root
+func listLocalFiles(root string) (map[string]syncEntry, error) {
+	files := make(map[string]syncEntry)
+
+	err := filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && filePath == root {
+				// the destination directory is created when downloading
+				return nil
+			}
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !syncIncluded(name) {
+			return nil
+		}
+
+		files[name] = syncEntry{
+			size:  info.Size(),
+			mtime: info.ModTime(),
+		}
+		return nil
+	})
+
+	return files, err
+}
+
+// listRemoteObjects returns the objects under prefix matching the include
+// and exclude patterns, keyed by their path relative to prefix
+func listRemoteObjects(ctx context.Context, metainfo storj.Metainfo, prefix fpath.FPath) (map[string]syncEntry, error) {
+	objects := make(map[string]syncEntry)
+
+	err := walkObjects(ctx, metainfo, prefix, true, func(object storj.Object) error {
+		// skip directory markers, e.g. created by uplink mount
+		if object.IsPrefix || object.Path == "" || object.ContentType == "application/directory" {
+			return nil
+		}
+		if !syncIncluded(object.Path) {
+			return nil
+		}
+
+		mtime := object.Modified
+		if nanos, err := strconv.ParseInt(object.Metadata[mtimeMetadataKey], 10, 64); err == nil {
+			mtime = time.Unix(0, nanos)
+		}
+
+		objects[object.Path] = syncEntry{
+			size:     object.Size,
+			mtime:    mtime,
+			checksum: object.Checksum,
+		}
+		return nil
+	})
+
+	return objects, err
+}
+
+// syncIncluded returns whether the file with the slash separated name is
+// synchronized. Patterns are matched both against the whole name and its
+// base name.
+func syncIncluded(name string) bool {
+	matches := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+			if ok, _ := path.Match(pattern, path.Base(name)); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(*syncInclude) > 0 && !matches(*syncInclude) {
+		return false
+	}
+	return !matches(*syncExclude)
+}
+
+func fileChecksum(name string) ([]byte, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.LogClose(file)
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+func sortedNames(entries map[string]syncEntry) []string {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runParallel runs the tasks with at most parallelism of them at a time and
+// returns the combined errors of all of them
+func runParallel(parallelism int, tasks []func() error) error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var mu sync.Mutex
+	var group utils.ErrorGroup
+	var wg sync.WaitGroup
+
+	limiter := make(chan struct{}, parallelism)
+	for _, task := range tasks {
+		task := task
+		limiter <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-limiter
+				wg.Done()
+			}()
+
+			if err := task(); err != nil {
+				mu.Lock()
+				group.Add(err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return group.Finish()
+}
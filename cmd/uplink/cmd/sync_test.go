@@ -0,0 +1,218 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+)
+
+// saveSyncFlags returns a function restoring the current sync flags
+func saveSyncFlags() func() {
+	include, exclude := *syncInclude, *syncExclude
+	checksum, delete := *syncChecksum, *syncDelete
+	return func() {
+		*syncInclude, *syncExclude = include, exclude
+		*syncChecksum, *syncDelete = checksum, delete
+	}
+}
+
+func localPath(t *testing.T, dir string) fpath.FPath {
+	p, err := fpath.New(dir)
+	require.NoError(t, err)
+	return p
+}
+
+func TestSyncIncluded(t *testing.T) {
+	defer saveSyncFlags()()
+
+	for i, tt := range []struct {
+		include  []string
+		exclude  []string
+		name     string
+		included bool
+	}{
+		{nil, nil, "a.txt", true},
+		{nil, nil, "dir/a.txt", true},
+		{[]string{"*.txt"}, nil, "a.txt", true},
+		{[]string{"*.txt"}, nil, "a.jpg", false},
+		// the patterns match the base name as well as the whole name
+		{[]string{"*.txt"}, nil, "dir/a.txt", true},
+		{[]string{"dir/*"}, nil, "dir/a.txt", true},
+		{[]string{"dir/*"}, nil, "other/a.txt", false},
+		{nil, []string{"*.tmp"}, "a.tmp", false},
+		{nil, []string{"*.tmp"}, "dir/a.tmp", false},
+		{nil, []string{"*.tmp"}, "a.txt", true},
+		// exclusions take precedence over inclusions
+		{[]string{"*.txt"}, []string{"secret.txt"}, "secret.txt", false},
+		{[]string{"*.txt"}, []string{"secret.txt"}, "public.txt", true},
+		{[]string{"*.txt", "*.jpg"}, nil, "a.jpg", true},
+		// malformed patterns never match
+		{[]string{"["}, nil, "a.txt", false},
+		{nil, []string{"["}, "a.txt", true},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		*syncInclude, *syncExclude = tt.include, tt.exclude
+		assert.Equal(t, tt.included, syncIncluded(tt.name), errTag)
+	}
+}
+
+func TestSyncChanged(t *testing.T) {
+	defer saveSyncFlags()()
+
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	content := []byte("content")
+	filePath := ctx.File("file.txt")
+	require.NoError(t, ioutil.WriteFile(filePath, content, 0644))
+	checksum := sha256.Sum256(content)
+
+	now := time.Now()
+	later := now.Add(time.Second)
+	file := syncEntry{size: int64(len(content)), mtime: now}
+
+	for i, tt := range []struct {
+		checksum bool
+		object   syncEntry
+		filePath string
+		changed  bool
+	}{
+		{false, syncEntry{size: 7, mtime: now}, filePath, false},
+		{false, syncEntry{size: 8, mtime: now}, filePath, true},
+		{false, syncEntry{size: 7, mtime: later}, filePath, true},
+		// the checksums are ignored unless requested
+		{false, syncEntry{size: 7, mtime: now, checksum: []byte("other")}, filePath, false},
+		// the checksums replace the modification times when requested
+		{true, syncEntry{size: 7, mtime: later, checksum: checksum[:]}, filePath, false},
+		{true, syncEntry{size: 7, mtime: now, checksum: []byte("other")}, filePath, true},
+		// the sizes are compared first
+		{true, syncEntry{size: 8, mtime: now, checksum: checksum[:]}, filePath, true},
+		// the modification times are compared for objects without checksums
+		{true, syncEntry{size: 7, mtime: now}, filePath, false},
+		{true, syncEntry{size: 7, mtime: later}, filePath, true},
+		// an unreadable local file is changed
+		{true, syncEntry{size: 7, mtime: now, checksum: checksum[:]}, ctx.File("missing.txt"), true},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		*syncChecksum = tt.checksum
+		assert.Equal(t, tt.changed, syncChanged(file, tt.object, tt.filePath), errTag)
+	}
+}
+
+func TestSyncDelete(t *testing.T) {
+	defer saveSyncFlags()()
+
+	runPlanetTest(t, func(ctx *testcontext.Context, metainfo storj.Metainfo, streams streams.Store, bucket storj.Bucket) {
+		remote, err := fpath.New("sj://" + testBucket + "/dir/")
+		require.NoError(t, err)
+
+		localDir := ctx.Dir("upload")
+		writeLocalFiles(t, localDir, "a.txt", "sub/b.txt")
+		require.NoError(t, syncUpload(ctx, metainfo, streams, localPath(t, localDir), remote))
+
+		// a file removed locally is only deleted remotely with --delete
+		require.NoError(t, os.Remove(filepath.Join(localDir, "a.txt")))
+		require.NoError(t, syncUpload(ctx, metainfo, streams, localPath(t, localDir), remote))
+		assert.Equal(t, []string{"a.txt", "sub/b.txt"}, listRemoteNames(t, ctx, metainfo, remote))
+
+		*syncDelete = true
+		require.NoError(t, syncUpload(ctx, metainfo, streams, localPath(t, localDir), remote))
+		assert.Equal(t, []string{"sub/b.txt"}, listRemoteNames(t, ctx, metainfo, remote))
+
+		// a file missing remotely is deleted locally with --delete
+		downloadDir := ctx.Dir("download")
+		writeLocalFiles(t, downloadDir, "extra.txt")
+		require.NoError(t, syncDownload(ctx, metainfo, streams, remote, localPath(t, downloadDir)))
+		assert.Equal(t, []string{"sub/b.txt"}, listLocalNames(t, downloadDir))
+
+		data, err := ioutil.ReadFile(filepath.Join(downloadDir, "sub", "b.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "sub/b.txt", string(data))
+	})
+}
+
+// writeLocalFiles creates the files under dir with their names as content
+func writeLocalFiles(t *testing.T, dir string, names ...string) {
+	for _, name := range names {
+		localPath := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(localPath), 0755))
+		require.NoError(t, ioutil.WriteFile(localPath, []byte(name), 0644))
+	}
+}
+
+func listRemoteNames(t *testing.T, ctx *testcontext.Context, metainfo storj.Metainfo, prefix fpath.FPath) []string {
+	objects, err := listRemoteObjects(ctx, metainfo, prefix)
+	require.NoError(t, err)
+	return sortedNames(objects)
+}
+
+func listLocalNames(t *testing.T, dir string) []string {
+	files, err := listLocalFiles(dir)
+	require.NoError(t, err)
+	return sortedNames(files)
+}
+
+func TestRunParallel(t *testing.T) {
+	var mu sync.Mutex
+	var ran []int
+	var running, maxRunning int
+
+	var tasks []func() error
+	for i := 0; i < 10; i++ {
+		i := i
+		tasks = append(tasks, func() error {
+			mu.Lock()
+			ran = append(ran, i)
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+
+			if i%4 == 1 {
+				return fmt.Errorf("task %d failed", i)
+			}
+			return nil
+		})
+	}
+
+	err := runParallel(3, tasks)
+
+	// the failing tasks don't stop the others
+	sort.Ints(ran)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, ran)
+	assert.True(t, maxRunning <= 3, "%d tasks ran at once", maxRunning)
+
+	// the errors of all the failing tasks are returned
+	require.Error(t, err)
+	for _, i := range []int{1, 5, 9} {
+		assert.True(t, strings.Contains(err.Error(), fmt.Sprintf("task %d failed", i)), err.Error())
+	}
+
+	assert.NoError(t, runParallel(0, nil))
+	assert.NoError(t, runParallel(0, []func() error{func() error { return nil }}))
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/webdav"
+)
+
+var (
+	webdavAddr *string
+)
+
+func init() {
+	webdavCmd := addCmd(&cobra.Command{
+		Use:   "webdav",
+		Short: "Serve a bucket over WebDAV",
+		RunE:  serveWebDAV,
+	}, CLICmd)
+	webdavAddr = webdavCmd.Flags().String("addr", "localhost:8080", "address to serve WebDAV on")
+}
+
+func serveWebDAV(cmd *cobra.Command, args []string) (err error) {
+	if len(args) == 0 {
+		return fmt.Errorf("No bucket specified for serving")
+	}
+
+	ctx := process.Ctx(cmd)
+
+	src, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+	if src.IsLocal() {
+		return fmt.Errorf("No bucket specified. Use format sj://bucket/")
+	}
+
+	metainfo, streams, err := cfg.Metainfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	bucket, err := metainfo.GetBucket(ctx, src.Bucket())
+	if err != nil {
+		return convertError(err, src)
+	}
+
+	fs := webdav.NewFileSystem(metainfo, streams, bucket)
+
+	listener, err := net.Listen("tcp", *webdavAddr)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Handler: webdav.NewHandler(fs, zap.L())}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	fmt.Printf("Serving %s on http://%s/\n", src, listener.Addr())
+
+	err = server.Serve(listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
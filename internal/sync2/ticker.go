@@ -0,0 +1,76 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Ticker implements a ticker whose interval can be changed while it's waited on
+type Ticker struct {
+	mu     sync.Mutex
+	ticker *time.Ticker
+	// reset is closed when the interval changes
+	reset chan struct{}
+	// trigger ends the pending wait early
+	trigger chan struct{}
+}
+
+// NewTicker returns a new Ticker ticking every interval
+func NewTicker(interval time.Duration) *Ticker {
+	return &Ticker{
+		ticker:  time.NewTicker(interval),
+		reset:   make(chan struct{}),
+		trigger: make(chan struct{}, 1),
+	}
+}
+
+// Wait waits for the next tick, or returns the error of the context when it's
+// canceled. The tick is restarted with the new interval when it changes.
+// Wait returns early when the ticker was triggered.
+func (ticker *Ticker) Wait(ctx context.Context) error {
+	for {
+		ticker.mu.Lock()
+		tick, reset := ticker.ticker.C, ticker.reset
+		ticker.mu.Unlock()
+
+		select {
+		case <-tick:
+			return nil
+		case <-ticker.trigger:
+			return nil
+		case <-reset:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// SetInterval changes the interval of the ticker
+func (ticker *Ticker) SetInterval(interval time.Duration) {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+	ticker.ticker.Stop()
+	ticker.ticker = time.NewTicker(interval)
+	close(ticker.reset)
+	ticker.reset = make(chan struct{})
+}
+
+// Trigger ends the pending or the next wait without waiting for the tick.
+// Triggers before the wait ends are coalesced.
+func (ticker *Ticker) Trigger() {
+	select {
+	case ticker.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Stop stops the ticker
+func (ticker *Ticker) Stop() {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+	ticker.ticker.Stop()
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTickerSetInterval(t *testing.T) {
+	ticker := NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- ticker.Wait(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+	ticker.SetInterval(time.Millisecond)
+
+	if err := <-done; err != nil {
+		t.Fatalf("ticker didn't tick with the new interval: %v", err)
+	}
+}
+
+func TestTickerCancel(t *testing.T) {
+	ticker := NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := ticker.Wait(ctx); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTickerTrigger(t *testing.T) {
+	ticker := NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ticker.Trigger()
+	ticker.Trigger()
+
+	if err := ticker.Wait(ctx); err != nil {
+		t.Fatalf("ticker didn't return after trigger: %v", err)
+	}
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer waitCancel()
+	if err := ticker.Wait(waitCtx); err != context.DeadlineExceeded {
+		t.Fatalf("expected the triggers to be coalesced, got %v", err)
+	}
+}
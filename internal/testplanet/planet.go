@@ -106,6 +106,7 @@ func New(t zaptest.TestingT, satelliteCount, storageNodeCount, uplinkCount int)
 				Overlay:              true,
 			},
 			node.Identity)
+		pointerServer.SetExpirationIndex(pointerdb.NewExpirationIndex(teststore.New()))
 		pb.RegisterPointerDBServer(node.Provider.GRPC(), pointerServer)
 		// bootstrap satellite kademlia node
 		go func(n *Node) {
@@ -72,6 +72,7 @@ func (t *tally) Run(ctx context.Context) (err error) {
 func (t *tally) calculateAtRestData(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
 	var nodeData = make(map[storj.NodeID]int64)
+	now := time.Now()
 	err = t.pointerdb.Iterate(ctx, &pb.IterateRequest{Recurse: true},
 		func(it storage.Iterator) error {
 			var item storage.ListItem
@@ -81,6 +82,10 @@ func (t *tally) calculateAtRestData(ctx context.Context) (err error) {
 				if err != nil {
 					return Error.Wrap(err)
 				}
+				// expired segments are deleted by the expiration reaper
+				if pointerdb.IsExpired(pointer, now) {
+					continue
+				}
 				remote := pointer.GetRemote()
 				if remote == nil {
 					continue
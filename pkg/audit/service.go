@@ -9,8 +9,10 @@ import (
 
 	"go.uber.org/zap"
 
+	"storj.io/storj/internal/sync2"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pointerdb/pdbclient"
+	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/transport"
 )
@@ -20,7 +22,7 @@ type Service struct {
 	Cursor   *Cursor
 	Verifier *Verifier
 	Reporter reporter
-	ticker   *time.Ticker
+	ticker   *sync2.Ticker
 }
 
 // Config contains configurable values for audit service
@@ -32,7 +34,7 @@ type Config struct {
 }
 
 // Run runs the repairer with the configured values
-func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+func (c *Config) Run(ctx context.Context, server *provider.Provider) (err error) {
 	identity := server.Identity()
 	pointers, err := pdbclient.NewClient(identity, c.SatelliteAddr, c.APIKey)
 	if err != nil {
@@ -51,6 +53,13 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 		err := service.Run(ctx)
 		zap.S().Error("audit service failed to run:", zap.Error(err))
 	}()
+
+	defer process.OnReload(func(ctx context.Context) error {
+		service.SetInterval(c.Interval)
+		zap.L().Info("audit interval applied", zap.Duration("interval", c.Interval))
+		return nil
+	})()
+
 	return server.Run(ctx)
 }
 
@@ -68,7 +77,7 @@ func NewService(ctx context.Context, statDBPort string, interval time.Duration,
 		Cursor:   cursor,
 		Verifier: verifier,
 		Reporter: reporter,
-		ticker:   time.NewTicker(interval),
+		ticker:   sync2.NewTicker(interval),
 	}, nil
 }
 
@@ -83,14 +92,17 @@ func (service *Service) Run(ctx context.Context) (err error) {
 			zap.L().Error("process", zap.Error(err))
 		}
 
-		select {
-		case <-service.ticker.C:
-		case <-ctx.Done():
-			return ctx.Err()
+		if err := service.ticker.Wait(ctx); err != nil {
+			return err
 		}
 	}
 }
 
+// SetInterval changes how frequently segments are audited
+func (service *Service) SetInterval(interval time.Duration) {
+	service.ticker.SetInterval(interval)
+}
+
 // process picks a random stripe and verifies correctness
 func (service *Service) process(ctx context.Context) error {
 	stripe, err := service.Cursor.NextStripe(ctx)
@@ -0,0 +1,19 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cfgstruct
+
+import "strings"
+
+// EnvPrefix is the prefix of the environment variables setting configuration
+// values
+const EnvPrefix = "STORJ_"
+
+var envReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// EnvName returns the environment variable setting the value of the flag
+// named flagname, e.g. STORJ_KADEMLIA_BOOTSTRAP_ADDR for
+// kademlia.bootstrap-addr.
+func EnvName(flagname string) string {
+	return EnvPrefix + strings.ToUpper(envReplacer.Replace(flagname))
+}
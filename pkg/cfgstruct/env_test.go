@@ -0,0 +1,25 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cfgstruct
+
+import (
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	for _, test := range []struct {
+		input, expected string
+	}{
+		{"kademlia.bootstrap-addr", "STORJ_KADEMLIA_BOOTSTRAP_ADDR"},
+		{"pointer-db.auth.api-key", "STORJ_POINTER_DB_AUTH_API_KEY"},
+		{"config-dir", "STORJ_CONFIG_DIR"},
+		{"log.level", "STORJ_LOG_LEVEL"},
+	} {
+		actual := EnvName(test.input)
+		if actual != test.expected {
+			t.Logf("expected %#v but got %#v", test.expected, actual)
+			t.Fail()
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"go.uber.org/zap"
 
+	"storj.io/storj/internal/sync2"
 	"storj.io/storj/pkg/datarepair/irreparable"
 	"storj.io/storj/pkg/datarepair/queue"
 	"storj.io/storj/pkg/pb"
@@ -22,6 +23,8 @@ import (
 // Checker is the interface for data repair checker
 type Checker interface {
 	Run(ctx context.Context) error
+	SetInterval(interval time.Duration)
+	Trigger()
 }
 
 // Checker contains the information needed to do checks for missing pieces
@@ -33,7 +36,7 @@ type checker struct {
 	irrdb       irreparable.DB
 	limit       int
 	logger      *zap.Logger
-	ticker      *time.Ticker
+	ticker      *sync2.Ticker
 }
 
 // newChecker creates a new instance of checker
@@ -46,7 +49,7 @@ func newChecker(pointerdb *pointerdb.Server, sdb statdb.DB, repairQueue *queue.Q
 		irrdb:       irrdb,
 		limit:       limit,
 		logger:      logger,
-		ticker:      time.NewTicker(interval),
+		ticker:      sync2.NewTicker(interval),
 	}
 }
 
@@ -60,18 +63,29 @@ func (c *checker) Run(ctx context.Context) (err error) {
 			c.logger.Error("Checker failed", zap.Error(err))
 		}
 
-		select {
-		case <-c.ticker.C: // wait for the next interval to happen
-		case <-ctx.Done(): // or the checker is canceled via context
-			return ctx.Err()
+		// wait for the next interval to happen, or the checker is canceled
+		// via context
+		if err := c.ticker.Wait(ctx); err != nil {
+			return err
 		}
 	}
 }
 
+// SetInterval changes how frequently the segments are checked
+func (c *checker) SetInterval(interval time.Duration) {
+	c.ticker.SetInterval(interval)
+}
+
+// Trigger checks the segments without waiting for the interval
+func (c *checker) Trigger() {
+	c.ticker.Trigger()
+}
+
 // identifyInjuredSegments checks for missing pieces off of the pointerdb and overlay cache
 func (c *checker) identifyInjuredSegments(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	now := time.Now()
 	err = c.pointerdb.Iterate(ctx, &pb.IterateRequest{Recurse: true},
 		func(it storage.Iterator) error {
 			var item storage.ListItem
@@ -87,6 +101,11 @@ func (c *checker) identifyInjuredSegments(ctx context.Context) (err error) {
 					return Error.New("error unmarshalling pointer %s", err)
 				}
 
+				// expired segments are deleted by the expiration reaper
+				if pointerdb.IsExpired(pointer, now) {
+					continue
+				}
+
 				remote := pointer.GetRemote()
 				if remote == nil {
 					continue
@@ -13,8 +13,10 @@ import (
 	"storj.io/storj/pkg/datarepair/queue"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/statdb"
+	"storj.io/storj/pkg/storj"
 	"storj.io/storj/storage/redis"
 )
 
@@ -25,7 +27,7 @@ type Config struct {
 }
 
 // Initialize a Checker struct
-func (c Config) initialize(ctx context.Context) (Checker, error) {
+func (c *Config) initialize(ctx context.Context) (Checker, error) {
 	pdb := pointerdb.LoadFromContext(ctx)
 	if pdb == nil {
 		return nil, Error.New("failed to load pointerdb from context")
@@ -54,7 +56,7 @@ func (c Config) initialize(ctx context.Context) (Checker, error) {
 }
 
 // Run runs the checker with configured values
-func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+func (c *Config) Run(ctx context.Context, server *provider.Provider) (err error) {
 	check, err := c.initialize(ctx)
 	if err != nil {
 		return err
@@ -68,5 +70,16 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 		}
 	}()
 
+	// check the segments stored on a node as soon as it goes offline
+	if cache := overlay.LoadFromContext(ctx); cache != nil {
+		cache.OnOffline(func(storj.NodeID) { check.Trigger() })
+	}
+
+	defer process.OnReload(func(ctx context.Context) error {
+		check.SetInterval(c.Interval)
+		zap.L().Info("checker interval applied", zap.Duration("interval", c.Interval))
+		return nil
+	})()
+
 	return server.Run(ctx)
 }
@@ -12,6 +12,7 @@ import (
 	"storj.io/storj/pkg/datarepair/queue"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pointerdb/pdbclient"
+	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/storage/ec"
 	"storj.io/storj/pkg/storage/segments"
@@ -30,7 +31,7 @@ type Config struct {
 }
 
 // Run runs the repair service with configured values
-func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+func (c *Config) Run(ctx context.Context, server *provider.Provider) (err error) {
 	redisQ, err := redis.NewQueueFrom(c.QueueAddress)
 	if err != nil {
 		return Error.Wrap(err)
@@ -55,6 +56,12 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 		}
 	}()
 
+	defer process.OnReload(func(ctx context.Context) error {
+		service.SetInterval(c.Interval)
+		zap.L().Info("repairer interval applied", zap.Duration("interval", c.Interval))
+		return nil
+	})()
+
 	return server.Run(ctx)
 }
 
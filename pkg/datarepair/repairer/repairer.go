@@ -25,7 +25,7 @@ type repairService struct {
 	queue    queue.RepairQueue
 	repairer SegmentRepairer
 	limiter  *sync2.Limiter
-	ticker   *time.Ticker
+	ticker   *sync2.Ticker
 }
 
 func newService(queue queue.RepairQueue, repairer SegmentRepairer, interval time.Duration, concurrency int) *repairService {
@@ -33,7 +33,7 @@ func newService(queue queue.RepairQueue, repairer SegmentRepairer, interval time
 		queue:    queue,
 		repairer: repairer,
 		limiter:  sync2.NewLimiter(concurrency),
-		ticker:   time.NewTicker(interval),
+		ticker:   sync2.NewTicker(interval),
 	}
 }
 
@@ -50,14 +50,19 @@ func (service *repairService) Run(ctx context.Context) (err error) {
 			zap.L().Error("process", zap.Error(err))
 		}
 
-		select {
-		case <-service.ticker.C: // wait for the next interval to happen
-		case <-ctx.Done(): // or the repairer service is canceled via context
-			return ctx.Err()
+		// wait for the next interval to happen, or the repairer service is
+		// canceled via context
+		if err := service.ticker.Wait(ctx); err != nil {
+			return err
 		}
 	}
 }
 
+// SetInterval changes how frequently the repair queue is processed
+func (service *repairService) SetInterval(interval time.Duration) {
+	service.ticker.SetInterval(interval)
+}
+
 // process picks an item from repair queue and spawns a repair worker
 func (service *repairService) process(ctx context.Context) error {
 	seg, err := service.queue.Dequeue()
@@ -34,6 +34,10 @@ const (
 // Config loads on the configuration values from run flags
 type Config struct {
 	RefreshInterval time.Duration `help:"the interval at which the cache refreshes itself in seconds" default:"1s"`
+	RefreshTTL      time.Duration `help:"the age after which the info of a node in the cache is looked up again, zero disables it" default:"1h"`
+	EvictAfter      time.Duration `help:"the time after which a node not seen is removed from the cache, zero disables it" default:"24h"`
+	WalkInterval    time.Duration `help:"the interval at which the network is crawled for new nodes" default:"1h"`
+	Concurrency     int           `help:"the number of nodes looked up or crawled at once" default:"5"`
 }
 
 // Run runs the Discovery boot up and initialization
@@ -51,7 +55,7 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 	if !ok {
 		return Error.New("unable to get master db instance")
 	}
-	discovery := NewDiscovery(ol, kad, stat.StatDB())
+	discovery := NewDiscovery(zap.L(), ol, kad, stat.StatDB(), c)
 
 	zap.L().Debug("Starting discovery")
 
@@ -78,5 +82,35 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 		}
 	}()
 
+	walk := time.NewTicker(c.WalkInterval)
+	defer walk.Stop()
+
+	go func() {
+		zap.L().Debug("Kicking off bootstrap crawl")
+		if err := discovery.Bootstrap(ctx); err != nil {
+			zap.L().Error("Error with network crawl: ", zap.Error(err))
+		}
+		for {
+			select {
+			case <-walk.C:
+				zap.L().Debug("Kicking off network crawl")
+				if err := discovery.Walk(ctx); err != nil {
+					zap.L().Error("Error with network crawl: ", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
 	return server.Run(context.WithValue(ctx, ctxKeyDiscovery, discovery))
 }
+
+// LoadFromContext gives access to the Discovery from the context, or returns
+// nil
+func LoadFromContext(ctx context.Context) *Discovery {
+	if v, ok := ctx.Value(ctxKeyDiscovery).(*Discovery); ok {
+		return v
+	}
+	return nil
+}
@@ -6,12 +6,19 @@ package discovery
 import (
 	"context"
 	"crypto/rand"
+	"sync"
+	"time"
 
 	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/sync2"
 	"storj.io/storj/pkg/kademlia"
 	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/statdb"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/utils"
 )
 
 var (
@@ -21,49 +28,245 @@ var (
 
 // Discovery struct loads on cache, kad, and statdb
 type Discovery struct {
+	log    *zap.Logger
 	cache  *overlay.Cache
 	kad    *kademlia.Kademlia
 	statdb statdb.DB
+	config Config
+
+	mu sync.Mutex
+	// nodes are the nodes of the cache, loaded from the cache on the first
+	// refresh
+	nodes  map[storj.NodeID]*tracked
+	loaded bool
+	stats  CrawlStats
+}
+
+// tracked is what discovery knows about a node of the cache
+type tracked struct {
+	// seen is when the node was last seen on the network
+	seen time.Time
+	// refreshed is when the node was last looked up
+	refreshed time.Time
+	// info is the info of the node put in the cache
+	info nodeInfo
+}
+
+// nodeInfo is the address and metadata of a node, which are put in the cache
+// again when they change
+type nodeInfo struct {
+	transport pb.NodeTransport
+	address   string
+	email     string
+	wallet    string
+}
+
+func infoOf(node *pb.Node) nodeInfo {
+	return nodeInfo{
+		transport: node.GetAddress().GetTransport(),
+		address:   node.GetAddress().GetAddress(),
+		email:     node.GetMetadata().GetEmail(),
+		wallet:    node.GetMetadata().GetWallet(),
+	}
+}
+
+// CrawlStats are the statistics of the crawls of the network and of the
+// maintenance of the cache
+type CrawlStats struct {
+	// Crawls is the number of crawls completed
+	Crawls int64
+	// LastCrawl is when the last crawl completed
+	LastCrawl time.Time
+	// NodesQueried is the number of nodes asked for their neighbors during
+	// the last crawl
+	NodesQueried int64
+	// QueriesFailed is the number of nodes failing to respond during the last
+	// crawl
+	QueriesFailed int64
+	// NodesFound is the number of nodes added to the cache by the last crawl
+	NodesFound int64
+	// NodesRefreshed is the number of stale nodes looked up again
+	NodesRefreshed int64
+	// NodesEvicted is the number of nodes removed from the cache
+	NodesEvicted int64
 }
 
 // NewDiscovery Returns a new Discovery instance with cache, kad, and statdb loaded on
-func NewDiscovery(ol *overlay.Cache, kad *kademlia.Kademlia, stat statdb.DB) *Discovery {
+func NewDiscovery(log *zap.Logger, ol *overlay.Cache, kad *kademlia.Kademlia, stat statdb.DB, config Config) *Discovery {
 	return &Discovery{
+		log:    log,
 		cache:  ol,
 		kad:    kad,
 		statdb: stat,
+		config: config,
+		nodes:  make(map[storj.NodeID]*tracked),
 	}
 }
 
-// Refresh updates the cache db with the current DHT.
-// We currently do not penalize nodes that are unresponsive,
-// but should in the future.
-func (d *Discovery) Refresh(ctx context.Context) error {
-	// TODO(coyle): make refresh work by looking on the network for new ndoes
-	nodes := d.kad.Seen()
+// Stats returns the statistics of the crawls
+func (d *Discovery) Stats() CrawlStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stats
+}
+
+// Refresh adds the nodes kademlia came into contact with to the cache, looks
+// up again the nodes whose info is older than the refresh TTL, and evicts the
+// nodes not seen for the eviction period
+func (d *Discovery) Refresh(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := d.load(ctx); err != nil {
+		return err
+	}
 
-	for _, v := range nodes {
+	// kademlia doesn't forget the nodes it has seen, so only the new ones and
+	// the ones whose address or metadata changed are put, the others are kept
+	// fresh by the refreshes and the crawls
+	now := time.Now()
+	for _, v := range d.kad.Seen() {
+		if v.Id.IsZero() || !d.add(v, now) {
+			continue
+		}
 		if err := d.cache.Put(ctx, v.Id, *v); err != nil {
 			return err
 		}
 	}
 
+	stale, evicted := d.expired(now)
+
+	for _, id := range evicted {
+		if err := d.cache.Delete(ctx, id); err != nil {
+			return err
+		}
+		d.log.Debug("evicted node not seen", zap.Stringer("node", id))
+	}
+
+	var mu sync.Mutex
+	var errlist []error
+	limiter := sync2.NewLimiter(d.concurrency())
+	for _, id := range stale {
+		id := id
+		started := limiter.Go(ctx, func() {
+			if err := d.refresh(ctx, id); err != nil {
+				mu.Lock()
+				errlist = append(errlist, err)
+				mu.Unlock()
+			}
+		})
+		if !started {
+			break
+		}
+	}
+	limiter.Wait()
+
+	return DiscoveryError.Wrap(utils.CombineErrors(errlist...))
+}
+
+// refresh looks up the node again and updates it in the cache
+func (d *Discovery) refresh(ctx context.Context, id storj.NodeID) error {
+	node, err := d.kad.FindNode(ctx, id)
+	if err != nil {
+		// the node is evicted if it's not seen again
+		d.log.Debug("failed to refresh node", zap.Stringer("node", id), zap.Error(err))
+		return nil
+	}
+	if err := d.cache.Put(ctx, id, node); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.see(&node, time.Now())
+	d.stats.NodesRefreshed++
 	return nil
 }
 
-// Bootstrap walks the initialized network and populates the cache
-func (d *Discovery) Bootstrap(ctx context.Context) error {
-	// o := overlay.LoadFromContext(ctx)
-	// kad := kademlia.LoadFromContext(ctx)
-	// TODO(coyle): make Bootstrap work
-	// look in our routing table
-	// get every node we know about
-	// ask every node for every node they know about
-	// for each newly known node, ask those nodes for every node they know about
-	// continue until no new nodes are found
+// load tracks the nodes already in the cache, as seen now
+func (d *Discovery) load(ctx context.Context) error {
+	d.mu.Lock()
+	loaded := d.loaded
+	d.mu.Unlock()
+	if loaded {
+		return nil
+	}
+
+	nodes, err := d.cache.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, node := range nodes {
+		if _, ok := d.nodes[node.Id]; !ok {
+			d.nodes[node.Id] = &tracked{seen: now, refreshed: now, info: infoOf(node)}
+		}
+	}
+	d.loaded = true
 	return nil
 }
 
+// add tracks the node as seen now if it wasn't tracked before or its address
+// or metadata changed, and returns whether it has to be put in the cache
+func (d *Discovery) add(node *pb.Node, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if t, ok := d.nodes[node.Id]; ok && t.info == infoOf(node) {
+		return false
+	}
+	d.see(node, now)
+	return true
+}
+
+// track marks the node seen, and returns whether it wasn't tracked before and
+// whether its address or metadata changed
+func (d *Discovery) track(node *pb.Node, now time.Time) (isNew, changed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	t, ok := d.nodes[node.Id]
+	changed = ok && t.info != infoOf(node)
+	d.see(node, now)
+	return !ok, changed
+}
+
+// see marks the node seen with its current info. d.mu must be held.
+func (d *Discovery) see(node *pb.Node, now time.Time) {
+	if t, ok := d.nodes[node.Id]; ok {
+		t.seen = now
+		t.refreshed = now
+		t.info = infoOf(node)
+		return
+	}
+	d.nodes[node.Id] = &tracked{seen: now, refreshed: now, info: infoOf(node)}
+}
+
+// expired returns the nodes to refresh and untracks the nodes to evict
+func (d *Discovery) expired(now time.Time) (stale, evicted storj.NodeIDList) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for id, t := range d.nodes {
+		switch {
+		case d.config.EvictAfter > 0 && now.Sub(t.seen) >= d.config.EvictAfter:
+			delete(d.nodes, id)
+			evicted = append(evicted, id)
+			d.stats.NodesEvicted++
+		case d.config.RefreshTTL > 0 && now.Sub(t.refreshed) >= d.config.RefreshTTL:
+			// don't look up the node again before the TTL, even if it fails
+			t.refreshed = now
+			stale = append(stale, id)
+		}
+	}
+	return stale, evicted
+}
+
+// Bootstrap crawls the network starting from the nodes of the routing table
+func (d *Discovery) Bootstrap(ctx context.Context) error {
+	return d.crawl(ctx, d.kad.Seen())
+}
+
 // Discovery runs lookups for random node ID's to find new nodes in the network
 func (d *Discovery) Discovery(ctx context.Context) error {
 	r, err := randomID()
@@ -77,12 +280,104 @@ func (d *Discovery) Discovery(ctx context.Context) error {
 	return nil
 }
 
-// Walk iterates over each node in each bucket to traverse the network
+// Walk crawls the network starting from the nodes of the cache
 func (d *Discovery) Walk(ctx context.Context) error {
-	// TODO: This should walk the cache, rather than be a duplicate of refresh
+	nodes, err := d.cache.List(ctx)
+	if err != nil {
+		return err
+	}
+	return d.crawl(ctx, nodes)
+}
+
+// crawl asks the nodes for their neighbors, then the neighbors for theirs,
+// until no new nodes appear. The nodes responding are added to the cache.
+func (d *Discovery) crawl(ctx context.Context, start []*pb.Node) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var mu sync.Mutex
+	var queried, failed, found int64
+
+	visited := make(map[storj.NodeID]bool)
+	var frontier []*pb.Node
+	for _, node := range start {
+		if node.Id.IsZero() || visited[node.Id] {
+			continue
+		}
+		visited[node.Id] = true
+		frontier = append(frontier, node)
+	}
+
+	for len(frontier) > 0 {
+		var next []*pb.Node
+
+		limiter := sync2.NewLimiter(d.concurrency())
+		for _, node := range frontier {
+			node := node
+			started := limiter.Go(ctx, func() {
+				neighbors, err := d.kad.Neighbors(ctx, *node)
+
+				isNew, changed := false, false
+				if err == nil {
+					isNew, changed = d.track(node, time.Now())
+					if isNew || changed {
+						if putErr := d.cache.Put(ctx, node.Id, *node); putErr != nil {
+							d.log.Error("failed to add crawled node", zap.Stringer("node", node.Id), zap.Error(putErr))
+						}
+					}
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				queried++
+				if err != nil {
+					failed++
+					d.log.Debug("failed to crawl node", zap.Stringer("node", node.Id), zap.Error(err))
+					return
+				}
+				if isNew {
+					found++
+				}
+				for _, neighbor := range neighbors {
+					if neighbor == nil || neighbor.Id.IsZero() || visited[neighbor.Id] {
+						continue
+					}
+					visited[neighbor.Id] = true
+					next = append(next, neighbor)
+				}
+			})
+			if !started {
+				break
+			}
+		}
+		limiter.Wait()
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		frontier = next
+	}
+
+	mon.IntVal("crawl_nodes_queried").Observe(queried)
+	mon.IntVal("crawl_nodes_found").Observe(found)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stats.Crawls++
+	d.stats.LastCrawl = time.Now()
+	d.stats.NodesQueried = queried
+	d.stats.QueriesFailed = failed
+	d.stats.NodesFound = found
 	return nil
 }
 
+// concurrency returns the number of nodes queried at once
+func (d *Discovery) concurrency() int {
+	if d.config.Concurrency <= 0 {
+		return 1
+	}
+	return d.config.Concurrency
+}
+
 func randomID() (storj.NodeID, error) {
 	b := make([]byte, 32)
 	_, err := rand.Read(b)
@@ -5,11 +5,17 @@ package discovery_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
 
 	"storj.io/storj/internal/testcontext"
 	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/discovery"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
 )
 
 func TestCache_Refresh(t *testing.T) {
@@ -27,3 +33,120 @@ func TestCache_Refresh(t *testing.T) {
 	err = planet.Satellites[0].Discovery.Refresh(ctx)
 	assert.NoError(t, err)
 }
+
+func TestCrawl(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+	// wait for the nodes to bootstrap off the satellite
+	time.Sleep(2 * time.Second)
+
+	satellite := planet.Satellites[0]
+	service := discovery.NewDiscovery(zaptest.NewLogger(t), satellite.Overlay, satellite.Kademlia, satellite.StatDB,
+		discovery.Config{Concurrency: 3})
+
+	err = service.Bootstrap(ctx)
+	require.NoError(t, err)
+
+	for _, node := range planet.StorageNodes {
+		_, err := satellite.Overlay.Get(ctx, node.ID())
+		assert.NoError(t, err)
+	}
+
+	stats := service.Stats()
+	assert.EqualValues(t, 1, stats.Crawls)
+	assert.False(t, stats.LastCrawl.IsZero())
+	assert.True(t, stats.NodesQueried >= int64(len(planet.StorageNodes)))
+	assert.EqualValues(t, 0, stats.QueriesFailed)
+
+	// crawling again finds no new nodes
+	err = service.Walk(ctx)
+	require.NoError(t, err)
+
+	stats = service.Stats()
+	assert.EqualValues(t, 2, stats.Crawls)
+	assert.EqualValues(t, 0, stats.NodesFound)
+}
+
+func TestEvict(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	satellite := planet.Satellites[0]
+	for _, node := range planet.StorageNodes {
+		require.NoError(t, satellite.Overlay.Put(ctx, node.ID(), node.Info))
+	}
+
+	const evictAfter = 50 * time.Millisecond
+	service := discovery.NewDiscovery(zaptest.NewLogger(t), satellite.Overlay, satellite.Kademlia, satellite.StatDB,
+		discovery.Config{EvictAfter: evictAfter})
+
+	// the nodes in the cache are tracked as seen on the first refresh
+	require.NoError(t, service.Refresh(ctx))
+	for _, node := range planet.StorageNodes {
+		_, err := satellite.Overlay.Get(ctx, node.ID())
+		assert.NoError(t, err)
+	}
+
+	time.Sleep(evictAfter)
+
+	require.NoError(t, service.Refresh(ctx))
+	for _, node := range planet.StorageNodes {
+		_, err := satellite.Overlay.Get(ctx, node.ID())
+		assert.Equal(t, overlay.ErrNodeNotFound, err)
+	}
+	assert.True(t, service.Stats().NodesEvicted >= int64(len(planet.StorageNodes)))
+}
+
+func TestRefreshChanged(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+	// wait for the nodes to bootstrap off the satellite
+	time.Sleep(2 * time.Second)
+
+	satellite := planet.Satellites[0]
+	var seen *pb.Node
+	for _, node := range satellite.Kademlia.Seen() {
+		if !node.Id.IsZero() && node.Id != satellite.ID() {
+			seen = node
+			break
+		}
+	}
+	require.NotNil(t, seen)
+
+	// the cache holds an old address of the node
+	moved := *seen
+	moved.Address = &pb.NodeAddress{Transport: pb.NodeTransport_TCP_TLS_GRPC, Address: "127.0.0.1:1"}
+	require.NoError(t, satellite.Overlay.Put(ctx, moved.Id, moved))
+
+	service := discovery.NewDiscovery(zaptest.NewLogger(t), satellite.Overlay, satellite.Kademlia, satellite.StatDB,
+		discovery.Config{})
+	require.NoError(t, service.Refresh(ctx))
+
+	cached, err := satellite.Overlay.Get(ctx, seen.Id)
+	require.NoError(t, err)
+	assert.Equal(t, seen.GetAddress().GetAddress(), cached.GetAddress().GetAddress())
+}
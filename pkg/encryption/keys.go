@@ -0,0 +1,68 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package encryption
+
+import (
+	"golang.org/x/crypto/argon2"
+
+	"storj.io/storj/pkg/storj"
+)
+
+const (
+	// Argon2id parameters for deriving root keys from passphrases
+	argon2Time    = 1
+	argon2Memory  = 64 * 1024 // in KiB
+	argon2Threads = 4
+)
+
+// DeriveRootKey derives a root key from the passphrase and the salt with the
+// memory-hard Argon2id function
+func DeriveRootKey(passphrase, salt []byte) *storj.Key {
+	derived := argon2.IDKey(passphrase, salt, argon2Time, argon2Memory, argon2Threads, storj.KeySize)
+
+	key := new(storj.Key)
+	copy(key[:], derived)
+	return key
+}
+
+// KeyStore holds the root key and the bucket specific root keys from which
+// the path and content keys of the objects are derived
+type KeyStore struct {
+	root             *storj.Key
+	buckets          map[string]*storj.Key
+	deriveBucketKeys bool
+}
+
+// NewKeyStore creates a key store with the given root key. If deriveBucketKeys
+// is true, every bucket without an explicit key gets its own key derived from
+// the root key.
+func NewKeyStore(root *storj.Key, deriveBucketKeys bool) *KeyStore {
+	return &KeyStore{
+		root:             root,
+		buckets:          make(map[string]*storj.Key),
+		deriveBucketKeys: deriveBucketKeys,
+	}
+}
+
+// SetBucketKey sets an explicit root key for the given bucket
+func (keys *KeyStore) SetBucketKey(bucket string, key *storj.Key) {
+	keys.buckets[bucket] = key
+}
+
+// RootKey returns the root key for the bucket of the given unencrypted path
+func (keys *KeyStore) RootKey(path storj.Path) (*storj.Key, error) {
+	comps := storj.SplitPath(path)
+	if len(comps) == 0 || comps[0] == "" {
+		return keys.root, nil
+	}
+
+	bucket := comps[0]
+	if key, ok := keys.buckets[bucket]; ok {
+		return key, nil
+	}
+	if keys.deriveBucketKeys {
+		return DeriveKey(keys.root, "bucket:"+bucket)
+	}
+	return keys.root, nil
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package encryption
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/storj"
+)
+
+func TestDeriveRootKey(t *testing.T) {
+	passphrase := []byte("correct horse battery staple")
+
+	key1 := DeriveRootKey(passphrase, []byte("salt1"))
+	key2 := DeriveRootKey(passphrase, []byte("salt1"))
+	key3 := DeriveRootKey(passphrase, []byte("salt2"))
+	key4 := DeriveRootKey([]byte("another passphrase"), []byte("salt1"))
+
+	assert.Equal(t, key1, key2, "same passphrase and salt")
+	assert.NotEqual(t, key1, key3, "different salt")
+	assert.NotEqual(t, key1, key4, "different passphrase")
+
+	var raw storj.Key
+	copy(raw[:], passphrase)
+	assert.NotEqual(t, &raw, key1, "derived key is not the raw passphrase")
+}
+
+func TestKeyStore(t *testing.T) {
+	root := new(storj.Key)
+	copy(root[:], randData(storj.KeySize))
+
+	explicit := new(storj.Key)
+	copy(explicit[:], randData(storj.KeySize))
+
+	derivedBucket, err := DeriveKey(root, "bucket:derived")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for i, tt := range []struct {
+		derive bool
+		path   storj.Path
+		key    *storj.Key
+	}{
+		{false, "", root},
+		{false, "derived/a/b", root},
+		{false, "explicit/a/b", explicit},
+		{true, "", root},
+		{true, "derived", derivedBucket},
+		{true, "derived/a/b", derivedBucket},
+		{true, "explicit/a/b", explicit},
+	} {
+		keys := NewKeyStore(root, tt.derive)
+		keys.SetBucketKey("explicit", explicit)
+
+		key, err := keys.RootKey(tt.path)
+		if assert.NoError(t, err, i) {
+			assert.Equal(t, tt.key, key, i)
+		}
+	}
+}
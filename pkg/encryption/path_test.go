@@ -95,6 +95,7 @@ func forAllCiphers(test func(cipher storj.Cipher)) {
 		storj.Unencrypted,
 		storj.AESGCM,
 		storj.SecretBox,
+		storj.XChaCha20Poly1305,
 	} {
 		test(cipher)
 	}
@@ -0,0 +1,80 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package encryption
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// TestVectors checks the output of all ciphers against fixed vectors, so
+// data encrypted by older versions stays readable
+func TestVectors(t *testing.T) {
+	var key storj.Key
+	copy(key[:], "the quick brown fox jumps over the lazy dog")
+	var nonce storj.Nonce
+	copy(nonce[:], "storj nonce for vectors!")
+
+	plain := []byte("hello storj")
+	path := storj.Path("bucket/a/b/c.txt")
+
+	for _, tt := range []struct {
+		cipher    storj.Cipher
+		encrypted string
+		path      storj.Path
+	}{
+		{
+			cipher:    storj.AESGCM,
+			encrypted: "ec64f80178f14f5055942a23d8c56b59f21726f380ca706a796765",
+			path:      "-xgPf5qbUOqnZJ8LdOeFPwFa7MxDK9NfKVN-N9wPnYSWGg/QJjXLSdxTxS3_ZT8LutAtQ7zdFFylu-5IRyU5aU/LXw2sawTPdSuweyVjn4tZfjYq-HrWfoeoAP3Sgw/YA98VkiFAlf9FoNV_zBfLaBVRDlvgPvWDfM6WbCuXzbG",
+		},
+		{
+			cipher:    storj.SecretBox,
+			encrypted: "29725490d3d9b0a204f3339572af99456ccb0e53648540ec9613c1",
+			path:      "-xgPf5qbUOqnZJ8LUulcd1GQdO9wJ6Nn7j8A_XbJBlUoqjSwLhYrayaMvpeECw/QJjXLSdxTxS3_ZT87zFVryUpDaXFzWqyzQmrgPeABQ1Zne_rQJZuHSE/LXw2sawTPdSuweyVx7EspFsyTOX_i9ZhftpQeJHyMXSiSPSr958LujM/YA98VkiFAlf9FoNVB5PZuDV0_-hH4H9qZertJui2lyiUIs8VlJqdp3-q79Zv",
+		},
+		{
+			cipher:    storj.XChaCha20Poly1305,
+			encrypted: "4dd2e977a3f15c03e2ac8e32e354e53e7bf0e8b250ed486cc7e2a3",
+			path:      "-xgPf5qbUOqnZJ8LUulcd1GQdO9wJ6NniGxqiT218uYe37SGBnFS7-oNgGa7wg/QJjXLSdxTxS3_ZT87zFVryUpDaXFzWqy-RVNuUbmsjP7J8XDGaL8l7s/LXw2sawTPdSuweyVx7EspFsyTOX_i9ZhyDh1ugNf5t9sWMYmccwCkh8/YA98VkiFAlf9FoNVB5PZuDV0_-hH4H9qk1rruNUtq8KQ6qvJSTVsd_Rh0eLq",
+		},
+	} {
+		encrypted, err := Encrypt(plain, tt.cipher, &key, &nonce)
+		if assert.NoError(t, err, tt.cipher) {
+			assert.Equal(t, tt.encrypted, hex.EncodeToString(encrypted), tt.cipher)
+		}
+
+		data, err := hex.DecodeString(tt.encrypted)
+		if !assert.NoError(t, err, tt.cipher) {
+			continue
+		}
+		decrypted, err := Decrypt(data, tt.cipher, &key, &nonce)
+		if assert.NoError(t, err, tt.cipher) {
+			assert.Equal(t, plain, decrypted, tt.cipher)
+		}
+
+		// the data can't be decrypted with any of the other ciphers
+		forAllCiphers(func(other storj.Cipher) {
+			if other == tt.cipher || other == storj.Unencrypted {
+				return
+			}
+			_, err := Decrypt(data, other, &key, &nonce)
+			assert.Error(t, err, "%v decrypted with %v", tt.cipher, other)
+		})
+
+		encryptedPath, err := EncryptPath(path, tt.cipher, &key)
+		if assert.NoError(t, err, tt.cipher) {
+			assert.Equal(t, tt.path, encryptedPath, tt.cipher)
+		}
+
+		decryptedPath, err := DecryptPath(tt.path, tt.cipher, &key)
+		if assert.NoError(t, err, tt.cipher) {
+			assert.Equal(t, path, decryptedPath, tt.cipher)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package encryption
+
+import (
+	"crypto/cipher"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"storj.io/storj/pkg/storj"
+)
+
+type xchachaEncrypter struct {
+	blockSize     int
+	key           *storj.Key
+	startingNonce *storj.Nonce
+	overhead      int
+	aead          cipher.AEAD
+}
+
+// NewXChaChaEncrypter returns a Transformer that encrypts the data passing
+// through with key using XChaCha20-Poly1305.
+//
+// startingNonce is treated as a big-endian encoded unsigned
+// integer, and as blocks pass through, their block number and the starting
+// nonce is added together to come up with that block's nonce. Encrypting
+// different data with the same key and the same nonce is a huge security
+// issue. It's safe to always encode new data with a random key and random
+// startingNonce. The monotonically-increasing nonce (that rolls over) is to
+// protect against data reordering.
+//
+// When in doubt, generate a new key from crypto/rand and a startingNonce
+// from crypto/rand as often as possible.
+func NewXChaChaEncrypter(key *storj.Key, startingNonce *storj.Nonce, encryptedBlockSize int) (Transformer, error) {
+	aead, err := chacha20poly1305.NewX(key[:])
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if encryptedBlockSize <= aead.Overhead() {
+		return nil, ErrInvalidConfig.New("encrypted block size %d too small", encryptedBlockSize)
+	}
+	return &xchachaEncrypter{
+		blockSize:     encryptedBlockSize - aead.Overhead(),
+		key:           key,
+		startingNonce: startingNonce,
+		overhead:      aead.Overhead(),
+		aead:          aead,
+	}, nil
+}
+
+func (s *xchachaEncrypter) InBlockSize() int {
+	return s.blockSize
+}
+
+func (s *xchachaEncrypter) OutBlockSize() int {
+	return s.blockSize + s.overhead
+}
+
+func (s *xchachaEncrypter) Transform(out, in []byte, blockNum int64) ([]byte, error) {
+	nonce, err := calcNonce(s.startingNonce, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(out, nonce[:], in, nil), nil
+}
+
+type xchachaDecrypter struct {
+	blockSize     int
+	key           *storj.Key
+	startingNonce *storj.Nonce
+	overhead      int
+	aead          cipher.AEAD
+}
+
+// NewXChaChaDecrypter returns a Transformer that decrypts the data passing
+// through with key using XChaCha20-Poly1305. See the comments for
+// NewXChaChaEncrypter about startingNonce.
+func NewXChaChaDecrypter(key *storj.Key, startingNonce *storj.Nonce, encryptedBlockSize int) (Transformer, error) {
+	aead, err := chacha20poly1305.NewX(key[:])
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if encryptedBlockSize <= aead.Overhead() {
+		return nil, ErrInvalidConfig.New("encrypted block size %d too small", encryptedBlockSize)
+	}
+	return &xchachaDecrypter{
+		blockSize:     encryptedBlockSize - aead.Overhead(),
+		key:           key,
+		startingNonce: startingNonce,
+		overhead:      aead.Overhead(),
+		aead:          aead,
+	}, nil
+}
+
+func (s *xchachaDecrypter) InBlockSize() int {
+	return s.blockSize + s.overhead
+}
+
+func (s *xchachaDecrypter) OutBlockSize() int {
+	return s.blockSize
+}
+
+func (s *xchachaDecrypter) Transform(out, in []byte, blockNum int64) ([]byte, error) {
+	nonce, err := calcNonce(s.startingNonce, blockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	plainData, err := s.aead.Open(out, nonce[:], in, nil)
+	if err != nil {
+		return nil, ErrDecryptFailed.Wrap(err)
+	}
+	return plainData, nil
+}
+
+// EncryptXChaCha encrypts byte data with a key and nonce. The cipher data is returned
+func EncryptXChaCha(data []byte, key *storj.Key, nonce *storj.Nonce) (cipherData []byte, err error) {
+	aead, err := chacha20poly1305.NewX(key[:])
+	if err != nil {
+		return []byte{}, Error.Wrap(err)
+	}
+	return aead.Seal(nil, nonce[:], data, nil), nil
+}
+
+// DecryptXChaCha decrypts byte data with a key and nonce. The plain data is returned
+func DecryptXChaCha(cipherData []byte, key *storj.Key, nonce *storj.Nonce) (data []byte, err error) {
+	if len(cipherData) == 0 {
+		return []byte{}, Error.New("empty cipher data")
+	}
+	aead, err := chacha20poly1305.NewX(key[:])
+	if err != nil {
+		return []byte{}, Error.Wrap(err)
+	}
+	plainData, err := aead.Open(nil, nonce[:], cipherData, nil)
+	if err != nil {
+		return []byte{}, ErrDecryptFailed.Wrap(err)
+	}
+	return plainData, nil
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package expiration
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/provider"
+)
+
+// Config contains configurable values for the expiration reaper
+type Config struct {
+	Interval time.Duration `help:"how frequently expired segments should be deleted" default:"1h"`
+	Limit    int           `help:"maximum number of expired segments deleted per batch" default:"1000"`
+}
+
+// Initialize a Reaper struct
+func (c Config) initialize(ctx context.Context) (Reaper, error) {
+	pdb := pointerdb.LoadFromContext(ctx)
+	if pdb == nil {
+		return nil, Error.New("failed to load pointerdb from context")
+	}
+	if pdb.ExpirationIndex() == nil {
+		return nil, Error.New("pointerdb has no expiration index")
+	}
+	return newReaper(pdb, zap.L(), c.Limit, c.Interval), nil
+}
+
+// Run runs the expiration reaper with configured values
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	reaper, err := c.initialize(ctx)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		if err := reaper.Run(ctx); err != nil {
+			defer cancel()
+			zap.L().Error("Error running expiration reaper", zap.Error(err))
+		}
+	}()
+
+	return server.Run(ctx)
+}
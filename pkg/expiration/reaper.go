@@ -0,0 +1,118 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package expiration
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/storage"
+)
+
+// Reaper is the interface for the service deleting expired segments
+type Reaper interface {
+	Run(ctx context.Context) error
+}
+
+// reaper deletes the pointers of expired segments. The pieces of remote
+// segments are deleted by the storage nodes themselves once they expire.
+type reaper struct {
+	pointerdb *pointerdb.Server
+	index     *pointerdb.ExpirationIndex
+	limit     int
+	logger    *zap.Logger
+	ticker    *time.Ticker
+}
+
+// newReaper creates a new instance of reaper
+func newReaper(pointerdb *pointerdb.Server, logger *zap.Logger, limit int, interval time.Duration) *reaper {
+	return &reaper{
+		pointerdb: pointerdb,
+		index:     pointerdb.ExpirationIndex(),
+		limit:     limit,
+		logger:    logger,
+		ticker:    time.NewTicker(interval),
+	}
+}
+
+// Run the reaper loop
+func (r *reaper) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for {
+		err = r.deleteExpired(ctx, time.Now())
+		if err != nil {
+			r.logger.Error("Expiration reaper failed", zap.Error(err))
+		}
+
+		select {
+		case <-r.ticker.C: // wait for the next interval to happen
+		case <-ctx.Done(): // or the reaper is canceled via context
+			return ctx.Err()
+		}
+	}
+}
+
+// deleteExpired deletes all segments which expired before now. Deleting the
+// last segment of a stream deletes the object and stream metadata with it.
+func (r *reaper) deleteExpired(ctx context.Context, now time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for {
+		expired, err := r.index.Expired(now, r.limit)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		for _, entry := range expired {
+			if err := r.reap(entry, now); err != nil {
+				return err
+			}
+		}
+
+		if len(expired) == 0 || (r.limit > 0 && len(expired) < r.limit) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+}
+
+// reap deletes the pointer of an expired index entry and removes the entry.
+// Entries left behind by overwritten or deleted pointers are only removed.
+func (r *reaper) reap(entry pointerdb.ExpiredPointer, now time.Time) error {
+	path := storage.Key(entry.Path)
+
+	value, err := r.pointerdb.DB.Get(path)
+	if err != nil && !storage.ErrKeyNotFound.Has(err) {
+		return Error.Wrap(err)
+	}
+
+	if err == nil {
+		pointer := &pb.Pointer{}
+		if err := proto.Unmarshal(value, pointer); err != nil {
+			return Error.New("error unmarshalling pointer %s", err)
+		}
+
+		if pointerdb.IsExpired(pointer, now) {
+			err = r.pointerdb.DB.Delete(path)
+			if err != nil && !storage.ErrKeyNotFound.Has(err) {
+				return Error.Wrap(err)
+			}
+			mon.Meter("expired_segments_deleted").Mark(1)
+			r.logger.Debug("deleted expired segment", zap.String("path", entry.Path))
+		}
+	}
+
+	return Error.Wrap(r.index.Remove(entry.Path, entry.Expiration))
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package expiration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestDeleteExpired(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	pdb := pointerdb.NewServer(teststore.New(), &overlay.Cache{}, zap.NewNop(), pointerdb.Config{}, nil)
+	index := pointerdb.NewExpirationIndex(teststore.New())
+	pdb.SetExpirationIndex(index)
+
+	now := time.Now()
+	put := func(path string, expiration time.Time) {
+		pointer := &pb.Pointer{Type: pb.Pointer_INLINE}
+		if !expiration.IsZero() {
+			timestamp, err := ptypes.TimestampProto(expiration)
+			require.NoError(t, err)
+			pointer.ExpirationDate = timestamp
+		}
+		value, err := proto.Marshal(pointer)
+		require.NoError(t, err)
+		require.NoError(t, pdb.DB.Put(storage.Key(path), value))
+		if !expiration.IsZero() {
+			require.NoError(t, index.Add(path, expiration))
+		}
+	}
+
+	put("l/bucket/expired", now.Add(-time.Hour))
+	put("s0/bucket/expired", now.Add(-time.Hour))
+	put("l/bucket/later", now.Add(time.Hour))
+	put("l/bucket/forever", time.Time{})
+	// an index entry whose pointer was overwritten with a later expiration
+	put("l/bucket/extended", now.Add(time.Hour))
+	require.NoError(t, index.Add("l/bucket/extended", now.Add(-time.Hour)))
+	// an index entry whose pointer was deleted
+	require.NoError(t, index.Add("l/bucket/deleted", now.Add(-time.Hour)))
+
+	reaper := newReaper(pdb, zap.NewNop(), 2, time.Hour)
+	require.NoError(t, reaper.deleteExpired(ctx, now))
+
+	for _, path := range []string{"l/bucket/expired", "s0/bucket/expired"} {
+		_, err := pdb.DB.Get(storage.Key(path))
+		assert.True(t, storage.ErrKeyNotFound.Has(err), path)
+	}
+	for _, path := range []string{"l/bucket/later", "l/bucket/forever", "l/bucket/extended"} {
+		_, err := pdb.DB.Get(storage.Key(path))
+		assert.NoError(t, err, path)
+	}
+
+	expired, err := index.Expired(now, 0)
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+
+	expired, err = index.Expired(now.Add(2*time.Hour), 0)
+	require.NoError(t, err)
+	assert.Len(t, expired, 2)
+}
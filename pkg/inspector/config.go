@@ -10,6 +10,7 @@ import (
 	"go.uber.org/zap"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
+	"storj.io/storj/pkg/discovery"
 	"storj.io/storj/pkg/kademlia"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pb"
@@ -55,12 +56,13 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 	}
 
 	srv := &Server{
-		dht:      kad,
-		identity: id,
-		cache:    ol,
-		statdb:   sdb.StatDB(),
-		logger:   zap.L(),
-		metrics:  monkit.Default,
+		dht:       kad,
+		identity:  id,
+		cache:     ol,
+		discovery: discovery.LoadFromContext(ctx),
+		statdb:    sdb.StatDB(),
+		logger:    zap.L(),
+		metrics:   monkit.Default,
 	}
 
 	pb.RegisterInspectorServer(server.GRPC(), srv)
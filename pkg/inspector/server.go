@@ -11,6 +11,7 @@ import (
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
 	"storj.io/storj/pkg/dht"
+	"storj.io/storj/pkg/discovery"
 	"storj.io/storj/pkg/node"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pb"
@@ -26,12 +27,13 @@ var (
 
 // Server holds references to cache and kad
 type Server struct {
-	dht      dht.DHT
-	cache    *overlay.Cache
-	statdb   statdb.DB
-	logger   *zap.Logger
-	metrics  *monkit.Registry
-	identity *provider.FullIdentity
+	dht       dht.DHT
+	cache     *overlay.Cache
+	discovery *discovery.Discovery
+	statdb    statdb.DB
+	logger    *zap.Logger
+	metrics   *monkit.Registry
+	identity  *provider.FullIdentity
 }
 
 // ---------------------
@@ -141,6 +143,27 @@ func (srv *Server) LookupNode(ctx context.Context, req *pb.LookupNodeRequest) (*
 	}, nil
 }
 
+// GetCrawlStats returns the statistics of the crawls of the network by discovery
+func (srv *Server) GetCrawlStats(ctx context.Context, req *pb.GetCrawlStatsRequest) (*pb.GetCrawlStatsResponse, error) {
+	if srv.discovery == nil {
+		return nil, ServerError.New("discovery responsibility unstarted")
+	}
+	stats := srv.discovery.Stats()
+
+	res := &pb.GetCrawlStatsResponse{
+		Crawls:         stats.Crawls,
+		NodesQueried:   stats.NodesQueried,
+		QueriesFailed:  stats.QueriesFailed,
+		NodesFound:     stats.NodesFound,
+		NodesRefreshed: stats.NodesRefreshed,
+		NodesEvicted:   stats.NodesEvicted,
+	}
+	if !stats.LastCrawl.IsZero() {
+		res.LastCrawlUnixSec = stats.LastCrawl.Unix()
+	}
+	return res, nil
+}
+
 // ---------------------
 // StatDB commands:
 // ---------------------
@@ -6,6 +6,7 @@ package kademlia
 import (
 	"context"
 	"flag"
+	"time"
 
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
@@ -49,6 +50,9 @@ type Config struct {
 	Alpha           int    `help:"alpha is a system wide concurrency parameter." default:"5"`
 	ExternalAddress string `help:"the public address of the kademlia node; defaults to the gRPC server address." default:""`
 	Farmer          FarmerConfig
+
+	RefreshInterval  time.Duration `help:"how frequently the routing table is refreshed" default:"10m"`
+	BucketStaleAfter time.Duration `help:"how long a k-bucket can go untouched before a random node in its range is looked up" default:"1h"`
 }
 
 // Run implements provider.Responsibility
@@ -89,6 +93,10 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (
 		}
 	}()
 
+	go func() {
+		_ = kad.RunRefresh(ctx, c.RefreshInterval, c.BucketStaleAfter)
+	}()
+
 	return server.Run(context.WithValue(ctx, ctxKeyKad, kad))
 }
 
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/gogo/protobuf/proto"
@@ -17,6 +18,7 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
+	"storj.io/storj/internal/sync2"
 	"storj.io/storj/pkg/dht"
 	"storj.io/storj/pkg/node"
 	"storj.io/storj/pkg/pb"
@@ -37,6 +39,9 @@ var (
 	// TODO: shouldn't default to TCP but not sure what to do yet
 	defaultTransport = pb.NodeTransport_TCP_TLS_GRPC
 	defaultRetries   = 3
+	// defaultRefreshInterval is how often the routing table is refreshed
+	// until RunRefresh sets the interval
+	defaultRefreshInterval = time.Hour
 )
 
 type discoveryOptions struct {
@@ -55,6 +60,7 @@ type Kademlia struct {
 	nodeClient      node.Client
 	identity        *provider.FullIdentity
 	bootstrapCancel unsafe.Pointer // context.CancelFunc
+	refresh         *sync2.Ticker
 }
 
 // NewKademlia returns a newly configured Kademlia instance
@@ -97,7 +103,9 @@ func NewKademliaWithRoutingTable(self pb.Node, bootstrapNodes []pb.Node, identit
 		bootstrapNodes: bootstrapNodes,
 		address:        self.Address.Address,
 		identity:       identity,
+		refresh:        sync2.NewTicker(defaultRefreshInterval),
 	}
+	rt.onContested = k.refresh.Trigger
 
 	nc, err := node.NewNodeClient(identity, self, k)
 	if err != nil {
@@ -116,6 +124,7 @@ func (k *Kademlia) Disconnect() error {
 	if ptr != nil {
 		(*(*context.CancelFunc)(ptr))()
 	}
+	k.refresh.Stop()
 
 	return utils.CombineErrors(
 		k.nodeClient.Disconnect(),
@@ -228,6 +237,15 @@ func (k *Kademlia) Ping(ctx context.Context, node pb.Node) (pb.Node, error) {
 	return node, nil
 }
 
+// Neighbors asks the node for the nodes of its routing table closest to it
+func (k *Kademlia) Neighbors(ctx context.Context, node pb.Node) ([]*pb.Node, error) {
+	nodes, err := k.nodeClient.Lookup(ctx, node, node)
+	if err != nil {
+		return nil, NodeErr.Wrap(err)
+	}
+	return nodes, nil
+}
+
 // FindNode looks up the provided NodeID first in the local Node, and if it is not found
 // begins searching the network for the NodeID. Returns and error if node was not found
 func (k *Kademlia) FindNode(ctx context.Context, ID storj.NodeID) (pb.Node, error) {
@@ -0,0 +1,202 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package kademlia
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/utils"
+)
+
+// RunRefresh refreshes the routing table every interval until the context is
+// canceled, and as soon as a full kbucket turns away a contact. The kbuckets
+// not touched for staleAfter are refreshed.
+func (k *Kademlia) RunRefresh(ctx context.Context, interval, staleAfter time.Duration) error {
+	k.refresh.SetInterval(interval)
+	for {
+		if err := k.Refresh(ctx, staleAfter); err != nil {
+			zap.L().Warn("routing table refresh failed", zap.Error(err))
+		}
+
+		if err := k.refresh.Wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// Refresh pings the least recently seen node of each full kbucket which
+// turned away a contact, evicting it in favor of the replacement cache when
+// it doesn't respond. It then looks up a random node ID in the range of each
+// kbucket not touched for staleAfter. The kbuckets are never stale when
+// staleAfter isn't positive.
+func (k *Kademlia) Refresh(ctx context.Context, staleAfter time.Duration) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var errlist []error
+	for _, bID := range k.routingTable.takeContested() {
+		if err := k.evictLeastRecentlySeen(ctx, bID); err != nil {
+			errlist = append(errlist, err)
+		}
+	}
+
+	if staleAfter > 0 {
+		stale, err := k.routingTable.staleKBuckets(time.Now().Add(-staleAfter))
+		if err != nil {
+			errlist = append(errlist, err)
+		}
+		for _, bID := range stale {
+			if err := k.refreshKBucket(ctx, bID); err != nil {
+				errlist = append(errlist, err)
+			}
+		}
+	}
+
+	return Error.Wrap(utils.CombineErrors(errlist...))
+}
+
+// evictLeastRecentlySeen pings the least recently seen node of the kbucket,
+// and removes it from the routing table if it doesn't respond
+func (k *Kademlia) evictLeastRecentlySeen(ctx context.Context, bID bucketID) error {
+	hasRoom, err := k.routingTable.kadBucketHasRoom(bID)
+	if err != nil || hasRoom {
+		return err
+	}
+
+	lru, err := k.routingTable.leastRecentlySeen(bID)
+	if err != nil || lru == nil {
+		return err
+	}
+
+	_, err = k.Ping(ctx, *lru)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		zap.L().Debug("evicting unresponsive node", zap.Stringer("node", lru.Id), zap.Error(err))
+		return k.routingTable.ConnectionFailed(lru)
+	}
+	// the node is kept and the contact stays in the replacement cache
+	return k.routingTable.ConnectionSuccess(lru)
+}
+
+// refreshKBucket looks up a random node ID in the range of the kbucket
+func (k *Kademlia) refreshKBucket(ctx context.Context, bID bucketID) error {
+	id, err := k.routingTable.randomIDInKBucket(bID)
+	if err != nil {
+		return err
+	}
+
+	_, err = k.FindNode(ctx, id)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil && !NodeNotFound.Has(err) {
+		zap.L().Debug("kbucket refresh lookup failed", zap.Stringer("target", id), zap.Error(err))
+	}
+
+	// the kbucket isn't looked up again before it goes stale, even if the
+	// lookup failed
+	return k.routingTable.SetBucketTimestamp(bID[:], time.Now())
+}
+
+// takeContested returns the kbuckets which turned away a contact since the
+// last call
+func (rt *RoutingTable) takeContested() []bucketID {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+	var bIDs []bucketID
+	for bID := range rt.contested {
+		bIDs = append(bIDs, bID)
+	}
+	rt.contested = make(map[bucketID]bool)
+	return bIDs
+}
+
+// touchKBucket updates the timestamp of the kbucket containing the node id
+func (rt *RoutingTable) touchKBucket(nodeID storj.NodeID) error {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+	bID, err := rt.getKBucketID(nodeID)
+	if err != nil {
+		return err
+	}
+	return rt.createOrUpdateKBucket(bID, time.Now())
+}
+
+// staleKBuckets returns the kbuckets last updated before the given time
+func (rt *RoutingTable) staleKBuckets(before time.Time) ([]bucketID, error) {
+	kbuckets, err := rt.GetBucketIds()
+	if err != nil {
+		return nil, RoutingErr.New("could not get bucket ids %s", err)
+	}
+	var stale []bucketID
+	for _, k := range kbuckets {
+		timestamp, err := rt.GetBucketTimestamp(k, nil)
+		if err != nil {
+			return stale, err
+		}
+		if timestamp.Before(before) {
+			stale = append(stale, keyToBucketID(k))
+		}
+	}
+	return stale, nil
+}
+
+// leastRecentlySeen returns the node of the kbucket, other than the local
+// node, seen the longest time ago. The nodes not seen since the routing table
+// was opened come first.
+func (rt *RoutingTable) leastRecentlySeen(bID bucketID) (*pb.Node, error) {
+	nodes, err := rt.getUnmarshaledNodesFromBucket(bID)
+	if err != nil {
+		return nil, err
+	}
+
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+	var lru *pb.Node
+	var oldest time.Time
+	for _, node := range nodes {
+		if node.Id == rt.self.Id {
+			continue
+		}
+		seen := rt.lastSeen[node.Id]
+		if lru == nil || seen.Before(oldest) {
+			lru, oldest = node, seen
+		}
+	}
+	return lru, nil
+}
+
+// randomIDInKBucket returns a random node id within the range of the kbucket
+func (rt *RoutingTable) randomIDInKBucket(bID bucketID) (storj.NodeID, error) {
+	endpoints, err := rt.getKBucketRange(bID)
+	if err != nil {
+		return storj.NodeID{}, err
+	}
+	left := new(big.Int).SetBytes(endpoints[0][:])
+	right := new(big.Int).SetBytes(endpoints[1][:])
+
+	// the range excludes the left endpoint and includes the right one
+	span := new(big.Int).Sub(right, left)
+	if span.Sign() <= 0 {
+		return storj.NodeID(endpoints[1]), nil
+	}
+	offset, err := rand.Int(rand.Reader, span)
+	if err != nil {
+		return storj.NodeID{}, RoutingErr.Wrap(err)
+	}
+	offset.Add(offset, left).Add(offset, big.NewInt(1))
+
+	var id storj.NodeID
+	b := offset.Bytes()
+	copy(id[len(id)-len(b):], b)
+	return id, nil
+}
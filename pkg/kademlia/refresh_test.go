@@ -0,0 +1,216 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package kademlia
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/internal/teststorj"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+// refreshClient is a node client answering no lookups, with offline nodes
+// failing to respond to pings
+type refreshClient struct {
+	mu      sync.Mutex
+	offline map[storj.NodeID]bool
+	pinged  []storj.NodeID
+	lookups []storj.NodeID
+}
+
+func (c *refreshClient) Lookup(ctx context.Context, to pb.Node, find pb.Node) ([]*pb.Node, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lookups = append(c.lookups, find.Id)
+	return nil, nil
+}
+
+func (c *refreshClient) Ping(ctx context.Context, to pb.Node) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pinged = append(c.pinged, to.Id)
+	if c.offline[to.Id] {
+		return false, errs.New("node offline")
+	}
+	return true, nil
+}
+
+func (c *refreshClient) Disconnect() error { return nil }
+
+// fillKBucket adds contacts far from the local node until a kbucket turns one
+// away, and returns the kbucket, its least recently seen node and the contact
+func fillKBucket(t *testing.T, rt *RoutingTable) (bID bucketID, lru *pb.Node, contact *pb.Node) {
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 64; i++ {
+		node := teststorj.MockNode(string([]byte{byte(128 + i), 255}))
+		require.NoError(t, rt.ConnectionSuccess(node))
+
+		rt.mutex.Lock()
+		rt.lastSeen[node.Id] = start.Add(time.Duration(i) * time.Second)
+		rt.mutex.Unlock()
+
+		if _, err := rt.nodeBucketDB.Get(node.Id.Bytes()); err == nil {
+			continue
+		}
+
+		bID, err := rt.getKBucketID(node.Id)
+		require.NoError(t, err)
+		nodes, err := rt.getUnmarshaledNodesFromBucket(bID)
+		require.NoError(t, err)
+		require.Len(t, nodes, rt.bucketSize)
+
+		// the nodes were seen in the order they were added
+		for j := 0; j < i; j++ {
+			id := teststorj.NodeIDFromBytes([]byte{byte(128 + j), 255})
+			for _, n := range nodes {
+				if n.Id == id {
+					return bID, n, node
+				}
+			}
+		}
+	}
+	t.Fatal("no kbucket turned away a contact")
+	return bucketID{}, nil, nil
+}
+
+func TestRefreshEvictsLeastRecentlySeen(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unresponsive node is evicted", func(t *testing.T) {
+		rt, cleanup := createRoutingTable(t, teststorj.NodeIDFromBytes([]byte{0, 255}))
+		defer cleanup()
+
+		bID, lru, contact := fillKBucket(t, rt)
+		assert.Equal(t, []*pb.Node{contact}, rt.replacementCache[bID])
+
+		client := &refreshClient{offline: map[storj.NodeID]bool{lru.Id: true}}
+		k := &Kademlia{alpha: 1, routingTable: rt, nodeClient: client}
+		require.NoError(t, k.Refresh(ctx, 0))
+
+		assert.Equal(t, []storj.NodeID{lru.Id}, client.pinged)
+		_, err := rt.nodeBucketDB.Get(lru.Id.Bytes())
+		assert.Error(t, err)
+		_, err = rt.nodeBucketDB.Get(contact.Id.Bytes())
+		assert.NoError(t, err)
+		assert.Empty(t, rt.replacementCache[bID])
+
+		// the kbucket is only checked again when it turns away a contact
+		client.pinged = nil
+		require.NoError(t, k.Refresh(ctx, 0))
+		assert.Empty(t, client.pinged)
+	})
+
+	t.Run("responsive node is kept", func(t *testing.T) {
+		rt, cleanup := createRoutingTable(t, teststorj.NodeIDFromBytes([]byte{0, 255}))
+		defer cleanup()
+
+		bID, lru, contact := fillKBucket(t, rt)
+
+		client := &refreshClient{}
+		k := &Kademlia{alpha: 1, routingTable: rt, nodeClient: client}
+		require.NoError(t, k.Refresh(ctx, 0))
+
+		assert.Equal(t, []storj.NodeID{lru.Id}, client.pinged)
+		_, err := rt.nodeBucketDB.Get(lru.Id.Bytes())
+		assert.NoError(t, err)
+		_, err = rt.nodeBucketDB.Get(contact.Id.Bytes())
+		assert.Error(t, err)
+		assert.Equal(t, []*pb.Node{contact}, rt.replacementCache[bID])
+
+		// the node responding is now the most recently seen
+		next, err := rt.leastRecentlySeen(bID)
+		require.NoError(t, err)
+		assert.NotEqual(t, lru.Id, next.Id)
+	})
+}
+
+func TestRefreshStaleKBuckets(t *testing.T) {
+	ctx := context.Background()
+	rt, cleanup := createRoutingTable(t, teststorj.NodeIDFromBytes([]byte{0, 255}))
+	defer cleanup()
+	fillKBucket(t, rt)
+	rt.takeContested()
+
+	bIDs, err := rt.GetBucketIds()
+	require.NoError(t, err)
+	require.True(t, len(bIDs) > 1)
+	stale := keyToBucketID(bIDs[0])
+
+	now := time.Now()
+	for _, k := range bIDs {
+		require.NoError(t, rt.SetBucketTimestamp(k, now))
+	}
+	require.NoError(t, rt.SetBucketTimestamp(stale[:], now.Add(-2*time.Hour)))
+
+	client := &refreshClient{}
+	k := &Kademlia{alpha: 1, routingTable: rt, nodeClient: client}
+	require.NoError(t, k.Refresh(ctx, time.Hour))
+
+	// a random node in the range of the stale kbucket was looked up
+	require.NotEmpty(t, client.lookups)
+	for _, id := range client.lookups {
+		bID, err := rt.getKBucketID(id)
+		require.NoError(t, err)
+		assert.Equal(t, stale, bID)
+	}
+
+	timestamp, err := rt.GetBucketTimestamp(stale[:], nil)
+	require.NoError(t, err)
+	assert.False(t, timestamp.Before(now))
+
+	// the kbucket isn't stale anymore
+	client.lookups = nil
+	require.NoError(t, k.Refresh(ctx, time.Hour))
+	assert.Empty(t, client.lookups)
+}
+
+func TestRandomIDInKBucket(t *testing.T) {
+	rt, cleanup := createRoutingTable(t, teststorj.NodeIDFromBytes([]byte{0, 255}))
+	defer cleanup()
+	fillKBucket(t, rt)
+
+	bIDs, err := rt.GetBucketIds()
+	require.NoError(t, err)
+	require.True(t, len(bIDs) > 1)
+	for _, k := range bIDs {
+		for i := 0; i < 10; i++ {
+			id, err := rt.randomIDInKBucket(keyToBucketID(k))
+			require.NoError(t, err)
+			bID, err := rt.getKBucketID(id)
+			require.NoError(t, err)
+			assert.Equal(t, keyToBucketID(k), bID)
+		}
+	}
+}
+
+func TestConnectionSuccessTouchesKBucket(t *testing.T) {
+	id := teststorj.NodeIDFromString("AA")
+	rt, cleanup := createRoutingTable(t, id)
+	defer cleanup()
+
+	node := teststorj.MockNode("BB")
+	require.NoError(t, rt.ConnectionSuccess(node))
+	bID, err := rt.getKBucketID(node.Id)
+	require.NoError(t, err)
+
+	past := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, rt.SetBucketTimestamp(bID[:], past))
+	stale, err := rt.staleKBuckets(time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []bucketID{bID}, stale)
+
+	// contacting a node of the kbucket again touches it
+	require.NoError(t, rt.ConnectionSuccess(node))
+	stale, err = rt.staleKBuckets(time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, stale)
+}
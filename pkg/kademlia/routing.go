@@ -38,9 +38,12 @@ type RoutingTable struct {
 	transport        *pb.NodeTransport
 	mutex            *sync.Mutex
 	seen             map[storj.NodeID]*pb.Node
+	lastSeen         map[storj.NodeID]time.Time
 	replacementCache map[bucketID][]*pb.Node
-	bucketSize       int // max number of nodes stored in a kbucket = 20 (k)
-	rcBucketSize     int // replacementCache bucket max length
+	contested        map[bucketID]bool // full kbuckets which turned away a contact
+	onContested      func()            // called when a kbucket turns away a contact
+	bucketSize       int               // max number of nodes stored in a kbucket = 20 (k)
+	rcBucketSize     int               // replacementCache bucket max length
 
 }
 
@@ -54,7 +57,9 @@ func NewRoutingTable(localNode pb.Node, kdb, ndb storage.KeyValueStore) (*Routin
 
 		mutex:            &sync.Mutex{},
 		seen:             make(map[storj.NodeID]*pb.Node),
+		lastSeen:         make(map[storj.NodeID]time.Time),
 		replacementCache: make(map[bucketID][]*pb.Node),
+		contested:        make(map[bucketID]bool),
 
 		bucketSize:   *flagBucketSize,
 		rcBucketSize: *flagReplacementCacheSize,
@@ -168,6 +173,7 @@ func (rt *RoutingTable) ConnectionSuccess(node *pb.Node) error {
 
 	rt.mutex.Lock()
 	rt.seen[node.Id] = node
+	rt.lastSeen[node.Id] = time.Now()
 	rt.mutex.Unlock()
 	v, err := rt.nodeBucketDB.Get(storage.Key(node.Id.Bytes()))
 	if err != nil && !storage.ErrKeyNotFound.Has(err) {
@@ -180,6 +186,11 @@ func (rt *RoutingTable) ConnectionSuccess(node *pb.Node) error {
 			return RoutingErr.New("could not update node %s", err)
 		}
 
+		err = rt.touchKBucket(node.Id)
+		if err != nil {
+			return RoutingErr.New("could not update bucket timestamp %s", err)
+		}
+
 		return nil
 	}
 
@@ -79,6 +79,10 @@ func (rt *RoutingTable) addNode(node *pb.Node) (bool, error) {
 
 		} else {
 			rt.addToReplacementCache(kadBucketID, node)
+			rt.contested[kadBucketID] = true
+			if rt.onContested != nil {
+				rt.onContested()
+			}
 			return false, nil
 		}
 	}
@@ -118,6 +122,9 @@ func (rt *RoutingTable) removeNode(nodeID storj.NodeID) error {
 	if err != nil {
 		return RoutingErr.New("could not delete node %s", err)
 	}
+	rt.mutex.Lock()
+	delete(rt.lastSeen, nodeID)
+	rt.mutex.Unlock()
 	nodes := rt.replacementCache[kadBucketID]
 	if len(nodes) == 0 {
 		return nil
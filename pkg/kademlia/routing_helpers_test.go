@@ -34,7 +34,9 @@ func newTestRoutingTable(localNode pb.Node) (*RoutingTable, error) {
 
 		mutex:            &sync.Mutex{},
 		seen:             make(map[storj.NodeID]*pb.Node),
+		lastSeen:         make(map[storj.NodeID]time.Time),
 		replacementCache: make(map[bucketID][]*pb.Node),
+		contested:        make(map[bucketID]bool),
 
 		bucketSize:   6,
 		rcBucketSize: 2,
@@ -0,0 +1,88 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package linksharing
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"storj.io/storj/pkg/satellite/satelliteauth"
+	"storj.io/storj/pkg/storj"
+)
+
+// Access is a read-only grant to the objects of a bucket under a prefix,
+// which is encoded in the shared links. It isn't a Storj credential: it's
+// only meaningful to the link sharing servers holding the secret it's signed
+// with, which read the objects with their own credentials.
+type Access struct {
+	Bucket  string     `json:"bucket"`
+	Prefix  storj.Path `json:"prefix,omitempty"`
+	Expires time.Time  `json:"expires,omitempty"`
+}
+
+// Allows returns whether the access grants reading the object or listing
+// the prefix at path in bucket
+func (access Access) Allows(bucket string, path storj.Path) bool {
+	if bucket != access.Bucket {
+		return false
+	}
+
+	prefix := strings.TrimSuffix(access.Prefix, "/")
+	if prefix == "" {
+		return true
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// Expired returns whether the access is expired at now
+func (access Access) Expired(now time.Time) bool {
+	return !access.Expires.IsZero() && !now.Before(access.Expires)
+}
+
+// Sign encodes the access into a token signed with secret
+func (access Access) Sign(secret []byte) (string, error) {
+	payload, err := json.Marshal(access)
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+
+	signer := satelliteauth.Hmac{Secret: secret}
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+
+	return satelliteauth.Token{Payload: payload, Signature: signature}.String(), nil
+}
+
+// ParseAccess decodes the access from the token and verifies that it's
+// signed with secret and not expired
+func ParseAccess(token string, secret []byte, now time.Time) (Access, error) {
+	decoded, err := satelliteauth.FromBase64URLString(token)
+	if err != nil {
+		return Access{}, ErrAccessDenied.Wrap(err)
+	}
+
+	signer := satelliteauth.Hmac{Secret: secret}
+	expected, err := signer.Sign(decoded.Payload)
+	if err != nil {
+		return Access{}, Error.Wrap(err)
+	}
+	if !hmac.Equal(expected, decoded.Signature) {
+		return Access{}, ErrAccessDenied.New("invalid signature")
+	}
+
+	var access Access
+	if err := json.Unmarshal(decoded.Payload, &access); err != nil {
+		return Access{}, ErrAccessDenied.Wrap(err)
+	}
+
+	if access.Expired(now) {
+		return Access{}, ErrAccessDenied.New("expired at %s", access.Expires)
+	}
+
+	return access, nil
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package linksharing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccess(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Now()
+
+	access := Access{Bucket: "bucket", Prefix: "dir", Expires: now.Add(time.Hour)}
+	token, err := access.Sign(secret)
+	require.NoError(t, err)
+
+	parsed, err := ParseAccess(token, secret, now)
+	require.NoError(t, err)
+	assert.Equal(t, access.Bucket, parsed.Bucket)
+	assert.Equal(t, access.Prefix, parsed.Prefix)
+	assert.True(t, access.Expires.Equal(parsed.Expires))
+
+	_, err = ParseAccess(token, []byte("other secret"), now)
+	assert.True(t, ErrAccessDenied.Has(err))
+
+	_, err = ParseAccess(token, secret, now.Add(2*time.Hour))
+	assert.True(t, ErrAccessDenied.Has(err))
+
+	_, err = ParseAccess("invalid", secret, now)
+	assert.True(t, ErrAccessDenied.Has(err))
+
+	// tampering with the payload invalidates the signature
+	other, err := Access{Bucket: "other"}.Sign(secret)
+	require.NoError(t, err)
+	_, err = ParseAccess(other[:len(other)/2]+token[len(token)/2:], secret, now)
+	assert.Error(t, err)
+
+	for _, tt := range []struct {
+		bucket  string
+		path    string
+		allowed bool
+	}{
+		{"bucket", "dir", true},
+		{"bucket", "dir/file", true},
+		{"bucket", "dir/sub/file", true},
+		{"bucket", "dirfile", false},
+		{"bucket", "", false},
+		{"bucket", "file", false},
+		{"other", "dir/file", false},
+	} {
+		assert.Equal(t, tt.allowed, parsed.Allows(tt.bucket, tt.path), tt.bucket+"/"+tt.path)
+	}
+
+	assert.True(t, Access{Bucket: "bucket"}.Allows("bucket", ""))
+	assert.False(t, Access{Bucket: "bucket"}.Expired(now.Add(100*365*24*time.Hour)))
+}
@@ -0,0 +1,19 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package linksharing
+
+import (
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+// Error is a standard error class for this package.
+var (
+	Error = errs.Class("link sharing error")
+	mon   = monkit.Package()
+)
+
+// ErrAccessDenied is the errs class of invalid, expired or insufficient
+// access tokens
+var ErrAccessDenied = errs.Class("access denied")
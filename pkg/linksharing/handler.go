@@ -0,0 +1,278 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package linksharing
+
+import (
+	"context"
+	"html/template"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+)
+
+// Handler serves the objects of shared links over HTTP.
+//
+// The links have the form /<token>/<bucket>/<path>, where token is an Access
+// signed with the secret of the handler. Paths ending with a slash are
+// rendered as directory listings.
+//
+// The links don't carry any Storj credentials. The handler is a trusted
+// gateway reading the objects with its own API key and encryption keys, like
+// the S3 gateway, and it enforces the bucket, prefix and expiration of the
+// links itself. Anyone holding the secret can create links to any object of
+// the served buckets, so the handler only serves the buckets it's configured
+// with, and a bucket should only be served if all its content may be shared.
+type Handler struct {
+	metainfo storj.Metainfo
+	streams  streams.Store
+	secret   []byte
+	buckets  map[string]bool
+	log      *zap.Logger
+}
+
+// NewHandler creates a new link sharing handler serving the objects of the
+// given buckets
+func NewHandler(metainfo storj.Metainfo, streams streams.Store, secret []byte, buckets []string, log *zap.Logger) *Handler {
+	handler := &Handler{
+		metainfo: metainfo,
+		streams:  streams,
+		secret:   secret,
+		buckets:  make(map[string]bool),
+		log:      log,
+	}
+	for _, bucket := range buckets {
+		handler.buckets[bucket] = true
+	}
+	return handler
+}
+
+// Link returns the path of the link sharing the object or prefix at
+// objPath with access, relative to the root of the handler
+func (handler *Handler) Link(access Access, objPath storj.Path) (string, error) {
+	token, err := access.Sign(handler.secret)
+	if err != nil {
+		return "", err
+	}
+
+	link := "/" + token + "/" + url.PathEscape(access.Bucket) + "/"
+	for i, segment := range strings.Split(objPath, "/") {
+		if i > 0 {
+			link += "/"
+		}
+		link += url.PathEscape(segment)
+	}
+	return link, nil
+}
+
+// ServeHTTP implements http.Handler
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 3)
+	if len(parts) < 2 || parts[1] == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	token, bucketName := parts[0], parts[1]
+	objPath := ""
+	if len(parts) == 3 {
+		objPath = parts[2]
+	}
+
+	listing := objPath == "" || strings.HasSuffix(objPath, "/")
+	objPath = strings.TrimSuffix(objPath, "/")
+
+	access, err := ParseAccess(token, handler.secret, time.Now())
+	if err != nil {
+		handler.log.Debug("invalid access", zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	if !handler.buckets[bucketName] || !access.Allows(bucketName, objPath) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	bucket, err := handler.metainfo.GetBucket(ctx, bucketName)
+	if err != nil {
+		handler.serveError(w, r, err)
+		return
+	}
+
+	if listing {
+		err = handler.serveListing(ctx, w, r, bucket, objPath)
+		if err != nil {
+			handler.serveError(w, r, err)
+		}
+		return
+	}
+
+	object, err := handler.metainfo.GetObject(ctx, bucket.Name, objPath)
+	if err == nil {
+		err = handler.serveObject(ctx, w, r, bucket, object)
+		if err != nil {
+			handler.serveError(w, r, err)
+		}
+		return
+	}
+	if !storj.ErrObjectNotFound.Has(err) {
+		handler.serveError(w, r, err)
+		return
+	}
+
+	// object not found so maybe it's a prefix/directory
+	list, err := handler.metainfo.ListObjects(ctx, bucket.Name, storj.ListOptions{Direction: storj.After, Prefix: objPath, Limit: 1})
+	if err != nil {
+		handler.serveError(w, r, err)
+		return
+	}
+	if len(list.Items) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+}
+
+// serveObject streams the object with support for range and conditional
+// requests
+func (handler *Handler) serveObject(ctx context.Context, w http.ResponseWriter, r *http.Request, bucket storj.Bucket, object storj.Object) error {
+	rr, _, err := handler.streams.Get(ctx, storj.JoinPaths(bucket.Name, object.Path), bucket.PathCipher)
+	if err != nil {
+		return err
+	}
+
+	contentType := object.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(object.Path))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+
+	if object.ETag != "" {
+		w.Header().Set("Etag", `"`+object.ETag+`"`)
+	}
+
+	ranger.ServeContent(ctx, w, r, path.Base(object.Path), object.Modified, rr)
+	return nil
+}
+
+// listingEntry is an object or prefix in a directory listing
+type listingEntry struct {
+	Name     string
+	Link     string
+	Size     int64
+	Modified time.Time
+	IsPrefix bool
+}
+
+var listingTemplate = template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+{{if .Parent}}<tr><td><a href="../">../</a></td><td></td><td></td></tr>
+{{end}}{{range .Entries}}<tr><td><a href="{{.Link}}">{{.Name}}</a></td><td>{{if not .IsPrefix}}{{.Size}}{{end}}</td><td>{{if not .IsPrefix}}{{.Modified.Format "2006-01-02 15:04:05"}}{{end}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// serveListing renders the objects and prefixes under prefix as HTML
+func (handler *Handler) serveListing(ctx context.Context, w http.ResponseWriter, r *http.Request, bucket storj.Bucket, prefix storj.Path) error {
+	var entries []listingEntry
+
+	startAfter := ""
+	for {
+		list, err := handler.metainfo.ListObjects(ctx, bucket.Name, storj.ListOptions{
+			Direction: storj.After,
+			Cursor:    startAfter,
+			Prefix:    prefix,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, object := range list.Items {
+			// skip the object marking the directory itself
+			if object.Path == "" {
+				continue
+			}
+
+			entry := listingEntry{
+				Name:     object.Path,
+				Link:     url.PathEscape(strings.TrimSuffix(object.Path, "/")),
+				Size:     object.Size,
+				Modified: object.Modified,
+				IsPrefix: object.IsPrefix,
+			}
+			if object.IsPrefix {
+				entry.Link += "/"
+			}
+			entries = append(entries, entry)
+		}
+
+		if !list.More {
+			break
+		}
+
+		startAfter = list.Items[len(list.Items)-1].Path
+	}
+
+	if len(entries) == 0 && prefix != "" {
+		http.NotFound(w, r)
+		return nil
+	}
+
+	title := bucket.Name + "/"
+	if prefix != "" {
+		title += prefix + "/"
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	return listingTemplate.Execute(w, struct {
+		Title   string
+		Parent  bool
+		Entries []listingEntry
+	}{
+		Title:   title,
+		Parent:  prefix != "",
+		Entries: entries,
+	})
+}
+
+func (handler *Handler) serveError(w http.ResponseWriter, r *http.Request, err error) {
+	if storj.ErrBucketNotFound.Has(err) || storj.ErrObjectNotFound.Has(err) {
+		http.NotFound(w, r)
+		return
+	}
+
+	handler.log.Error("request failed", zap.String("path", r.URL.Path), zap.Error(err))
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
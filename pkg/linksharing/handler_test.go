@@ -0,0 +1,219 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package linksharing
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vivint/infectious"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/encryption"
+	"storj.io/storj/pkg/metainfo/kvmetainfo"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/ec"
+	"storj.io/storj/pkg/storage/segments"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/stream"
+)
+
+const (
+	TestAPIKey = "test-api-key"
+	TestEncKey = "test-encryption-key"
+	TestBucket = "test-bucket"
+)
+
+func TestHandler(t *testing.T) {
+	runTest(t, func(ctx context.Context, metainfo storj.Metainfo, streams streams.Store) {
+		_, err := metainfo.CreateBucket(ctx, TestBucket, &storj.Bucket{PathCipher: storj.AESGCM})
+		require.NoError(t, err)
+
+		for path, data := range map[storj.Path]string{
+			"shared/file.txt":     "hello world",
+			"shared/sub/data.bin": "data",
+			"private.txt":         "secret",
+		} {
+			createFile(ctx, t, metainfo, streams, path, data)
+		}
+
+		handler := NewHandler(metainfo, streams, []byte("secret"), []string{TestBucket}, zaptest.NewLogger(t))
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		get := func(link string, header map[string]string) (*http.Response, string) {
+			request, err := http.NewRequest(http.MethodGet, server.URL+link, nil)
+			require.NoError(t, err)
+			for key, value := range header {
+				request.Header.Set(key, value)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			defer func() { assert.NoError(t, response.Body.Close()) }()
+
+			body, err := ioutil.ReadAll(response.Body)
+			require.NoError(t, err)
+			return response, string(body)
+		}
+
+		access := Access{Bucket: TestBucket, Prefix: "shared"}
+
+		link, err := handler.Link(access, "shared/file.txt")
+		require.NoError(t, err)
+
+		response, body := get(link, nil)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "hello world", body)
+		assert.True(t, strings.HasPrefix(response.Header.Get("Content-Type"), "text/plain"))
+		etag := response.Header.Get("Etag")
+		assert.NotEmpty(t, etag)
+
+		response, body = get(link, map[string]string{"Range": "bytes=0-4"})
+		assert.Equal(t, http.StatusPartialContent, response.StatusCode)
+		assert.Equal(t, "hello", body)
+
+		response, _ = get(link, map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusNotModified, response.StatusCode)
+
+		// prefixes are rendered as listings
+		link, err = handler.Link(access, "shared/")
+		require.NoError(t, err)
+
+		response, body = get(link, nil)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Contains(t, body, `href="file.txt"`)
+		assert.Contains(t, body, `href="sub/"`)
+		assert.NotContains(t, body, "private.txt")
+
+		link, err = handler.Link(access, "shared/sub")
+		require.NoError(t, err)
+
+		response, _ = get(link, nil)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.True(t, strings.HasSuffix(response.Request.URL.Path, "/shared/sub/"))
+
+		// objects outside of the shared prefix can't be accessed
+		link, err = handler.Link(access, "private.txt")
+		require.NoError(t, err)
+
+		response, _ = get(link, nil)
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+		// links signed with another secret are rejected
+		other := NewHandler(metainfo, streams, []byte("other"), []string{TestBucket}, zaptest.NewLogger(t))
+		link, err = other.Link(access, "shared/file.txt")
+		require.NoError(t, err)
+
+		response, _ = get(link, nil)
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+		// buckets not served by the handler can't be accessed
+		_, err = metainfo.CreateBucket(ctx, "other-bucket", &storj.Bucket{PathCipher: storj.AESGCM})
+		require.NoError(t, err)
+		link, err = handler.Link(Access{Bucket: "other-bucket"}, "")
+		require.NoError(t, err)
+
+		response, _ = get(link, nil)
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+		link, err = handler.Link(access, "shared/missing.txt")
+		require.NoError(t, err)
+
+		response, _ = get(link, nil)
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	})
+}
+
+func createFile(ctx context.Context, t *testing.T, metainfo storj.Metainfo, streams streams.Store, path storj.Path, data string) {
+	object, err := metainfo.CreateObject(ctx, TestBucket, path, nil)
+	require.NoError(t, err)
+
+	mutableStream, err := object.CreateStream(ctx)
+	require.NoError(t, err)
+
+	upload := stream.NewUpload(ctx, mutableStream, streams)
+	_, err = upload.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, upload.Close())
+
+	require.NoError(t, object.Commit(ctx))
+}
+
+func runTest(t *testing.T, test func(context.Context, storj.Metainfo, streams.Store)) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 4, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	metainfo, streams, err := initEnv(planet)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	test(ctx, metainfo, streams)
+}
+
+func initEnv(planet *testplanet.Planet) (storj.Metainfo, streams.Store, error) {
+	// TODO(kaloyan): We should have a better way for configuring the Satellite's API Key
+	err := flag.Set("pointer-db.auth.api-key", TestAPIKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oc, err := planet.Uplinks[0].DialOverlay(planet.Satellites[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pdb, err := planet.Uplinks[0].DialPointerDB(planet.Satellites[0], TestAPIKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ec := ecclient.NewClient(planet.Uplinks[0].Identity, 0)
+	fc, err := infectious.NewFEC(2, 4)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rs, err := eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, int(1*memory.KB)), 3, 4)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	segments := segments.NewSegmentStore(oc, ec, pdb, rs, int(8*memory.KB))
+
+	key := new(storj.Key)
+	copy(key[:], TestEncKey)
+	keys := encryption.NewKeyStore(key, false)
+
+	streams, err := streams.NewStreamStore(segments, int64(64*memory.MB), keys, int(1*memory.KB), storj.AESGCM, 0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	buckets := buckets.NewStore(streams)
+
+	return kvmetainfo.New(buckets, streams, segments, pdb, keys), streams, nil
+}
@@ -18,7 +18,7 @@ func (db *DB) CreateBucket(ctx context.Context, bucket string, info *storj.Bucke
 		return storj.Bucket{}, storj.ErrNoBucket.New("")
 	}
 
-	meta, err := db.buckets.Put(ctx, bucket, getPathCipher(info))
+	meta, err := db.buckets.Put(ctx, bucket, metaFromInfo(info))
 	if err != nil {
 		return storj.Bucket{}, err
 	}
@@ -34,6 +34,7 @@ func (db *DB) DeleteBucket(ctx context.Context, bucket string) (err error) {
 		return storj.ErrNoBucket.New("")
 	}
 
+	defer db.listings.invalidate(bucket)
 	return db.buckets.Delete(ctx, bucket)
 }
 
@@ -97,17 +98,31 @@ func (db *DB) ListBuckets(ctx context.Context, options storj.BucketListOptions)
 	return list, nil
 }
 
-func getPathCipher(info *storj.Bucket) storj.Cipher {
+func metaFromInfo(info *storj.Bucket) buckets.Meta {
 	if info == nil {
-		return storj.AESGCM
+		return buckets.Meta{PathEncryptionType: storj.AESGCM}
+	}
+	return buckets.Meta{
+		PathEncryptionType: info.PathCipher,
+		SegmentsSize:       info.SegmentsSize,
+		InlineThreshold:    info.InlineThreshold,
+		RedundancyScheme:   info.RedundancyScheme,
+		EncryptionScheme:   info.EncryptionScheme,
+		Compression:        info.Compression,
+		Lifecycle:          info.Lifecycle,
 	}
-	return info.PathCipher
 }
 
 func bucketFromMeta(bucket string, meta buckets.Meta) storj.Bucket {
 	return storj.Bucket{
-		Name:       bucket,
-		Created:    meta.Created,
-		PathCipher: meta.PathEncryptionType,
+		Name:             bucket,
+		Created:          meta.Created,
+		PathCipher:       meta.PathEncryptionType,
+		SegmentsSize:     meta.SegmentsSize,
+		InlineThreshold:  meta.InlineThreshold,
+		RedundancyScheme: meta.RedundancyScheme,
+		EncryptionScheme: meta.EncryptionScheme,
+		Compression:      meta.Compression,
+		Lifecycle:        meta.Lifecycle,
 	}
 }
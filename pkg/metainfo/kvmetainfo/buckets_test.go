@@ -16,6 +16,7 @@ import (
 	"storj.io/storj/internal/testcontext"
 	"storj.io/storj/internal/testplanet"
 	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/encryption"
 	"storj.io/storj/pkg/storage/buckets"
 	"storj.io/storj/pkg/storage/ec"
 	"storj.io/storj/pkg/storage/segments"
@@ -72,7 +73,7 @@ func TestBucketsBasic(t *testing.T) {
 func TestBucketsReadNewWayWriteOldWay(t *testing.T) {
 	runTest(t, func(ctx context.Context, db *DB) {
 		// (Old API) Create new bucket
-		_, err := db.buckets.Put(ctx, TestBucket, storj.AESGCM)
+		_, err := db.buckets.Put(ctx, TestBucket, buckets.Meta{PathEncryptionType: storj.AESGCM})
 		assert.NoError(t, err)
 
 		// (New API) Check that bucket list include the new bucket
@@ -178,6 +179,62 @@ func TestBucketCreateCipher(t *testing.T) {
 	})
 }
 
+func TestBucketDefaults(t *testing.T) {
+	runTest(t, func(ctx context.Context, db *DB) {
+		info := storj.Bucket{
+			PathCipher:      storj.SecretBox,
+			SegmentsSize:    int64(16 * memory.KB),
+			InlineThreshold: int(1 * memory.KB),
+			RedundancyScheme: storj.RedundancyScheme{
+				Algorithm:      storj.ReedSolomon,
+				RequiredShares: 1,
+				RepairShares:   2,
+				OptimalShares:  3,
+				TotalShares:    4,
+				ShareSize:      1 * memory.KB.Int32(),
+			},
+			EncryptionScheme: storj.EncryptionScheme{
+				Cipher:    storj.XChaCha20Poly1305,
+				BlockSize: 1 * memory.KB.Int32(),
+			},
+			Compression: storj.Gzip,
+		}
+
+		assertDefaults := func(bucket storj.Bucket) {
+			assert.Equal(t, info.PathCipher, bucket.PathCipher)
+			assert.Equal(t, info.SegmentsSize, bucket.SegmentsSize)
+			assert.Equal(t, info.InlineThreshold, bucket.InlineThreshold)
+			assert.Equal(t, info.RedundancyScheme, bucket.RedundancyScheme)
+			assert.Equal(t, info.EncryptionScheme, bucket.EncryptionScheme)
+			assert.Equal(t, info.Compression, bucket.Compression)
+		}
+
+		bucket, err := db.CreateBucket(ctx, TestBucket, &info)
+		if assert.NoError(t, err) {
+			assertDefaults(bucket)
+		}
+
+		bucket, err = db.GetBucket(ctx, TestBucket)
+		if assert.NoError(t, err) {
+			assertDefaults(bucket)
+		}
+
+		list, err := db.ListBuckets(ctx, storj.BucketListOptions{Direction: storj.After})
+		if assert.NoError(t, err) && assert.Len(t, list.Items, 1) {
+			assertDefaults(list.Items[0])
+		}
+
+		// buckets without defaults have zero values
+		bucket, err = db.CreateBucket(ctx, "no-defaults", nil)
+		if assert.NoError(t, err) {
+			assert.Zero(t, bucket.SegmentsSize)
+			assert.Zero(t, bucket.InlineThreshold)
+			assert.True(t, bucket.RedundancyScheme.IsZero())
+			assert.True(t, bucket.EncryptionScheme.IsZero())
+		}
+	})
+}
+
 func TestListBucketsEmpty(t *testing.T) {
 	runTest(t, func(ctx context.Context, db *DB) {
 		_, err := db.ListBuckets(ctx, storj.BucketListOptions{})
@@ -360,15 +417,16 @@ func newDB(planet *testplanet.Planet) (*DB, error) {
 
 	key := new(storj.Key)
 	copy(key[:], TestEncKey)
+	keys := encryption.NewKeyStore(key, false)
 
-	streams, err := streams.NewStreamStore(segments, int64(64*memory.MB), key, int(1*memory.KB), storj.AESGCM)
+	streams, err := streams.NewStreamStore(segments, int64(64*memory.MB), keys, int(1*memory.KB), storj.AESGCM, 0, 0)
 	if err != nil {
 		return nil, err
 	}
 
 	buckets := buckets.NewStore(streams)
 
-	return New(buckets, streams, segments, pdb, key), nil
+	return New(buckets, streams, segments, pdb, keys), nil
 }
 
 func forAllCiphers(test func(cipher storj.Cipher)) {
@@ -376,6 +434,7 @@ func forAllCiphers(test func(cipher storj.Cipher)) {
 		storj.Unencrypted,
 		storj.AESGCM,
 		storj.SecretBox,
+		storj.XChaCha20Poly1305,
 	} {
 		test(cipher)
 	}
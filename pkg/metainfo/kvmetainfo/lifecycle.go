@@ -0,0 +1,88 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package kvmetainfo
+
+import (
+	"context"
+	"time"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// SetBucketLifecycle replaces the lifecycle rules of the bucket. The rules
+// apply to the objects created afterwards, use ApplyBucketLifecycle to
+// delete the existing objects past their rules.
+func (db *DB) SetBucketLifecycle(ctx context.Context, bucket string, rules []storj.LifecycleRule) (bucketInfo storj.Bucket, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if bucket == "" {
+		return storj.Bucket{}, storj.ErrNoBucket.New("")
+	}
+
+	for _, rule := range rules {
+		if rule.Days <= 0 {
+			return storj.Bucket{}, errClass.New("invalid expiration of %d days for prefix %q", rule.Days, rule.Prefix)
+		}
+	}
+
+	meta, err := db.buckets.Get(ctx, bucket)
+	if err != nil {
+		return storj.Bucket{}, err
+	}
+
+	meta.Lifecycle = rules
+	meta, err = db.buckets.Put(ctx, bucket, meta)
+	if err != nil {
+		return storj.Bucket{}, err
+	}
+
+	return bucketFromMeta(bucket, meta), nil
+}
+
+// ApplyBucketLifecycle deletes the existing objects of the bucket, which
+// don't expire yet, if they are older than the lifecycle rules of the bucket
+// allow at now. Their expiration isn't changed instead, as the storage nodes
+// only learn the expiration of the pieces at upload time.
+func (db *DB) ApplyBucketLifecycle(ctx context.Context, bucket string, now time.Time) (deleted int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	bucketInfo, err := db.GetBucket(ctx, bucket)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(bucketInfo.Lifecycle) == 0 {
+		return 0, nil
+	}
+
+	options := storj.ListOptions{Direction: storj.After, Recursive: true}
+	for {
+		list, err := db.ListObjects(ctx, bucket, options)
+		if err != nil {
+			return deleted, err
+		}
+
+		for _, item := range list.Items {
+			if item.IsPrefix || !item.Expires.IsZero() {
+				continue
+			}
+
+			expires := bucketInfo.Expiration(item.Path, item.Created)
+			if expires.IsZero() || expires.After(now) {
+				continue
+			}
+
+			err = db.DeleteObject(ctx, bucket, item.Path)
+			if err != nil && !storj.ErrObjectNotFound.Has(err) {
+				return deleted, err
+			}
+			deleted++
+		}
+
+		if !list.More || len(list.Items) == 0 {
+			return deleted, nil
+		}
+		options = options.NextPage(list)
+	}
+}
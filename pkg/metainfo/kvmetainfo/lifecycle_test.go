@@ -0,0 +1,114 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package kvmetainfo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/storj"
+)
+
+func TestSetBucketLifecycle(t *testing.T) {
+	runTest(t, func(ctx context.Context, db *DB) {
+		created, err := db.CreateBucket(ctx, TestBucket, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_, err = db.SetBucketLifecycle(ctx, TestBucket, []storj.LifecycleRule{{Prefix: "logs/", Days: 0}})
+		assert.Error(t, err)
+
+		_, err = db.SetBucketLifecycle(ctx, "", nil)
+		assert.True(t, storj.ErrNoBucket.Has(err))
+
+		rules := []storj.LifecycleRule{
+			{ID: "logs", Prefix: "logs/", Days: 7},
+			{ID: "all", Prefix: "", Days: 30},
+		}
+
+		bucket, err := db.SetBucketLifecycle(ctx, TestBucket, rules)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, rules, bucket.Lifecycle)
+
+		bucket, err = db.GetBucket(ctx, TestBucket)
+		if assert.NoError(t, err) {
+			assert.Equal(t, rules, bucket.Lifecycle)
+			assert.Equal(t, created.PathCipher, bucket.PathCipher)
+			assert.True(t, created.Created.Equal(bucket.Created))
+		}
+
+		bucket, err = db.SetBucketLifecycle(ctx, TestBucket, nil)
+		if assert.NoError(t, err) {
+			assert.Empty(t, bucket.Lifecycle)
+		}
+	})
+}
+
+func TestApplyBucketLifecycle(t *testing.T) {
+	runTest(t, func(ctx context.Context, db *DB) {
+		bucket, err := db.CreateBucket(ctx, TestBucket, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		upload(ctx, t, db, bucket, "logs/old", []byte("old log"))
+		upload(ctx, t, db, bucket, "data/old", []byte("old data"))
+
+		later := time.Now().AddDate(0, 0, 2)
+		deleted, err := db.ApplyBucketLifecycle(ctx, TestBucket, later)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0, deleted)
+		}
+
+		bucket, err = db.SetBucketLifecycle(ctx, TestBucket, []storj.LifecycleRule{{Prefix: "logs/", Days: 1}})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// new objects expire according to the rules
+		upload(ctx, t, db, bucket, "logs/new", []byte("new log"))
+		object, err := db.GetObject(ctx, TestBucket, "logs/new")
+		if assert.NoError(t, err) {
+			assertExpires(t, time.Now().AddDate(0, 0, 1), object.Expires)
+		}
+
+		// the existing objects keep their expiration
+		object, err = db.GetObject(ctx, TestBucket, "logs/old")
+		if assert.NoError(t, err) {
+			assert.True(t, object.Expires.IsZero())
+		}
+
+		// the existing objects aren't deleted before their rule allows
+		deleted, err = db.ApplyBucketLifecycle(ctx, TestBucket, time.Now())
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0, deleted)
+		}
+
+		// only the existing objects without expiration are deleted, the new
+		// ones expire by themselves
+		deleted, err = db.ApplyBucketLifecycle(ctx, TestBucket, later)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, deleted)
+		}
+
+		_, err = db.GetObject(ctx, TestBucket, "logs/old")
+		assert.True(t, storj.ErrObjectNotFound.Has(err))
+
+		_, err = db.GetObject(ctx, TestBucket, "data/old")
+		assert.NoError(t, err)
+
+		_, err = db.GetObject(ctx, TestBucket, "logs/new")
+		assert.NoError(t, err)
+	})
+}
+
+func assertExpires(t *testing.T, expected, actual time.Time) {
+	assert.WithinDuration(t, expected, actual, time.Minute)
+}
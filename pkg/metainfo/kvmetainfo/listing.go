@@ -0,0 +1,250 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package kvmetainfo
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+// isDirPrefix returns whether the prefix selects whole path components
+func isDirPrefix(prefix storj.Path) bool {
+	return prefix == "" || strings.HasSuffix(prefix, "/")
+}
+
+// cursorPrefix returns the prefix collapsed on '/' containing the cursor of
+// a forward listing, which the store lists even though it's before the cursor
+func cursorPrefix(options storj.ListOptions) storj.Path {
+	if options.Delimiter == 0 || options.Recursive {
+		return ""
+	}
+	if options.Direction != storj.Forward && options.Direction != storj.After {
+		return ""
+	}
+	i := strings.IndexByte(options.Cursor, '/')
+	if i < 0 {
+		return ""
+	}
+	return options.Cursor[:i+1]
+}
+
+// listingTTL is how long the sorted listings are reused for the next pages.
+// The changes made through other databases are listed once it passes.
+const listingTTL = time.Minute
+
+// maxListings is the maximum number of cached listings
+const maxListings = 16
+
+// listingKey identifies the listings sharing their sorted items
+type listingKey struct {
+	bucket    string
+	prefix    storj.Path
+	delimiter rune
+	recursive bool
+}
+
+type cachedListing struct {
+	items   []storj.Object
+	created time.Time
+}
+
+// listingCache keeps the sorted items of lexical listings between their
+// pages, so the store is read once per listing instead of once per page
+type listingCache struct {
+	mu       sync.Mutex
+	listings map[listingKey]cachedListing
+}
+
+func newListingCache() *listingCache {
+	return &listingCache{listings: make(map[listingKey]cachedListing)}
+}
+
+// get returns the sorted items of the listing, or false if it isn't cached
+// or expired
+func (cache *listingCache) get(key listingKey, now time.Time) ([]storj.Object, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	listing, ok := cache.listings[key]
+	if !ok || now.Sub(listing.created) >= listingTTL {
+		return nil, false
+	}
+	return listing.items, true
+}
+
+// put caches the sorted items of the listing, evicting the oldest listing
+// if the cache is full
+func (cache *listingCache) put(key listingKey, items []storj.Object, now time.Time) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if _, ok := cache.listings[key]; !ok && len(cache.listings) >= maxListings {
+		var oldest listingKey
+		var oldestCreated time.Time
+		for key, listing := range cache.listings {
+			if oldestCreated.IsZero() || listing.created.Before(oldestCreated) {
+				oldest, oldestCreated = key, listing.created
+			}
+		}
+		delete(cache.listings, oldest)
+	}
+	cache.listings[key] = cachedListing{items: items, created: now}
+}
+
+// invalidate drops the listings of the bucket after its objects changed
+func (cache *listingCache) invalidate(bucket string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for key := range cache.listings {
+		if key.bucket == bucket {
+			delete(cache.listings, key)
+		}
+	}
+}
+
+// listLexical lists the objects in the lexical order of their unencrypted
+// paths. The store lists whole path components in the order of the encrypted
+// paths, so the directory containing the prefix is listed entirely, and its
+// sorted items are cached for the next pages.
+func (db *DB) listLexical(ctx context.Context, bucket storj.Bucket, store objects.Store, options storj.ListOptions) (list storj.ObjectList, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	switch options.Direction {
+	case storj.Before, storj.Backward, storj.Forward, storj.After:
+	default:
+		return storj.ObjectList{}, errClass.New("invalid direction %d", options.Direction)
+	}
+
+	list = storj.ObjectList{
+		Bucket: bucket.Name,
+		Prefix: options.Prefix,
+	}
+
+	// without a delimiter the prefix is a directory, like for the store
+	if options.Delimiter == 0 {
+		options.Delimiter = '/'
+		if !isDirPrefix(options.Prefix) {
+			options.Prefix += "/"
+		}
+	}
+
+	key := listingKey{
+		bucket:    bucket.Name,
+		prefix:    options.Prefix,
+		delimiter: options.Delimiter,
+		recursive: options.Recursive,
+	}
+
+	now := time.Now()
+	items, ok := db.listings.get(key, now)
+	if !ok {
+		items, err = listSorted(ctx, bucket, store, options)
+		if err != nil {
+			return storj.ObjectList{}, err
+		}
+		db.listings.put(key, items, now)
+	}
+
+	limit := options.Limit
+	if limit <= 0 || limit > storage.LookupLimit {
+		limit = storage.LookupLimit
+	}
+
+	cursor := options.Cursor
+	switch options.Direction {
+	case storj.Forward, storj.After:
+		start := sort.Search(len(items), func(i int) bool {
+			if options.Direction == storj.Forward || cursor == "" {
+				return items[i].Path >= cursor
+			}
+			return items[i].Path > cursor
+		})
+		items = items[start:]
+		if len(items) > limit {
+			list.More = true
+			items = items[:limit]
+		}
+	case storj.Before, storj.Backward:
+		end := len(items)
+		if cursor != "" {
+			end = sort.Search(len(items), func(i int) bool {
+				if options.Direction == storj.Backward {
+					return items[i].Path > cursor
+				}
+				return items[i].Path >= cursor
+			})
+		}
+		items = items[:end]
+		if len(items) > limit {
+			list.More = true
+			items = items[len(items)-limit:]
+		}
+	}
+
+	// the cached items are shared by the pages
+	list.Items = append([]storj.Object(nil), items...)
+	return list, nil
+}
+
+// listSorted lists all items of a lexical listing sorted by their paths
+func listSorted(ctx context.Context, bucket storj.Bucket, store objects.Store, options storj.ListOptions) (items []storj.Object, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	dir := options.Prefix[:strings.LastIndex(options.Prefix, "/")+1]
+	// the store collapses the paths on '/', which is enough when the
+	// delimiter is '/' too
+	recursive := options.Recursive || options.Delimiter != '/'
+
+	prefixes := make(map[storj.Path]bool)
+
+	startAfter := ""
+	for {
+		page, more, err := store.List(ctx, dir, startAfter, "", recursive, 0, meta.All)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page {
+			path := dir + item.Path
+			if !strings.HasPrefix(path, options.Prefix) {
+				continue
+			}
+			relative := path[len(options.Prefix):]
+
+			if !options.Recursive {
+				if i := strings.IndexRune(relative, options.Delimiter); i >= 0 {
+					prefix := relative[:i+utf8.RuneLen(options.Delimiter)]
+					if !prefixes[prefix] {
+						prefixes[prefix] = true
+						items = append(items, objectFromMeta(bucket, prefix, true, objects.Meta{}))
+					}
+					continue
+				}
+			}
+
+			items = append(items, objectFromMeta(bucket, relative, item.IsPrefix, item.Meta))
+		}
+
+		if !more || len(page) == 0 {
+			break
+		}
+		startAfter = page[len(page)-1].Path
+	}
+
+	sort.Slice(items, func(i, k int) bool {
+		return items[i].Path < items[k].Path
+	})
+
+	return items, nil
+}
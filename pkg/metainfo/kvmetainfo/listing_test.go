@@ -0,0 +1,52 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package kvmetainfo
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/storj"
+)
+
+func TestListingCache(t *testing.T) {
+	cache := newListingCache()
+	now := time.Now()
+
+	key := listingKey{bucket: "bucket", prefix: "a", delimiter: '/'}
+	items := []storj.Object{{Path: "a"}, {Path: "b"}}
+
+	_, ok := cache.get(key, now)
+	assert.False(t, ok)
+
+	cache.put(key, items, now)
+	cached, ok := cache.get(key, now.Add(listingTTL/2))
+	assert.True(t, ok)
+	assert.Equal(t, items, cached)
+
+	// the listings expire
+	_, ok = cache.get(key, now.Add(listingTTL))
+	assert.False(t, ok)
+
+	// the listings of a bucket are dropped when its objects change
+	other := listingKey{bucket: "other"}
+	cache.put(key, items, now)
+	cache.put(other, items, now)
+	cache.invalidate("bucket")
+	_, ok = cache.get(key, now)
+	assert.False(t, ok)
+	_, ok = cache.get(other, now)
+	assert.True(t, ok)
+
+	// the oldest listings are evicted
+	for i := 0; i < maxListings; i++ {
+		cache.put(listingKey{bucket: fmt.Sprint(i)}, items, now.Add(time.Duration(i+1)*time.Millisecond))
+	}
+	assert.Len(t, cache.listings, maxListings)
+	_, ok = cache.get(other, now)
+	assert.False(t, ok)
+}
@@ -8,6 +8,7 @@ import (
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
 	"storj.io/storj/internal/memory"
+	"storj.io/storj/pkg/encryption"
 	"storj.io/storj/pkg/pointerdb/pdbclient"
 	"storj.io/storj/pkg/storage/buckets"
 	"storj.io/storj/pkg/storage/segments"
@@ -31,20 +32,36 @@ type DB struct {
 	segments segments.Store
 	pointers pdbclient.Client
 
-	rootKey *storj.Key
+	keys *encryption.KeyStore
+
+	redundancy storj.RedundancyScheme
+	encryption storj.EncryptionScheme
+
+	listings *listingCache
 }
 
 // New creates a new metainfo database
-func New(buckets buckets.Store, streams streams.Store, segments segments.Store, pointers pdbclient.Client, rootKey *storj.Key) *DB {
+func New(buckets buckets.Store, streams streams.Store, segments segments.Store, pointers pdbclient.Client, keys *encryption.KeyStore) *DB {
 	return &DB{
 		buckets:  buckets,
 		streams:  streams,
 		segments: segments,
 		pointers: pointers,
-		rootKey:  rootKey,
+		keys:     keys,
+		listings: newListingCache(),
 	}
 }
 
+// WithDefaults returns a copy of the metainfo database creating the objects
+// with the given schemes when neither the objects nor their buckets have
+// their own
+func (db *DB) WithDefaults(redundancy storj.RedundancyScheme, encryption storj.EncryptionScheme) *DB {
+	clone := *db
+	clone.redundancy = redundancy
+	clone.encryption = encryption
+	return &clone
+}
+
 // Limits returns limits for this metainfo database
 func (db *DB) Limits() (storj.MetainfoLimits, error) {
 	return storj.MetainfoLimits{
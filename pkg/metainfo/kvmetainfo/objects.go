@@ -61,7 +61,12 @@ func (db *DB) GetObjectStream(ctx context.Context, bucket string, path storj.Pat
 		return nil, err
 	}
 
-	streamKey, err := encryption.DeriveContentKey(meta.fullpath, db.rootKey)
+	rootKey, err := db.keys.RootKey(meta.fullpath)
+	if err != nil {
+		return nil, err
+	}
+
+	streamKey, err := encryption.DeriveContentKey(meta.fullpath, rootKey)
 	if err != nil {
 		return nil, err
 	}
@@ -98,11 +103,38 @@ func (db *DB) CreateObject(ctx context.Context, bucket string, path storj.Path,
 		info.Expires = createInfo.Expires
 		info.RedundancyScheme = createInfo.RedundancyScheme
 		info.EncryptionScheme = createInfo.EncryptionScheme
+		info.Compression = createInfo.Compression
 	}
 
 	// TODO: autodetect content type from the path extension
 	// if info.ContentType == "" {}
 
+	if info.Expires.IsZero() {
+		info.Expires = bucketInfo.Expiration(path, time.Now())
+	}
+
+	if info.RedundancyScheme.IsZero() {
+		info.RedundancyScheme = bucketInfo.RedundancyScheme
+	}
+
+	if info.EncryptionScheme.IsZero() {
+		info.EncryptionScheme = bucketInfo.EncryptionScheme
+	}
+
+	if createInfo != nil && createInfo.DisableCompression {
+		info.Compression = storj.Uncompressed
+	} else if info.Compression == storj.Uncompressed {
+		info.Compression = bucketInfo.Compression
+	}
+
+	if info.RedundancyScheme.IsZero() {
+		info.RedundancyScheme = db.redundancy
+	}
+
+	if info.EncryptionScheme.IsZero() {
+		info.EncryptionScheme = db.encryption
+	}
+
 	if info.RedundancyScheme.IsZero() {
 		info.RedundancyScheme = defaultRS
 	}
@@ -135,6 +167,7 @@ func (db *DB) DeleteObject(ctx context.Context, bucket string, path storj.Path)
 		return err
 	}
 
+	defer db.listings.invalidate(bucket)
 	return store.Delete(ctx, path)
 }
 
@@ -164,6 +197,14 @@ func (db *DB) ListObjects(ctx context.Context, bucket string, options storj.List
 		return storj.ObjectList{}, err
 	}
 
+	// the store lists the paths under a directory collapsed on '/', a page
+	// at a time in the order of the encrypted paths. That's the lexical order
+	// only for unencrypted paths, other delimiters and prefixes ending inside
+	// a path component need the whole directory too.
+	if bucketInfo.PathCipher != storj.Unencrypted || (options.Delimiter != 0 && (options.Delimiter != '/' || !isDirPrefix(options.Prefix))) {
+		return db.listLexical(ctx, bucketInfo, objects, options)
+	}
+
 	var startAfter, endBefore string
 	switch options.Direction {
 	case storj.Before:
@@ -187,11 +228,27 @@ func (db *DB) ListObjects(ctx context.Context, bucket string, options storj.List
 		endBefore = "\x7f\x7f\x7f\x7f\x7f\x7f\x7f"
 	}
 
-	items, more, err := objects.List(ctx, options.Prefix, startAfter, endBefore, options.Recursive, options.Limit, meta.All)
+	// the store lists the prefix containing the cursor again, which comes
+	// first and is dropped, so one more item is listed
+	limit := options.Limit
+	skip := cursorPrefix(options)
+	if skip != "" && limit > 0 {
+		limit++
+	}
+
+	items, more, err := objects.List(ctx, options.Prefix, startAfter, endBefore, options.Recursive, limit, meta.All)
 	if err != nil {
 		return storj.ObjectList{}, err
 	}
 
+	if skip != "" {
+		if len(items) > 0 && items[0].IsPrefix && items[0].Path == skip {
+			items = items[1:]
+		} else if options.Limit > 0 && len(items) > options.Limit {
+			items, more = items[:options.Limit], true
+		}
+	}
+
 	list = storj.ObjectList{
 		Bucket: bucket,
 		Prefix: options.Prefix,
@@ -228,7 +285,12 @@ func (db *DB) getInfo(ctx context.Context, prefix string, bucket string, path st
 
 	fullpath := bucket + "/" + path
 
-	encryptedPath, err := streams.EncryptAfterBucket(fullpath, bucketInfo.PathCipher, db.rootKey)
+	rootKey, err := db.keys.RootKey(fullpath)
+	if err != nil {
+		return object{}, storj.Object{}, err
+	}
+
+	encryptedPath, err := streams.EncryptAfterBucket(fullpath, bucketInfo.PathCipher, rootKey)
 	if err != nil {
 		return object{}, storj.Object{}, err
 	}
@@ -263,7 +325,7 @@ func (db *DB) getInfo(ctx context.Context, prefix string, bucket string, path st
 		Data:       pointer.GetMetadata(),
 	}
 
-	streamInfoData, err := streams.DecryptStreamInfo(ctx, lastSegmentMeta, fullpath, db.rootKey)
+	streamInfoData, err := streams.DecryptStreamInfo(ctx, lastSegmentMeta, fullpath, rootKey)
 	if err != nil {
 		return object{}, storj.Object{}, err
 	}
@@ -311,6 +373,7 @@ func objectFromMeta(bucket storj.Bucket, path storj.Path, isPrefix bool, meta ob
 		Stream: storj.Stream{
 			Size:     meta.Size,
 			Checksum: []byte(meta.Checksum),
+			ETag:     meta.ETag,
 		},
 	}
 }
@@ -325,6 +388,11 @@ func objectStreamFromMeta(bucket storj.Bucket, path storj.Path, lastSegment segm
 		return storj.Object{}, err
 	}
 
+	size := stream.SegmentsSize*(stream.NumberOfSegments-1) + stream.LastSegmentSize
+	if storj.Compression(stream.Compression) != storj.Uncompressed {
+		size = stream.UncompressedSize
+	}
+
 	return storj.Object{
 		Version:  0, // TODO:
 		Bucket:   bucket,
@@ -339,8 +407,9 @@ func objectStreamFromMeta(bucket storj.Bucket, path storj.Path, lastSegment segm
 		Expires:     lastSegment.Expiration, // TODO: use correct field
 
 		Stream: storj.Stream{
-			Size: stream.SegmentsSize*(stream.NumberOfSegments-1) + stream.LastSegmentSize,
-			// Checksum: []byte(object.Checksum),
+			Size:     size,
+			Checksum: stream.Checksum,
+			ETag:     stream.Etag,
 
 			SegmentCount:     stream.NumberOfSegments,
 			FixedSegmentSize: stream.SegmentsSize,
@@ -357,6 +426,7 @@ func objectStreamFromMeta(bucket storj.Bucket, path storj.Path, lastSegment segm
 				Cipher:    storj.Cipher(streamMeta.EncryptionType),
 				BlockSize: streamMeta.EncryptionBlockSize,
 			},
+			Compression: storj.Compression(stream.Compression),
 			LastSegment: storj.LastSegment{
 				Size:              stream.LastSegmentSize,
 				EncryptedKeyNonce: nonce,
@@ -401,6 +471,8 @@ func (object *mutableObject) DeleteStream(ctx context.Context) error {
 }
 
 func (object *mutableObject) Commit(ctx context.Context) error {
+	object.db.listings.invalidate(object.info.Bucket.Name)
+
 	_, info, err := object.db.getInfo(ctx, committedPrefix, object.info.Bucket.Name, object.info.Path)
 	object.info = info
 	return err
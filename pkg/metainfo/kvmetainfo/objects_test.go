@@ -5,9 +5,13 @@ package kvmetainfo
 
 import (
 	"context"
+	"crypto/md5"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"testing"
 	"time"
 
@@ -80,6 +84,45 @@ func TestCreateObject(t *testing.T) {
 			assert.Equal(t, tt.expectedRS, info.RedundancyScheme, errTag)
 			assert.Equal(t, tt.expectedES, info.EncryptionScheme, errTag)
 		}
+
+		// the defaults of the database apply to the buckets without their own
+		withDefaults := db.WithDefaults(customRS, customES)
+		obj, err := withDefaults.CreateObject(ctx, bucket.Name, TestFile, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, customRS, obj.Info().RedundancyScheme)
+		assert.Equal(t, customES, obj.Info().EncryptionScheme)
+
+		// the defaults of the bucket take precedence over the ones of the database
+		bucketES := storj.EncryptionScheme{Cipher: storj.SecretBox, BlockSize: 2 * memory.KB.Int32()}
+		withScheme, err := db.CreateBucket(ctx, TestBucket+"-schemes", &storj.Bucket{
+			RedundancyScheme: defaultRS,
+			EncryptionScheme: bucketES,
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+		obj, err = withDefaults.CreateObject(ctx, withScheme.Name, TestFile, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, defaultRS, obj.Info().RedundancyScheme)
+		assert.Equal(t, bucketES, obj.Info().EncryptionScheme)
+
+		// the objects can opt out of the default compression of the bucket
+		compressed, err := db.CreateBucket(ctx, TestBucket+"-compressed", &storj.Bucket{Compression: storj.Gzip})
+		if !assert.NoError(t, err) {
+			return
+		}
+		obj, err = db.CreateObject(ctx, compressed.Name, TestFile, nil)
+		if assert.NoError(t, err) {
+			assert.Equal(t, storj.Gzip, obj.Info().Compression)
+		}
+		obj, err = db.CreateObject(ctx, compressed.Name, TestFile, &storj.CreateObject{DisableCompression: true})
+		if assert.NoError(t, err) {
+			assert.Equal(t, storj.Uncompressed, obj.Info().Compression)
+		}
 	})
 }
 
@@ -151,6 +194,68 @@ func TestGetObjectStream(t *testing.T) {
 	})
 }
 
+func TestBucketDefaultsUpload(t *testing.T) {
+	runTest(t, func(ctx context.Context, db *DB) {
+		// we wait a second for all the nodes to complete bootstrapping off the satellite
+		time.Sleep(2 * time.Second)
+
+		info := storj.Bucket{
+			PathCipher:      storj.AESGCM,
+			SegmentsSize:    int64(16 * memory.KB),
+			InlineThreshold: int(1 * memory.KB),
+			RedundancyScheme: storj.RedundancyScheme{
+				Algorithm:      storj.ReedSolomon,
+				RequiredShares: 1,
+				RepairShares:   2,
+				OptimalShares:  3,
+				TotalShares:    4,
+				ShareSize:      2 * memory.KB.Int32(),
+			},
+			EncryptionScheme: storj.EncryptionScheme{
+				Cipher:    storj.SecretBox,
+				BlockSize: 2 * memory.KB.Int32(),
+			},
+		}
+
+		bucket, err := db.CreateBucket(ctx, TestBucket, &info)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		obj, err := db.CreateObject(ctx, bucket.Name, TestFile, nil)
+		if assert.NoError(t, err) {
+			assert.Equal(t, info.RedundancyScheme, obj.Info().RedundancyScheme)
+			assert.Equal(t, info.EncryptionScheme, obj.Info().EncryptionScheme)
+		}
+
+		// the last segment is remote so the object has its redundancy scheme
+		data := make([]byte, 40*memory.KB)
+		_, err = rand.Read(data)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		upload(ctx, t, db, bucket, TestFile, data)
+
+		readOnly, err := db.GetObjectStream(ctx, bucket.Name, TestFile)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		object := readOnly.Info()
+		assert.Equal(t, int64(3), object.SegmentCount)
+		assert.Equal(t, info.SegmentsSize, object.FixedSegmentSize)
+		assert.Equal(t, info.RedundancyScheme, object.RedundancyScheme)
+		assert.Equal(t, info.EncryptionScheme, object.EncryptionScheme)
+
+		download := stream.NewDownload(ctx, readOnly, db.streams)
+		downloaded, err := ioutil.ReadAll(download)
+		assert.NoError(t, err)
+		assert.NoError(t, download.Close())
+		assert.Equal(t, data, downloaded)
+	})
+}
+
 func upload(ctx context.Context, t *testing.T, db *DB, bucket storj.Bucket, path storj.Path, data []byte) {
 	obj, err := db.CreateObject(ctx, bucket.Name, path, nil)
 	if !assert.NoError(t, err) {
@@ -190,6 +295,11 @@ func assertStream(ctx context.Context, t *testing.T, db *DB, bucket storj.Bucket
 	assert.Equal(t, TestBucket, readOnly.Info().Bucket.Name)
 	assert.Equal(t, storj.AESGCM, readOnly.Info().Bucket.PathCipher)
 
+	checksum := sha256.Sum256(content)
+	md5sum := md5.Sum(content)
+	assert.Equal(t, checksum[:], readOnly.Info().Checksum)
+	assert.Equal(t, hex.EncodeToString(md5sum[:]), readOnly.Info().ETag)
+
 	segments, more, err := readOnly.Segments(ctx, 0, 0)
 	if !assert.NoError(t, err) {
 		return
@@ -222,6 +332,10 @@ func assertStream(ctx context.Context, t *testing.T, db *DB, bucket storj.Bucket
 
 	assert.Equal(t, len(content), n)
 	assert.Equal(t, content, data)
+
+	// the checksum is verified when reaching the end of the stream
+	_, err = download.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
 }
 
 func assertInlineSegment(t *testing.T, segment storj.Segment, content []byte) {
@@ -673,3 +787,107 @@ func optionsRecursive(prefix, cursor string, direction storj.ListDirection, limi
 		Recursive: true,
 	}
 }
+
+func TestListObjectsDelimiter(t *testing.T) {
+	runTest(t, func(ctx context.Context, db *DB) {
+		// the paths are listed in lexical order regardless of the encryption
+		bucket, err := db.CreateBucket(ctx, TestBucket, &storj.Bucket{PathCipher: storj.AESGCM})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		filePaths := []string{
+			"a", "a-b", "a-b-c", "a/b", "ab", "logs/2018-11-01", "logs/2018-11-02", "logs/2018-12-01", "logs/readme",
+		}
+
+		for _, path := range filePaths {
+			upload(ctx, t, db, bucket, path, nil)
+		}
+
+		for i, tt := range []struct {
+			options  storj.ListOptions
+			more     bool
+			result   []string
+			prefixes []string
+		}{
+			{
+				options:  optionsDelimiter("", "", '/', storj.After, 0),
+				result:   []string{"a", "a-b", "a-b-c", "a/", "ab", "logs/"},
+				prefixes: []string{"a/", "logs/"},
+			}, {
+				options: optionsDelimiter("", "a-", '/', storj.After, 2),
+				more:    true,
+				result:  []string{"a-b", "a-b-c"},
+			}, {
+				options: storj.ListOptions{Recursive: true, Direction: storj.After, Cursor: "a.", Limit: 2},
+				more:    true,
+				result:  []string{"a/b", "ab"},
+			}, {
+				options:  optionsDelimiter("a", "", '-', storj.After, 0),
+				result:   []string{"", "-", "/b", "b"},
+				prefixes: []string{"-"},
+			}, {
+				options:  optionsDelimiter("a", "", '-', storj.Forward, 2),
+				more:     true,
+				result:   []string{"", "-"},
+				prefixes: []string{"-"},
+			}, {
+				options: optionsDelimiter("a", "-", '-', storj.After, 0),
+				result:  []string{"/b", "b"},
+			}, {
+				options:  optionsDelimiter("a", "b", '-', storj.Before, 0),
+				result:   []string{"", "-", "/b"},
+				prefixes: []string{"-"},
+			}, {
+				options: optionsDelimiter("a", "", '-', storj.Backward, 1),
+				more:    true,
+				result:  []string{"b"},
+			}, {
+				options:  optionsDelimiter("logs/2018-", "", '-', storj.After, 0),
+				result:   []string{"11-", "12-"},
+				prefixes: []string{"11-", "12-"},
+			}, {
+				options: optionsDelimiter("logs/2018-1", "", '/', storj.After, 0),
+				result:  []string{"1-01", "1-02", "2-01"},
+			}, {
+				options: storj.ListOptions{Prefix: "logs/2018-1", Delimiter: '-', Recursive: true, Direction: storj.After},
+				result:  []string{"1-01", "1-02", "2-01"},
+			},
+		} {
+			errTag := fmt.Sprintf("%d. %+v", i, tt)
+
+			list, err := db.ListObjects(ctx, bucket.Name, tt.options)
+
+			if assert.NoError(t, err, errTag) {
+				assert.Equal(t, tt.more, list.More, errTag)
+
+				var result, prefixes []string
+				for _, item := range list.Items {
+					result = append(result, item.Path)
+					if item.IsPrefix {
+						prefixes = append(prefixes, item.Path)
+					}
+				}
+				assert.Equal(t, tt.result, result, errTag)
+				assert.Equal(t, tt.prefixes, prefixes, errTag)
+			}
+		}
+
+		// the cached listings include the objects uploaded since
+		upload(ctx, t, db, bucket, "a-c", nil)
+		list, err := db.ListObjects(ctx, bucket.Name, optionsDelimiter("", "a-b-c", '/', storj.After, 1))
+		if assert.NoError(t, err) && assert.Len(t, list.Items, 1) {
+			assert.Equal(t, "a-c", list.Items[0].Path)
+		}
+	})
+}
+
+func optionsDelimiter(prefix, cursor string, delimiter rune, direction storj.ListDirection, limit int) storj.ListOptions {
+	return storj.ListOptions{
+		Prefix:    prefix,
+		Cursor:    cursor,
+		Delimiter: delimiter,
+		Direction: direction,
+		Limit:     limit,
+	}
+}
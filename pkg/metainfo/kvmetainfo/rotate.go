@@ -0,0 +1,275 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package kvmetainfo
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strconv"
+
+	"github.com/gogo/protobuf/proto"
+
+	"storj.io/storj/pkg/encryption"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/segments"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+// RotateBucketKey re-encrypts the paths and the content keys of all objects
+// in the bucket, and of the bucket itself, with the root key from newKeys.
+// The encrypted data is not touched, so nothing needs to be re-uploaded.
+//
+// Objects which are already encrypted with the new key are skipped, so an
+// interrupted rotation can be resumed by running it again.
+func (db *DB) RotateBucketKey(ctx context.Context, bucket string, newKeys *encryption.KeyStore) (rotated int, err error) {
+	defer mon.Task()(&ctx)(&err)
+	defer db.listings.invalidate(bucket)
+
+	oldRoot, err := db.keys.RootKey(bucket)
+	if err != nil {
+		return 0, err
+	}
+	newRoot, err := newKeys.RootKey(bucket)
+	if err != nil {
+		return 0, err
+	}
+
+	pathCipher, err := db.bucketPathCipher(ctx, bucket, oldRoot, newRoot)
+	if err != nil {
+		return 0, err
+	}
+
+	// collect the paths first, as the rotated objects may get new paths
+	// inside the listed prefix
+	var encryptedPaths []storj.Path
+	startAfter := ""
+	for {
+		items, more, err := db.pointers.List(ctx, committedPrefix+bucket, startAfter, "", true, 0, meta.None)
+		if err != nil {
+			return 0, err
+		}
+		for _, item := range items {
+			encryptedPaths = append(encryptedPaths, storj.JoinPaths(bucket, item.Path))
+		}
+		if !more || len(items) == 0 {
+			break
+		}
+		startAfter = items[len(items)-1].Path
+	}
+
+	for _, encryptedPath := range encryptedPaths {
+		fullpath, err := streams.DecryptAfterBucket(encryptedPath, pathCipher, oldRoot)
+		if err != nil {
+			// the path may be encrypted with the new key already
+			fullpath, err = streams.DecryptAfterBucket(encryptedPath, pathCipher, newRoot)
+			if err != nil {
+				return rotated, err
+			}
+		}
+
+		err = db.rotateObjectKey(ctx, fullpath, pathCipher, oldRoot, newRoot)
+		if err != nil {
+			return rotated, err
+		}
+		rotated++
+	}
+
+	// the bucket itself is stored as an object with an unencrypted path
+	return rotated, db.rotateObjectKey(ctx, bucket, pathCipher, oldRoot, newRoot)
+}
+
+// bucketPathCipher returns the path cipher of the bucket. The metadata of the
+// bucket is read with either key, as the bucket itself is rotated last.
+func (db *DB) bucketPathCipher(ctx context.Context, bucket string, oldRoot, newRoot *storj.Key) (storj.Cipher, error) {
+	pointer, _, _, err := db.pointers.Get(ctx, storj.JoinPaths("l", bucket))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			err = storj.ErrBucketNotFound.Wrap(err)
+		}
+		return storj.Unencrypted, err
+	}
+
+	item := segments.Meta{Data: pointer.GetMetadata()}
+	streamInfoData, err := streams.DecryptStreamInfo(ctx, item, bucket, oldRoot)
+	if err != nil {
+		streamInfoData, err = streams.DecryptStreamInfo(ctx, item, bucket, newRoot)
+		if err != nil {
+			return storj.Unencrypted, err
+		}
+	}
+
+	streamInfo := pb.StreamInfo{}
+	err = proto.Unmarshal(streamInfoData, &streamInfo)
+	if err != nil {
+		return storj.Unencrypted, err
+	}
+	serMeta := pb.SerializableMeta{}
+	err = proto.Unmarshal(streamInfo.Metadata, &serMeta)
+	if err != nil {
+		return storj.Unencrypted, err
+	}
+
+	pathEncType := serMeta.UserDefined["path-enc-type"]
+	if pathEncType == "" {
+		// backward compatibility for old buckets
+		return storj.AESGCM, nil
+	}
+	pet, err := strconv.Atoi(pathEncType)
+	if err != nil {
+		return storj.Unencrypted, err
+	}
+	return storj.Cipher(pet), nil
+}
+
+// rotateObjectKey moves the segments of the object at fullpath to the paths
+// encrypted with newRoot and re-encrypts their content keys with newRoot
+func (db *DB) rotateObjectKey(ctx context.Context, fullpath storj.Path, pathCipher storj.Cipher, oldRoot, newRoot *storj.Key) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	oldPath, err := streams.EncryptAfterBucket(fullpath, pathCipher, oldRoot)
+	if err != nil {
+		return err
+	}
+	newPath, err := streams.EncryptAfterBucket(fullpath, pathCipher, newRoot)
+	if err != nil {
+		return err
+	}
+
+	oldKey, err := encryption.DeriveContentKey(fullpath, oldRoot)
+	if err != nil {
+		return err
+	}
+	newKey, err := encryption.DeriveContentKey(fullpath, newRoot)
+	if err != nil {
+		return err
+	}
+
+	lastSegmentOld := storj.JoinPaths("l", oldPath)
+	lastSegmentNew := storj.JoinPaths("l", newPath)
+	lastPointer, lastMoved, err := db.getEitherPointer(ctx, lastSegmentOld, lastSegmentNew)
+	if err != nil {
+		return err
+	}
+
+	streamMeta := pb.StreamMeta{}
+	err = proto.Unmarshal(lastPointer.GetMetadata(), &streamMeta)
+	if err != nil {
+		return err
+	}
+	cipher := storj.Cipher(streamMeta.EncryptionType)
+
+	if streamMeta.LastSegmentMeta != nil {
+		err = rewrapSegmentKey(streamMeta.LastSegmentMeta, cipher, oldKey, newKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the stream info is encrypted with the content key of the last segment,
+	// which was just re-encrypted with the new key
+	streamMetaData, err := proto.Marshal(&streamMeta)
+	if err != nil {
+		return err
+	}
+	streamInfoData, err := streams.DecryptStreamInfo(ctx, segments.Meta{Data: streamMetaData}, fullpath, newRoot)
+	if err != nil {
+		return err
+	}
+	streamInfo := pb.StreamInfo{}
+	err = proto.Unmarshal(streamInfoData, &streamInfo)
+	if err != nil {
+		return err
+	}
+
+	for i := int64(0); i < streamInfo.NumberOfSegments-1; i++ {
+		segmentOld := storj.JoinPaths(fmt.Sprintf("s%d", i), oldPath)
+		segmentNew := storj.JoinPaths(fmt.Sprintf("s%d", i), newPath)
+
+		pointer, moved, err := db.getEitherPointer(ctx, segmentOld, segmentNew)
+		if err != nil {
+			return err
+		}
+
+		if cipher != storj.Unencrypted {
+			segmentMeta := pb.SegmentMeta{}
+			err = proto.Unmarshal(pointer.GetMetadata(), &segmentMeta)
+			if err != nil {
+				return err
+			}
+			err = rewrapSegmentKey(&segmentMeta, cipher, oldKey, newKey)
+			if err != nil {
+				return err
+			}
+			pointer.Metadata, err = proto.Marshal(&segmentMeta)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = db.movePointer(ctx, segmentOld, segmentNew, pointer, moved)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the last segment goes last, so the object stays listed until the
+	// rotation of all its segments is complete
+	lastPointer.Metadata = streamMetaData
+	return db.movePointer(ctx, lastSegmentOld, lastSegmentNew, lastPointer, lastMoved)
+}
+
+// getEitherPointer returns the pointer at oldPath, or at newPath if the
+// pointer was moved already
+func (db *DB) getEitherPointer(ctx context.Context, oldPath, newPath storj.Path) (pointer *pb.Pointer, moved bool, err error) {
+	pointer, _, _, err = db.pointers.Get(ctx, oldPath)
+	if err == nil || oldPath == newPath || !storage.ErrKeyNotFound.Has(err) {
+		return pointer, oldPath == newPath, err
+	}
+	pointer, _, _, err = db.pointers.Get(ctx, newPath)
+	return pointer, true, err
+}
+
+// movePointer stores the pointer at newPath and removes it from oldPath,
+// unless it was moved already
+func (db *DB) movePointer(ctx context.Context, oldPath, newPath storj.Path, pointer *pb.Pointer, moved bool) error {
+	err := db.pointers.Put(ctx, newPath, pointer)
+	if err != nil || moved {
+		return err
+	}
+	return db.pointers.Delete(ctx, oldPath)
+}
+
+// rewrapSegmentKey re-encrypts the content key of the segment with newKey.
+// Content keys already encrypted with newKey are left as they are.
+func rewrapSegmentKey(segmentMeta *pb.SegmentMeta, cipher storj.Cipher, oldKey, newKey *storj.Key) error {
+	var keyNonce storj.Nonce
+	copy(keyNonce[:], segmentMeta.KeyNonce)
+
+	contentKey, err := encryption.DecryptKey(segmentMeta.EncryptedKey, cipher, oldKey, &keyNonce)
+	if err != nil {
+		if _, newErr := encryption.DecryptKey(segmentMeta.EncryptedKey, cipher, newKey, &keyNonce); newErr == nil {
+			return nil
+		}
+		return err
+	}
+
+	var newNonce storj.Nonce
+	_, err = rand.Read(newNonce[:])
+	if err != nil {
+		return err
+	}
+
+	encryptedKey, err := encryption.EncryptKey(contentKey, cipher, newKey, &newNonce)
+	if err != nil {
+		return err
+	}
+
+	segmentMeta.EncryptedKey = encryptedKey
+	segmentMeta.KeyNonce = newNonce[:]
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package kvmetainfo
+
+import (
+	"context"
+	"crypto/rand"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/pkg/encryption"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/stream"
+)
+
+func TestRotateBucketKey(t *testing.T) {
+	runTest(t, func(ctx context.Context, db *DB) {
+		bucket, err := db.CreateBucket(ctx, TestBucket, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		objects := map[storj.Path][]byte{
+			"a":     make([]byte, 5*memory.KB.Int()),
+			"a/b/c": make([]byte, 1*memory.KB.Int()),
+			"a/d":   make([]byte, 10),
+		}
+		for path, data := range objects {
+			_, err = rand.Read(data)
+			if !assert.NoError(t, err) {
+				return
+			}
+			upload(ctx, t, db, bucket, path, data)
+		}
+
+		newKeys := encryption.NewKeyStore(encryption.DeriveRootKey([]byte("new passphrase"), []byte("salt")), false)
+
+		rotated, err := db.RotateBucketKey(ctx, TestBucket, newKeys)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, len(objects), rotated)
+
+		// rotating again is a no-op
+		rotated, err = db.RotateBucketKey(ctx, TestBucket, newKeys)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, len(objects), rotated)
+
+		newDB, err := withKeys(db, newKeys)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		list, err := newDB.ListObjects(ctx, TestBucket, storj.ListOptions{Direction: storj.After, Recursive: true})
+		if assert.NoError(t, err) {
+			assert.Equal(t, len(objects), len(list.Items))
+		}
+
+		for path, data := range objects {
+			assert.Equal(t, data, download(ctx, t, newDB, path), path)
+		}
+	})
+}
+
+// withKeys returns a metainfo database on top of the same stores as db, but
+// with different encryption keys
+func withKeys(db *DB, keys *encryption.KeyStore) (*DB, error) {
+	streams, err := streams.NewStreamStore(db.segments, int64(64*memory.MB), keys, int(1*memory.KB), storj.AESGCM, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return New(buckets.NewStore(streams), streams, db.segments, db.pointers, keys), nil
+}
+
+func download(ctx context.Context, t *testing.T, db *DB, path storj.Path) []byte {
+	readOnly, err := db.GetObjectStream(ctx, TestBucket, path)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	download := stream.NewDownload(ctx, readOnly, db.streams)
+	defer func() { assert.NoError(t, download.Close()) }()
+
+	data, err := ioutil.ReadAll(download)
+	assert.NoError(t, err)
+	return data
+}
@@ -5,7 +5,9 @@ package miniogw
 
 import (
 	"context"
+	"encoding/hex"
 	"os"
+	"strings"
 
 	"github.com/minio/cli"
 	minio "github.com/minio/minio/cmd"
@@ -13,6 +15,7 @@ import (
 	"go.uber.org/zap"
 
 	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/encryption"
 	"storj.io/storj/pkg/metainfo/kvmetainfo"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pointerdb/pdbclient"
@@ -38,10 +41,13 @@ type RSConfig struct {
 // EncryptionConfig is a configuration struct that keeps details about
 // encrypting segments
 type EncryptionConfig struct {
-	Key       string `help:"root key for encrypting the data"`
-	BlockSize int    `help:"size (in bytes) of encrypted blocks" default:"1024"`
-	DataType  int    `help:"Type of encryption to use for content and metadata (1=AES-GCM, 2=SecretBox)" default:"1"`
-	PathType  int    `help:"Type of encryption to use for paths (0=Unencrypted, 1=AES-GCM, 2=SecretBox)" default:"1"`
+	Key              string `help:"root key or passphrase for encrypting the data"`
+	Salt             string `help:"hex encoded salt for deriving the root key from the passphrase (the key is used as is if empty)"`
+	BucketKeys       string `help:"comma separated list of bucket=passphrase pairs for buckets with their own root key"`
+	DeriveBucketKeys bool   `help:"derive a separate root key for every bucket from the root key" default:"false"`
+	BlockSize        int    `help:"size (in bytes) of encrypted blocks" default:"1024"`
+	DataType         int    `help:"Type of encryption to use for content and metadata (1=AES-GCM, 2=SecretBox, 3=XChaCha20-Poly1305)" default:"1"`
+	PathType         int    `help:"Type of encryption to use for paths (0=Unencrypted, 1=AES-GCM, 2=SecretBox, 3=XChaCha20-Poly1305)" default:"1"`
 }
 
 // MinioConfig is a configuration struct that keeps details about starting
@@ -62,6 +68,9 @@ type ClientConfig struct {
 	APIKey        string `help:"API Key (TODO: this needs to change to macaroons somehow)"`
 	MaxInlineSize int    `help:"max inline segment size in bytes" default:"4096"`
 	SegmentSize   int64  `help:"the size of a segment in bytes" default:"64000000"`
+
+	UploadConcurrency int `help:"number of segments of a stream uploaded in parallel" default:"2"`
+	DownloadPrefetch  int `help:"number of segments of a stream downloaded ahead of the one being read" default:"1"`
 }
 
 // Config is a general miniogw configuration struct. This should be everything
@@ -153,17 +162,65 @@ func (c Config) GetMetainfo(ctx context.Context, identity *provider.FullIdentity
 		return nil, nil, err
 	}
 
-	key := new(storj.Key)
-	copy(key[:], c.Enc.Key)
+	keys, err := c.Enc.KeyStore()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	streams, err := streams.NewStreamStore(segments, c.Client.SegmentSize, key, c.Enc.BlockSize, storj.Cipher(c.Enc.DataType))
+	streams, err := streams.NewStreamStore(segments, c.Client.SegmentSize, keys, c.Enc.BlockSize, storj.Cipher(c.Enc.DataType), c.Client.UploadConcurrency, c.Client.DownloadPrefetch)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	buckets := buckets.NewStore(streams)
 
-	return kvmetainfo.New(buckets, streams, segments, pdb, key), streams, nil
+	// the objects are created with the configured schemes unless their buckets
+	// have their own
+	db = kvmetainfo.New(buckets, streams, segments, pdb, keys).WithDefaults(c.GetRedundancyScheme(), c.GetEncryptionScheme())
+	return db, streams, nil
+}
+
+// KeyStore returns the root keys for encrypting the data
+func (c EncryptionConfig) KeyStore() (*encryption.KeyStore, error) {
+	root, err := c.deriveKey(c.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := encryption.NewKeyStore(root, c.DeriveBucketKeys)
+
+	if c.BucketKeys != "" {
+		for _, pair := range strings.Split(c.BucketKeys, ",") {
+			parts := strings.SplitN(pair, "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				return nil, Error.New("invalid bucket key %q, expected bucket=passphrase", pair)
+			}
+			key, err := c.deriveKey(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			keys.SetBucketKey(parts[0], key)
+		}
+	}
+
+	return keys, nil
+}
+
+// deriveKey derives a key from the passphrase with the configured salt. For
+// compatibility the passphrase is copied into the key if there is no salt.
+func (c EncryptionConfig) deriveKey(passphrase string) (*storj.Key, error) {
+	if c.Salt == "" {
+		key := new(storj.Key)
+		copy(key[:], passphrase)
+		return key, nil
+	}
+
+	salt, err := hex.DecodeString(c.Salt)
+	if err != nil {
+		return nil, Error.New("invalid salt: %v", err)
+	}
+
+	return encryption.DeriveRootKey([]byte(passphrase), salt), nil
 }
 
 // GetRedundancyScheme returns the configured redundancy scheme for new uploads
@@ -174,6 +231,7 @@ func (c Config) GetRedundancyScheme() storj.RedundancyScheme {
 		RepairShares:   int16(c.RS.RepairThreshold),
 		OptimalShares:  int16(c.RS.SuccessThreshold),
 		TotalShares:    int16(c.RS.MaxThreshold),
+		ShareSize:      int32(c.RS.ErasureShareSize),
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 	"encoding/hex"
 	"io"
 	"strings"
+	"unicode/utf8"
 
 	minio "github.com/minio/minio/cmd"
 	"github.com/minio/minio/pkg/auth"
@@ -153,7 +154,7 @@ func (layer *gatewayLayer) GetObjectInfo(ctx context.Context, bucket, object str
 		Bucket:      bucket,
 		ModTime:     obj.Modified,
 		Size:        obj.Size,
-		ETag:        hex.EncodeToString(obj.Checksum),
+		ETag:        etag(obj),
 		ContentType: obj.ContentType,
 		UserDefined: obj.Metadata,
 	}, err
@@ -187,132 +188,115 @@ func (layer *gatewayLayer) ListBuckets(ctx context.Context) (bucketItems []minio
 func (layer *gatewayLayer) ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (result minio.ListObjectsInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	if delimiter != "" && delimiter != "/" {
-		return minio.ListObjectsInfo{}, minio.UnsupportedDelimiter{Delimiter: delimiter}
-	}
-
-	startAfter := marker
-	recursive := delimiter == ""
-
-	var objects []minio.ObjectInfo
-	var prefixes []string
-
-	list, err := layer.gateway.metainfo.ListObjects(ctx, bucket, storj.ListOptions{
-		Direction: storj.After,
-		Cursor:    startAfter,
-		Prefix:    prefix,
-		Recursive: recursive,
-		Limit:     maxKeys,
-	})
+	objects, prefixes, more, next, err := layer.listObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
 	if err != nil {
-		return result, convertError(err, bucket, "")
-	}
-
-	if len(list.Items) > 0 {
-		for _, item := range list.Items {
-			path := item.Path
-			if recursive && prefix != "" {
-				path = storj.JoinPaths(strings.TrimSuffix(prefix, "/"), path)
-			}
-			if item.IsPrefix {
-				prefixes = append(prefixes, path)
-				continue
-			}
-			objects = append(objects, minio.ObjectInfo{
-				Bucket:      bucket,
-				IsDir:       false,
-				Name:        path,
-				ModTime:     item.Modified,
-				Size:        item.Size,
-				ETag:        hex.EncodeToString(item.Checksum),
-				ContentType: item.ContentType,
-				UserDefined: item.Metadata,
-			})
-		}
-		startAfter = list.Items[len(list.Items)-1].Path
+		return minio.ListObjectsInfo{}, err
 	}
 
 	result = minio.ListObjectsInfo{
-		IsTruncated: list.More,
+		IsTruncated: more,
 		Objects:     objects,
 		Prefixes:    prefixes,
 	}
-	if list.More {
-		result.NextMarker = startAfter
+	if more {
+		result.NextMarker = next
 	}
 
-	return result, err
+	return result, nil
 }
 
-// ListObjectsV2 - Not implemented stub
+// ListObjectsV2 lists the objects like ListObjects, where the continuation
+// token is the last key or common prefix of the previous page
 func (layer *gatewayLayer) ListObjectsV2(ctx context.Context, bucket, prefix, continuationToken, delimiter string, maxKeys int, fetchOwner bool, startAfter string) (result minio.ListObjectsV2Info, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	if delimiter != "" && delimiter != "/" {
-		return minio.ListObjectsV2Info{ContinuationToken: continuationToken}, minio.UnsupportedDelimiter{Delimiter: delimiter}
+	marker := continuationToken
+	if marker == "" {
+		marker = startAfter
 	}
 
-	recursive := delimiter == ""
-	var nextContinuationToken string
+	objects, prefixes, more, next, err := layer.listObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+	if err != nil {
+		return minio.ListObjectsV2Info{ContinuationToken: continuationToken}, err
+	}
 
-	var startAfterPath storj.Path
-	if continuationToken != "" {
-		startAfterPath = continuationToken
+	result = minio.ListObjectsV2Info{
+		IsTruncated:       more,
+		ContinuationToken: continuationToken,
+		Objects:           objects,
+		Prefixes:          prefixes,
 	}
-	if startAfterPath == "" && startAfter != "" {
-		startAfterPath = startAfter
+	if more {
+		result.NextContinuationToken = next
 	}
 
-	var objects []minio.ObjectInfo
-	var prefixes []string
+	return result, nil
+}
 
-	list, err := layer.gateway.metainfo.ListObjects(ctx, bucket, storj.ListOptions{
-		Direction: storj.After,
-		Cursor:    startAfterPath,
+// listObjects lists the keys of the bucket starting with prefix in lexical
+// order after marker. The keys are collapsed into common prefixes up to the
+// first delimiter after prefix, unless the delimiter is empty. next is the
+// last key or common prefix listed.
+func (layer *gatewayLayer) listObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (objects []minio.ObjectInfo, prefixes []string, more bool, next string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	options := storj.ListOptions{
+		Direction: storj.Forward,
 		Prefix:    prefix,
-		Recursive: recursive,
 		Limit:     maxKeys,
-	})
-	if err != nil {
-		return minio.ListObjectsV2Info{ContinuationToken: continuationToken}, convertError(err, bucket, "")
 	}
 
-	if len(list.Items) > 0 {
-		for _, item := range list.Items {
-			path := item.Path
-			if recursive && prefix != "" {
-				path = storj.JoinPaths(strings.TrimSuffix(prefix, "/"), path)
-			}
-			if item.IsPrefix {
-				prefixes = append(prefixes, path)
-				continue
-			}
-			objects = append(objects, minio.ObjectInfo{
-				Bucket:      bucket,
-				IsDir:       false,
-				Name:        path,
-				ModTime:     item.Modified,
-				Size:        item.Size,
-				ETag:        hex.EncodeToString(item.Checksum),
-				ContentType: item.ContentType,
-				UserDefined: item.Metadata,
-			})
+	switch utf8.RuneCountInString(delimiter) {
+	case 0:
+		// an empty delimiter selects the plain string prefix, which is
+		// listed recursively without collapsing any key
+		options.Delimiter = '/'
+		options.Recursive = true
+	case 1:
+		options.Delimiter, _ = utf8.DecodeRuneInString(delimiter)
+	default:
+		return nil, nil, false, "", minio.UnsupportedDelimiter{Delimiter: delimiter}
+	}
+
+	// the marker is a full key, while the cursor is relative to the prefix.
+	// The listing starts from the least key after the marker, which may be
+	// the prefix itself.
+	if marker >= prefix && marker != "" {
+		if !strings.HasPrefix(marker, prefix) {
+			// all the keys with the prefix are before the marker
+			return nil, nil, false, "", nil
 		}
+		options.Cursor = marker[len(prefix):] + "\x00"
+	}
 
-		nextContinuationToken = list.Items[len(list.Items)-1].Path + "\x00"
+	list, err := layer.gateway.metainfo.ListObjects(ctx, bucket, options)
+	if err != nil {
+		return nil, nil, false, "", convertError(err, bucket, "")
 	}
 
-	result = minio.ListObjectsV2Info{
-		IsTruncated:       list.More,
-		ContinuationToken: continuationToken,
-		Objects:           objects,
-		Prefixes:          prefixes,
+	for _, item := range list.Items {
+		key := prefix + item.Path
+		if item.IsPrefix {
+			prefixes = append(prefixes, key)
+			continue
+		}
+		objects = append(objects, minio.ObjectInfo{
+			Bucket:      bucket,
+			IsDir:       false,
+			Name:        key,
+			ModTime:     item.Modified,
+			Size:        item.Size,
+			ETag:        etag(item),
+			ContentType: item.ContentType,
+			UserDefined: item.Metadata,
+		})
 	}
-	if list.More {
-		result.NextContinuationToken = nextContinuationToken
+
+	if len(list.Items) > 0 {
+		next = prefix + list.Items[len(list.Items)-1].Path
 	}
 
-	return result, err
+	return objects, prefixes, list.More, next, nil
 }
 
 func (layer *gatewayLayer) MakeBucketWithLocation(ctx context.Context, bucket string, location string) (err error) {
@@ -333,7 +317,11 @@ func (layer *gatewayLayer) MakeBucketWithLocation(ctx context.Context, bucket st
 		return convertError(err, bucket, "")
 	}
 
-	_, err = layer.gateway.metainfo.CreateBucket(ctx, bucket, &storj.Bucket{PathCipher: layer.gateway.pathCipher})
+	_, err = layer.gateway.metainfo.CreateBucket(ctx, bucket, &storj.Bucket{
+		PathCipher:       layer.gateway.pathCipher,
+		RedundancyScheme: layer.gateway.redundancy,
+		EncryptionScheme: layer.gateway.encryption,
+	})
 
 	return err
 }
@@ -386,7 +374,7 @@ func (layer *gatewayLayer) putObject(ctx context.Context, bucket, object string,
 		Bucket:      bucket,
 		ModTime:     info.Modified,
 		Size:        info.Size,
-		ETag:        hex.EncodeToString(info.Checksum),
+		ETag:        etag(info),
 		ContentType: info.ContentType,
 		UserDefined: info.Metadata,
 	}, nil
@@ -402,9 +390,22 @@ func upload(ctx context.Context, streams streams.Store, mutableObject storj.Muta
 
 	_, err = io.Copy(upload, reader)
 
+	if etagger, ok := reader.(interface{ ETag() string }); ok && err == nil {
+		upload.SetETag(etagger.ETag())
+	}
+
 	return utils.CombineErrors(err, upload.Close())
 }
 
+// etag returns the S3 compatible ETag of the object. Objects uploaded before
+// ETags were stored fall back to the hex encoded checksum.
+func etag(object storj.Object) string {
+	if object.ETag != "" {
+		return object.ETag
+	}
+	return hex.EncodeToString(object.Checksum)
+}
+
 func (layer *gatewayLayer) PutObject(ctx context.Context, bucket, object string, data *hash.Reader, metadata map[string]string) (objInfo minio.ObjectInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -412,10 +413,8 @@ func (layer *gatewayLayer) PutObject(ctx context.Context, bucket, object string,
 	delete(metadata, "content-type")
 
 	createInfo := storj.CreateObject{
-		ContentType:      contentType,
-		Metadata:         metadata,
-		RedundancyScheme: layer.gateway.redundancy,
-		EncryptionScheme: layer.gateway.encryption,
+		ContentType: contentType,
+		Metadata:    metadata,
 	}
 
 	return layer.putObject(ctx, bucket, object, data, &createInfo)
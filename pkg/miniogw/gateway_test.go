@@ -6,10 +6,11 @@ package miniogw
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"flag"
 	"fmt"
-	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"storj.io/storj/internal/testcontext"
 	"storj.io/storj/internal/testplanet"
 	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/encryption"
 	"storj.io/storj/pkg/metainfo/kvmetainfo"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storage/buckets"
@@ -198,7 +200,7 @@ func TestPutObject(t *testing.T) {
 			assert.False(t, info.IsDir)
 			assert.True(t, time.Since(info.ModTime) < 1*time.Second)
 			assert.Equal(t, data.Size(), info.Size)
-			// assert.Equal(t, data.SHA256HexString(), info.ETag) TODO: when we start calculating checksums
+			assert.Equal(t, data.MD5HexString(), info.ETag)
 			assert.Equal(t, serMetaInfo.ContentType, info.ContentType)
 			assert.Equal(t, serMetaInfo.UserDefined, info.UserDefined)
 		}
@@ -211,13 +213,60 @@ func TestPutObject(t *testing.T) {
 			assert.False(t, obj.IsPrefix)
 			assert.Equal(t, info.ModTime, obj.Modified)
 			assert.Equal(t, info.Size, obj.Size)
-			assert.Equal(t, info.ETag, hex.EncodeToString(obj.Checksum))
+			assert.Equal(t, info.ETag, obj.ETag)
+			assert.Equal(t, data.SHA256(), obj.Checksum)
 			assert.Equal(t, info.ContentType, obj.ContentType)
 			assert.Equal(t, info.UserDefined, obj.Metadata)
 		}
 	})
 }
 
+func TestMultipartUploadETag(t *testing.T) {
+	runTest(t, func(ctx context.Context, layer minio.ObjectLayer, metainfo storj.Metainfo, streams streams.Store) {
+		_, err := metainfo.CreateBucket(ctx, TestBucket, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		uploadID, err := layer.NewMultipartUpload(ctx, TestBucket, TestFile, map[string]string{})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		expected := md5.New()
+		for i, part := range []string{"first part", "second part"} {
+			data, err := hash.NewReader(bytes.NewReader([]byte(part)), int64(len(part)), "", "")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			info, err := layer.PutObjectPart(ctx, TestBucket, TestFile, uploadID, i+1, data)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			partMD5 := md5.Sum([]byte(part))
+			assert.Equal(t, hex.EncodeToString(partMD5[:]), info.ETag)
+			_, _ = expected.Write(partMD5[:])
+		}
+
+		info, err := layer.CompleteMultipartUpload(ctx, TestBucket, TestFile, uploadID, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		etag := hex.EncodeToString(expected.Sum(nil)) + "-2"
+		assert.Equal(t, etag, info.ETag)
+
+		obj, err := metainfo.GetObject(ctx, TestBucket, TestFile)
+		if assert.NoError(t, err) {
+			assert.Equal(t, etag, obj.ETag)
+			checksum := sha256.Sum256([]byte("first partsecond part"))
+			assert.Equal(t, checksum[:], obj.Checksum)
+		}
+	})
+}
+
 func TestGetObjectInfo(t *testing.T) {
 	runTest(t, func(ctx context.Context, layer minio.ObjectLayer, metainfo storj.Metainfo, streams streams.Store) {
 		// Check the error when getting an object from a bucket with empty name
@@ -256,7 +305,7 @@ func TestGetObjectInfo(t *testing.T) {
 			assert.False(t, info.IsDir)
 			assert.Equal(t, obj.Modified, info.ModTime)
 			assert.Equal(t, obj.Size, info.Size)
-			assert.Equal(t, hex.EncodeToString(obj.Checksum), info.ETag)
+			assert.Equal(t, obj.ETag, info.ETag)
 			assert.Equal(t, createInfo.ContentType, info.ContentType)
 			assert.Equal(t, createInfo.Metadata, info.UserDefined)
 		}
@@ -376,7 +425,7 @@ func TestCopyObject(t *testing.T) {
 			assert.False(t, info.IsDir)
 			assert.True(t, info.ModTime.Sub(obj.Modified) < 1*time.Second)
 			assert.Equal(t, obj.Size, info.Size)
-			assert.Equal(t, hex.EncodeToString(obj.Checksum), info.ETag)
+			assert.Equal(t, obj.ETag, info.ETag)
 			assert.Equal(t, createInfo.ContentType, info.ContentType)
 			assert.Equal(t, createInfo.Metadata, info.UserDefined)
 		}
@@ -389,7 +438,7 @@ func TestCopyObject(t *testing.T) {
 			assert.False(t, obj.IsPrefix)
 			assert.Equal(t, info.ModTime, obj.Modified)
 			assert.Equal(t, info.Size, obj.Size)
-			assert.Equal(t, info.ETag, hex.EncodeToString(obj.Checksum))
+			assert.Equal(t, info.ETag, obj.ETag)
 			assert.Equal(t, info.ContentType, obj.ContentType)
 			assert.Equal(t, info.UserDefined, obj.Metadata)
 		}
@@ -455,8 +504,8 @@ func TestListObjectsV2(t *testing.T) {
 func testListObjects(t *testing.T, listObjects func(context.Context, minio.ObjectLayer, string, string, string, string, int) ([]string, []minio.ObjectInfo, bool, error)) {
 	runTest(t, func(ctx context.Context, layer minio.ObjectLayer, metainfo storj.Metainfo, streams streams.Store) {
 		// Check the error when listing objects with unsupported delimiter
-		_, err := layer.ListObjects(ctx, TestBucket, "", "", "#", 0)
-		assert.Equal(t, minio.UnsupportedDelimiter{Delimiter: "#"}, err)
+		_, err := layer.ListObjects(ctx, TestBucket, "", "", "##", 0)
+		assert.Equal(t, minio.UnsupportedDelimiter{Delimiter: "##"}, err)
 
 		// Check the error when listing objects in a bucket with empty name
 		_, err = layer.ListObjects(ctx, "", "", "", "/", 0)
@@ -580,16 +629,17 @@ func testListObjects(t *testing.T, listObjects func(context.Context, minio.Objec
 			}, {
 				prefix:    "a",
 				delimiter: "/",
-				objects:   []string{"xa", "xaa", "xb", "xbb", "xc"},
+				prefixes:  []string{"a/"},
+				objects:   []string{"a", "aa"},
 			}, {
 				prefix:    "a/",
 				delimiter: "/",
-				objects:   []string{"xa", "xaa", "xb", "xbb", "xc"},
+				objects:   []string{"a/xa", "a/xaa", "a/xb", "a/xbb", "a/xc"},
 			}, {
 				prefix:    "a/",
-				marker:    "xb",
+				marker:    "a/xb",
 				delimiter: "/",
-				objects:   []string{"xbb", "xc"},
+				objects:   []string{"a/xbb", "a/xc"},
 			}, {
 				marker:  "a/xbb",
 				maxKeys: 5,
@@ -597,11 +647,11 @@ func testListObjects(t *testing.T, listObjects func(context.Context, minio.Objec
 				objects: []string{"a/xc", "aa", "b", "b/ya", "b/yaa"},
 			}, {
 				prefix:    "a/",
-				marker:    "xaa",
+				marker:    "a/xaa",
 				delimiter: "/",
 				maxKeys:   2,
 				more:      true,
-				objects:   []string{"xb", "xbb"},
+				objects:   []string{"a/xb", "a/xbb"},
 			},
 		} {
 			errTag := fmt.Sprintf("%d. %+v", i, tt)
@@ -613,18 +663,14 @@ func testListObjects(t *testing.T, listObjects func(context.Context, minio.Objec
 				assert.Equal(t, tt.prefixes, prefixes, errTag)
 				assert.Equal(t, len(tt.objects), len(objects), errTag)
 				for i, objectInfo := range objects {
-					path := objectInfo.Name
-					if tt.prefix != "" {
-						path = storj.JoinPaths(strings.TrimSuffix(tt.prefix, "/"), path)
-					}
-					obj := files[path]
+					obj := files[objectInfo.Name]
 
 					assert.Equal(t, tt.objects[i], objectInfo.Name, errTag)
 					assert.Equal(t, TestBucket, objectInfo.Bucket, errTag)
 					assert.False(t, objectInfo.IsDir, errTag)
 					assert.Equal(t, obj.Modified, objectInfo.ModTime, errTag)
 					assert.Equal(t, obj.Size, objectInfo.Size, errTag)
-					assert.Equal(t, hex.EncodeToString(obj.Checksum), objectInfo.ETag, errTag)
+					assert.Equal(t, obj.ETag, objectInfo.ETag, errTag)
 					assert.Equal(t, obj.ContentType, objectInfo.ContentType, errTag)
 					assert.Equal(t, obj.Metadata, objectInfo.UserDefined, errTag)
 				}
@@ -686,15 +732,16 @@ func initEnv(planet *testplanet.Planet) (minio.ObjectLayer, storj.Metainfo, stre
 
 	key := new(storj.Key)
 	copy(key[:], TestEncKey)
+	keys := encryption.NewKeyStore(key, false)
 
-	streams, err := streams.NewStreamStore(segments, int64(64*memory.MB), key, int(1*memory.KB), storj.AESGCM)
+	streams, err := streams.NewStreamStore(segments, int64(64*memory.MB), keys, int(1*memory.KB), storj.AESGCM, 0, 0)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
 	buckets := buckets.NewStore(streams)
 
-	metainfo := kvmetainfo.New(buckets, streams, segments, pdb, key)
+	metainfo := kvmetainfo.New(buckets, streams, segments, pdb, keys)
 
 	gateway := NewStorjGateway(
 		metainfo,
@@ -0,0 +1,57 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+
+	minio "github.com/minio/minio/cmd"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// LifecycleDB stores the lifecycle rules of buckets
+type LifecycleDB interface {
+	SetBucketLifecycle(ctx context.Context, bucket string, rules []storj.LifecycleRule) (storj.Bucket, error)
+}
+
+// BucketLifecycle is implemented by the gateway layers supporting the
+// expiration rules of buckets, which apply to the objects uploaded afterwards.
+//
+// The minio version of the gateway doesn't route the S3 bucket lifecycle API
+// to the gateway layer yet, the rules are set with `uplink lifecycle` until
+// it does.
+type BucketLifecycle interface {
+	GetBucketLifecycle(ctx context.Context, bucket string) ([]storj.LifecycleRule, error)
+	SetBucketLifecycle(ctx context.Context, bucket string, rules []storj.LifecycleRule) error
+	DeleteBucketLifecycle(ctx context.Context, bucket string) error
+}
+
+func (layer *gatewayLayer) GetBucketLifecycle(ctx context.Context, bucket string) (rules []storj.LifecycleRule, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	bucketInfo, err := layer.gateway.metainfo.GetBucket(ctx, bucket)
+	if err != nil {
+		return nil, convertError(err, bucket, "")
+	}
+
+	return bucketInfo.Lifecycle, nil
+}
+
+func (layer *gatewayLayer) SetBucketLifecycle(ctx context.Context, bucket string, rules []storj.LifecycleRule) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	db, ok := layer.gateway.metainfo.(LifecycleDB)
+	if !ok {
+		return minio.NotImplemented{}
+	}
+
+	_, err = db.SetBucketLifecycle(ctx, bucket, rules)
+	return convertError(err, bucket, "")
+}
+
+func (layer *gatewayLayer) DeleteBucketLifecycle(ctx context.Context, bucket string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return layer.SetBucketLifecycle(ctx, bucket, nil)
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"testing"
+
+	minio "github.com/minio/minio/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestBucketLifecycle(t *testing.T) {
+	runTest(t, func(ctx context.Context, layer minio.ObjectLayer, metainfo storj.Metainfo, streams streams.Store) {
+		// the logging wrapper passes on the lifecycle requests
+		var lifecycle BucketLifecycle = &layerLogging{layer: layer, logger: zap.NewNop()}
+
+		_, err := metainfo.CreateBucket(ctx, TestBucket, nil)
+		require.NoError(t, err)
+
+		err = lifecycle.SetBucketLifecycle(ctx, DestBucket, []storj.LifecycleRule{{Prefix: "logs/", Days: 7}})
+		assert.Equal(t, minio.BucketNotFound{Bucket: DestBucket}, err)
+
+		rules, err := lifecycle.GetBucketLifecycle(ctx, TestBucket)
+		if assert.NoError(t, err) {
+			assert.Empty(t, rules)
+		}
+
+		expected := []storj.LifecycleRule{
+			{ID: "logs", Prefix: "logs/", Days: 7},
+			{ID: "tmp", Prefix: "tmp/", Days: 1},
+		}
+		require.NoError(t, lifecycle.SetBucketLifecycle(ctx, TestBucket, expected))
+
+		rules, err = lifecycle.GetBucketLifecycle(ctx, TestBucket)
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, rules)
+		}
+
+		require.NoError(t, lifecycle.DeleteBucketLifecycle(ctx, TestBucket))
+
+		bucket, err := metainfo.GetBucket(ctx, TestBucket)
+		if assert.NoError(t, err) {
+			assert.Empty(t, bucket.Lifecycle)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	minio "github.com/minio/minio/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+)
+
+// s3Listing is the result of listing keys as specified by the S3 API
+type s3Listing struct {
+	Prefixes  []string
+	Objects   []string
+	Truncated bool
+	Next      string
+}
+
+// listS3 lists the sorted keys as specified by the S3 API: the keys with
+// prefix are listed after marker, and the keys containing delimiter after
+// prefix are rolled up into a common prefix counting as a single key.
+func listS3(keys []string, prefix, marker, delimiter string, maxKeys int) (listing s3Listing) {
+	count := 0
+	for _, key := range keys {
+		if key <= marker || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		entry, isPrefix := key, false
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				entry, isPrefix = key[:len(prefix)+i+len(delimiter)], true
+			}
+		}
+		if entry <= marker || (isPrefix && entry == listing.Next) {
+			continue
+		}
+
+		if count == maxKeys {
+			listing.Truncated = true
+			return listing
+		}
+		count++
+
+		if isPrefix {
+			listing.Prefixes = append(listing.Prefixes, entry)
+		} else {
+			listing.Objects = append(listing.Objects, entry)
+		}
+		listing.Next = entry
+	}
+
+	listing.Next = ""
+	return listing
+}
+
+func TestListObjectsConformance(t *testing.T) {
+	runTest(t, func(ctx context.Context, layer minio.ObjectLayer, metainfo storj.Metainfo, streams streams.Store) {
+		keys := []string{
+			"a", "a.b", "a.b.c", "a/b/c", "a/b/d", "a-b/c",
+			"logs-old", "logs/readme",
+			"logs/2018-11-01/app.log", "logs/2018-11-01/db.log",
+			"logs/2018-11-02/app.log", "logs/2018-12-01/app.log",
+			"photos/2018/a.jpg", "photos/2018/b.jpg", "photos/2019/c.jpg", "photos_x",
+			"x§y", "x§y§z", "ä/x", "z",
+		}
+		sort.Strings(keys)
+
+		buckets := map[string]storj.Cipher{
+			TestBucket: storj.Unencrypted,
+			DestBucket: storj.AESGCM,
+		}
+
+		for bucket, cipher := range buckets {
+			_, err := metainfo.CreateBucket(ctx, bucket, &storj.Bucket{PathCipher: cipher})
+			require.NoError(t, err)
+
+			for _, key := range keys {
+				_, err := createFile(ctx, metainfo, streams, bucket, key, &storj.CreateObject{}, []byte(key))
+				require.NoError(t, err)
+			}
+		}
+
+		prefixes := []string{"", "a", "a.", "a/", "logs", "logs/", "logs/2018-", "logs/2018-11", "photos/2018/", "x§", "missing/", "p"}
+		delimiters := []string{"", "/", "-", ".", "_", "§", "b"}
+		markers := []string{"", "a", "a/b/c", "logs/2018-11-01/", "logs/2018-11-01/app.log", "photos", "zz"}
+
+		for bucket := range buckets {
+			for _, prefix := range prefixes {
+				for _, delimiter := range delimiters {
+					for _, marker := range markers {
+						errTag := fmt.Sprintf("bucket %q, prefix %q, marker %q, delimiter %q", bucket, prefix, marker, delimiter)
+
+						expected := listS3(keys, prefix, marker, delimiter, 1000)
+						result, err := layer.ListObjects(ctx, bucket, prefix, marker, delimiter, 1000)
+						if assert.NoError(t, err, errTag) {
+							assert.Equal(t, expected, listingV1(result), errTag)
+						}
+					}
+
+					// list all the keys page by page following the markers
+					// and the continuation tokens
+					errTag := fmt.Sprintf("bucket %q, prefix %q, delimiter %q", bucket, prefix, delimiter)
+
+					marker := ""
+					for page := 0; ; page++ {
+						require.True(t, page <= len(keys), errTag)
+
+						expected := listS3(keys, prefix, marker, delimiter, 2)
+						result, err := layer.ListObjects(ctx, bucket, prefix, marker, delimiter, 2)
+						require.NoError(t, err, errTag)
+						require.Equal(t, expected, listingV1(result), errTag)
+						if !result.IsTruncated {
+							break
+						}
+						marker = result.NextMarker
+					}
+
+					token := ""
+					for page := 0; ; page++ {
+						require.True(t, page <= len(keys), errTag)
+
+						expected := listS3(keys, prefix, token, delimiter, 3)
+						result, err := layer.ListObjectsV2(ctx, bucket, prefix, token, delimiter, 3, false, "")
+						require.NoError(t, err, errTag)
+						require.Equal(t, expected, listingV2(result), errTag)
+						if !result.IsTruncated {
+							break
+						}
+						token = result.NextContinuationToken
+					}
+				}
+			}
+		}
+	})
+}
+
+func listingV1(result minio.ListObjectsInfo) s3Listing {
+	listing := s3Listing{
+		Prefixes:  result.Prefixes,
+		Truncated: result.IsTruncated,
+		Next:      result.NextMarker,
+	}
+	for _, object := range result.Objects {
+		listing.Objects = append(listing.Objects, object.Name)
+	}
+	return listing
+}
+
+func listingV2(result minio.ListObjectsV2Info) s3Listing {
+	listing := s3Listing{
+		Prefixes:  result.Prefixes,
+		Truncated: result.IsTruncated,
+		Next:      result.NextContinuationToken,
+	}
+	for _, object := range result.Objects {
+		listing.Objects = append(listing.Objects, object.Name)
+	}
+	return listing
+}
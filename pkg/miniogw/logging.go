@@ -15,6 +15,8 @@ import (
 	"github.com/minio/minio/pkg/madmin"
 	"github.com/minio/minio/pkg/policy"
 	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/storj"
 )
 
 type gatewayLogging struct {
@@ -199,6 +201,31 @@ func (log *layerLogging) DeleteBucketPolicy(ctx context.Context, n string) error
 	return log.log(log.layer.DeleteBucketPolicy(ctx, n))
 }
 
+func (log *layerLogging) GetBucketLifecycle(ctx context.Context, bucket string) ([]storj.LifecycleRule, error) {
+	layer, ok := log.layer.(BucketLifecycle)
+	if !ok {
+		return nil, minio.NotImplemented{}
+	}
+	rules, err := layer.GetBucketLifecycle(ctx, bucket)
+	return rules, log.log(err)
+}
+
+func (log *layerLogging) SetBucketLifecycle(ctx context.Context, bucket string, rules []storj.LifecycleRule) error {
+	layer, ok := log.layer.(BucketLifecycle)
+	if !ok {
+		return minio.NotImplemented{}
+	}
+	return log.log(layer.SetBucketLifecycle(ctx, bucket, rules))
+}
+
+func (log *layerLogging) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
+	layer, ok := log.layer.(BucketLifecycle)
+	if !ok {
+		return minio.NotImplemented{}
+	}
+	return log.log(layer.DeleteBucketLifecycle(ctx, bucket))
+}
+
 func (log *layerLogging) IsNotificationSupported() bool {
 	return log.layer.IsNotificationSupported()
 }
@@ -5,6 +5,9 @@ package miniogw
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
 	"io"
 	"sort"
 	"strconv"
@@ -39,10 +42,8 @@ func (layer *gatewayLayer) NewMultipartUpload(ctx context.Context, bucket, objec
 		delete(metadata, "content-type")
 
 		createInfo := storj.CreateObject{
-			ContentType:      contentType,
-			Metadata:         metadata,
-			RedundancyScheme: layer.gateway.redundancy,
-			EncryptionScheme: layer.gateway.encryption,
+			ContentType: contentType,
+			Metadata:    metadata,
 		}
 		objInfo, err := layer.putObject(ctx, bucket, object, upload.Stream, &createInfo)
 
@@ -81,7 +82,7 @@ func (layer *gatewayLayer) PutObjectPart(ctx context.Context, bucket, object, up
 	partInfo := minio.PartInfo{
 		PartNumber:   part.Number,
 		LastModified: time.Now(),
-		ETag:         data.SHA256HexString(),
+		ETag:         hex.EncodeToString(data.MD5Current()),
 		Size:         atomic.LoadInt64(&part.Size),
 	}
 
@@ -317,6 +318,7 @@ type MultipartStream struct {
 	nextNumber  int
 	currentPart *StreamPart
 	parts       []*StreamPart
+	partMD5s    [][]byte
 }
 
 // StreamPart is a reader waiting in MultipartStream
@@ -407,6 +409,9 @@ func (stream *MultipartStream) Read(data []byte) (n int, err error) {
 	if err == io.EOF {
 		// the part completed, hence advance to the next one
 		err = nil
+		stream.mu.Lock()
+		stream.partMD5s = append(stream.partMD5s, stream.currentPart.Reader.MD5Current())
+		stream.mu.Unlock()
 		close(stream.currentPart.Done)
 		stream.currentPart = nil
 	} else if err != nil {
@@ -418,6 +423,24 @@ func (stream *MultipartStream) Read(data []byte) (n int, err error) {
 	return n, err
 }
 
+// ETag returns the S3 compatible ETag of the parts read so far, which is the
+// MD5 hash of the concatenated MD5 hashes of the parts followed by the number
+// of parts
+func (stream *MultipartStream) ETag() string {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if len(stream.partMD5s) == 0 {
+		return ""
+	}
+
+	hash := md5.New()
+	for _, partMD5 := range stream.partMD5s {
+		_, _ = hash.Write(partMD5)
+	}
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(hash.Sum(nil)), len(stream.partMD5s))
+}
+
 // AddPart adds a new part to the stream to wait
 func (stream *MultipartStream) AddPart(partID int, data *hash.Reader) (*StreamPart, error) {
 	stream.mu.Lock()
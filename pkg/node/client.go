@@ -24,8 +24,6 @@ func NewNodeClient(identity *provider.FullIdentity, self pb.Node, dht dht.DHT) (
 		pool: NewConnectionPool(identity),
 	}
 
-	node.pool.Init()
-
 	return node, nil
 }
 
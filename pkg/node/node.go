@@ -19,10 +19,11 @@ type Node struct {
 
 // Lookup queries nodes looking for a particular node in the network
 func (node *Node) Lookup(ctx context.Context, to pb.Node, find pb.Node) ([]*pb.Node, error) {
-	conn, err := node.pool.Dial(ctx, &to)
+	conn, release, err := node.pool.Dial(ctx, &to)
 	if err != nil {
 		return nil, NodeClientErr.Wrap(err)
 	}
+	defer release()
 
 	resp, err := conn.Query(ctx, &pb.QueryRequest{
 		Limit:    20,
@@ -49,10 +50,11 @@ func (node *Node) Lookup(ctx context.Context, to pb.Node, find pb.Node) ([]*pb.N
 
 // Ping attempts to establish a connection with a node to verify it is alive
 func (node *Node) Ping(ctx context.Context, to pb.Node) (bool, error) {
-	conn, err := node.pool.Dial(ctx, &to)
+	conn, release, err := node.pool.Dial(ctx, &to)
 	if err != nil {
 		return false, NodeClientErr.Wrap(err)
 	}
+	defer release()
 
 	_, err = conn.Ping(ctx, &pb.PingRequest{})
 	if err != nil {
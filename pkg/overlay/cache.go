@@ -5,6 +5,7 @@ package overlay
 
 import (
 	"context"
+	"sync"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/zeebo/errs"
@@ -38,6 +39,12 @@ type Cache struct {
 	DB     storage.KeyValueStore
 	DHT    dht.DHT
 	StatDB statdb.DB
+
+	offlineMu sync.Mutex
+	// offline are the nodes which failed their last uptime check
+	offline map[storj.NodeID]bool
+	// onOffline are called when a node goes offline
+	onOffline []func(storj.NodeID)
 }
 
 // NewOverlayCache returns a new Cache
@@ -98,6 +105,45 @@ func (o *Cache) GetAll(ctx context.Context, nodeIDs storj.NodeIDList) ([]*pb.Nod
 	return ns, nil
 }
 
+// List returns all the nodes of the cache
+func (o *Cache) List(ctx context.Context) (nodes []*pb.Node, err error) {
+	// the keys are listed a page at a time, as not all the stores can
+	// iterate over their items. The first key isn't nil, which the SQL
+	// stores compare as NULL.
+	start := storage.Key{}
+	for {
+		keys, err := o.DB.List(start, storage.LookupLimit)
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) == 0 {
+			return nodes, nil
+		}
+
+		values, err := o.DB.GetAll(keys)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			// the nodes deleted since they were listed
+			if v == nil {
+				continue
+			}
+			na := &pb.Node{}
+			if err := proto.Unmarshal(v, na); err != nil {
+				return nil, OverlayError.New("could not unmarshal node: %v", err)
+			}
+			nodes = append(nodes, na)
+		}
+
+		if len(keys) < storage.LookupLimit {
+			return nodes, nil
+		}
+		last := keys[len(keys)-1]
+		start = append(append(storage.Key{}, last...), 0)
+	}
+}
+
 // Put adds a nodeID to the redis cache with a binary representation of proto defined Node
 func (o *Cache) Put(ctx context.Context, nodeID storj.NodeID, value pb.Node) error {
 	// If we get a Node without an ID (i.e. bootstrap node)
@@ -128,3 +174,56 @@ func (o *Cache) Put(ctx context.Context, nodeID storj.NodeID, value pb.Node) err
 
 	return o.DB.Put(nodeID.Bytes(), data)
 }
+
+// Delete removes the node from the cache
+func (o *Cache) Delete(ctx context.Context, nodeID storj.NodeID) error {
+	if nodeID.IsZero() {
+		return ErrEmptyNode
+	}
+	if err := o.DB.Delete(nodeID.Bytes()); err != nil && !storage.ErrKeyNotFound.Has(err) {
+		return err
+	}
+	o.MarkOnline(nodeID)
+	return nil
+}
+
+// MarkOffline marks the node offline, it's skipped when selecting nodes and
+// reported missing by lookups until it's marked online again
+func (o *Cache) MarkOffline(nodeID storj.NodeID) {
+	o.offlineMu.Lock()
+	if o.offline[nodeID] {
+		o.offlineMu.Unlock()
+		return
+	}
+	if o.offline == nil {
+		o.offline = make(map[storj.NodeID]bool)
+	}
+	o.offline[nodeID] = true
+	callbacks := o.onOffline
+	o.offlineMu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(nodeID)
+	}
+}
+
+// MarkOnline marks the node online again
+func (o *Cache) MarkOnline(nodeID storj.NodeID) {
+	o.offlineMu.Lock()
+	defer o.offlineMu.Unlock()
+	delete(o.offline, nodeID)
+}
+
+// IsOffline returns whether the node is marked offline
+func (o *Cache) IsOffline(nodeID storj.NodeID) bool {
+	o.offlineMu.Lock()
+	defer o.offlineMu.Unlock()
+	return o.offline[nodeID]
+}
+
+// OnOffline registers fn to be called when a node is marked offline
+func (o *Cache) OnOffline(fn func(storj.NodeID)) {
+	o.offlineMu.Lock()
+	defer o.offlineMu.Unlock()
+	o.onOffline = append(o.onOffline, fn)
+}
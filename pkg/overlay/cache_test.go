@@ -98,6 +98,48 @@ func testCache(ctx context.Context, t *testing.T, store storage.KeyValueStore, s
 			assert.Error(t, err)
 		}
 	}
+
+	{ // List
+		nodes, err := cache.List(ctx)
+		assert.NoError(t, err)
+		var ids storj.NodeIDList
+		for _, node := range nodes {
+			ids = append(ids, node.Id)
+		}
+		assert.ElementsMatch(t, storj.NodeIDList{valid1ID, valid2ID}, ids)
+	}
+
+	{ // MarkOffline
+		var wentOffline storj.NodeIDList
+		cache.OnOffline(func(id storj.NodeID) { wentOffline = append(wentOffline, id) })
+
+		assert.False(t, cache.IsOffline(valid1ID))
+
+		cache.MarkOffline(valid1ID)
+		cache.MarkOffline(valid1ID)
+		assert.True(t, cache.IsOffline(valid1ID))
+		assert.False(t, cache.IsOffline(valid2ID))
+		assert.Equal(t, storj.NodeIDList{valid1ID}, wentOffline)
+
+		cache.MarkOnline(valid1ID)
+		assert.False(t, cache.IsOffline(valid1ID))
+	}
+
+	{ // Delete
+		cache.MarkOffline(valid2ID)
+		err := cache.Delete(ctx, valid2ID)
+		assert.NoError(t, err)
+		assert.False(t, cache.IsOffline(valid2ID))
+
+		_, err = cache.Get(ctx, valid2ID)
+		assert.True(t, err == overlay.ErrNodeNotFound)
+
+		err = cache.Delete(ctx, missingID)
+		assert.NoError(t, err)
+
+		err = cache.Delete(ctx, storj.NodeID{})
+		assert.True(t, err == overlay.ErrEmptyNode)
+	}
 }
 
 func TestCache_Masterdb(t *testing.T) {
@@ -34,7 +34,8 @@ type Client interface {
 
 // Overlay is the overlay concrete implementation of the client interface
 type Overlay struct {
-	client pb.OverlayClient
+	client   pb.OverlayClient
+	breakers *transport.Breakers
 }
 
 // Options contains parameters for selecting nodes
@@ -58,33 +59,55 @@ func NewOverlayClient(identity *provider.FullIdentity, address string) (Client,
 	}
 
 	return &Overlay{
-		client: pb.NewOverlayClient(conn),
+		client:   pb.NewOverlayClient(conn),
+		breakers: transport.DefaultBreakers(),
 	}, nil
 }
 
 // NewClientFrom returns a new overlay.Client from a connection
-func NewClientFrom(conn pb.OverlayClient) Client { return &Overlay{conn} }
+func NewClientFrom(conn pb.OverlayClient) Client {
+	return &Overlay{client: conn, breakers: transport.DefaultBreakers()}
+}
 
 // a compiler trick to make sure *Overlay implements Client
 var _ Client = (*Overlay)(nil)
 
+// chooseRounds is the maximum number of times the nodes are chosen again
+// instead of the nodes failing recently
+const chooseRounds = 3
+
 // Choose implements the client.Choose interface
-func (o *Overlay) Choose(ctx context.Context, op Options) ([]*pb.Node, error) {
+func (o *Overlay) Choose(ctx context.Context, op Options) (nodes []*pb.Node, err error) {
 	var exIDs storj.NodeIDList
 	exIDs = append(exIDs, op.Excluded...)
-	// TODO(coyle): We will also need to communicate with the reputation service here
-	resp, err := o.client.FindStorageNodes(ctx, &pb.FindStorageNodesRequest{
-		Opts: &pb.OverlayOptions{
-			Amount:        int64(op.Amount),
-			Restrictions:  &pb.NodeRestrictions{FreeDisk: op.Space, FreeBandwidth: op.Bandwidth},
-			ExcludedNodes: exIDs,
-		},
-	})
-	if err != nil {
-		return nil, Error.Wrap(err)
+	for round := 0; round < chooseRounds; round++ {
+		// TODO(coyle): We will also need to communicate with the reputation service here
+		resp, err := o.client.FindStorageNodes(ctx, &pb.FindStorageNodesRequest{
+			Opts: &pb.OverlayOptions{
+				Amount:        int64(op.Amount),
+				Restrictions:  &pb.NodeRestrictions{FreeDisk: op.Space, FreeBandwidth: op.Bandwidth},
+				ExcludedNodes: exIDs,
+			},
+		})
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		nodes = resp.GetNodes()
+
+		// choose again instead of the nodes failing recently
+		var unavailable storj.NodeIDList
+		for _, n := range nodes {
+			if !o.breakers.Available(n) {
+				unavailable = append(unavailable, n.Id)
+			}
+		}
+		if len(unavailable) == 0 {
+			break
+		}
+		exIDs = append(exIDs, unavailable...)
 	}
-
-	return resp.GetNodes(), nil
+	// the requests to the nodes still failing are rejected by their breakers
+	return nodes, nil
 }
 
 // Lookup provides a Node with the given ID
@@ -14,6 +14,7 @@ import (
 
 	"storj.io/storj/pkg/kademlia"
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/statdb"
 	"storj.io/storj/pkg/storj"
@@ -59,7 +60,7 @@ const (
 
 // Run implements the provider.Responsibility interface. Run assumes a
 // Kademlia responsibility has been started before this one.
-func (c Config) Run(ctx context.Context, server *provider.Provider) (
+func (c *Config) Run(ctx context.Context, server *provider.Provider) (
 	err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -78,21 +79,34 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (
 
 	cache := NewOverlayCache(sdb.OverlayCache(), kad, sdb.StatDB())
 
-	ns := &pb.NodeStats{
-		UptimeCount:       c.Node.UptimeCount,
-		UptimeRatio:       c.Node.UptimeRatio,
-		AuditSuccessRatio: c.Node.AuditSuccessRatio,
-		AuditCount:        c.Node.AuditCount,
-	}
-
-	srv := NewServer(zap.L(), cache, kad, ns)
+	srv := NewServer(zap.L(), cache, kad, c.Node.nodeStats())
 	pb.RegisterOverlayServer(server.GRPC(), srv)
 
+	defer process.OnReload(func(ctx context.Context) error {
+		srv.SetNodeStats(c.Node.nodeStats())
+		zap.L().Info("node selection applied",
+			zap.Int64("uptime count", c.Node.UptimeCount),
+			zap.Float64("uptime ratio", c.Node.UptimeRatio),
+			zap.Int64("audit count", c.Node.AuditCount),
+			zap.Float64("audit success ratio", c.Node.AuditSuccessRatio))
+		return nil
+	})()
+
 	ctx2 := context.WithValue(ctx, ctxKeyOverlay, cache)
 	ctx2 = context.WithValue(ctx2, ctxKeyOverlayServer, srv)
 	return server.Run(ctx2)
 }
 
+// nodeStats returns the minimum reputation of the selected nodes
+func (c NodeSelectionConfig) nodeStats() *pb.NodeStats {
+	return &pb.NodeStats{
+		UptimeCount:       c.UptimeCount,
+		UptimeRatio:       c.UptimeRatio,
+		AuditSuccessRatio: c.AuditSuccessRatio,
+		AuditCount:        c.AuditCount,
+	}
+}
+
 // LoadFromContext gives access to the cache from the context, or returns nil
 func LoadFromContext(ctx context.Context) *Cache {
 	if v, ok := ctx.Value(ctxKeyOverlay).(*Cache); ok {
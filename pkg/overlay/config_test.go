@@ -19,12 +19,12 @@ func TestRun(t *testing.T) {
 	ctx := context.WithValue(bctx, kadKey, kad)
 
 	// run with nil
-	err := Config{}.Run(context.Background(), nil)
+	err := (&Config{}).Run(context.Background(), nil)
 	assert.Error(t, err)
 	assert.Equal(t, "overlay error: programmer error: kademlia responsibility unstarted", err.Error())
 
 	// run with nil, pass pointer to Kademlia in context
-	err = Config{}.Run(ctx, nil)
+	err = (&Config{}).Run(ctx, nil)
 	assert.Error(t, err)
 	assert.Equal(t, "overlay error: unable to get master db instance", err.Error())
 }
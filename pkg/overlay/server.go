@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/zeebo/errs"
@@ -26,10 +27,12 @@ var ServerError = errs.Class("Server Error")
 
 // Server implements our overlay RPC service
 type Server struct {
-	logger    *zap.Logger
-	dht       dht.DHT
-	cache     *Cache
-	metrics   *monkit.Registry
+	logger  *zap.Logger
+	dht     dht.DHT
+	cache   *Cache
+	metrics *monkit.Registry
+
+	mu        sync.Mutex
 	nodeStats *pb.NodeStats
 }
 
@@ -44,6 +47,20 @@ func NewServer(log *zap.Logger, cache *Cache, dht dht.DHT, nodeStats *pb.NodeSta
 	}
 }
 
+// SetNodeStats changes the minimum reputation of the nodes returned by
+// FindStorageNodes
+func (o *Server) SetNodeStats(nodeStats *pb.NodeStats) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.nodeStats = nodeStats
+}
+
+func (o *Server) minimumNodeStats() *pb.NodeStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.nodeStats
+}
+
 // Lookup finds the address of a node in our overlay network
 func (o *Server) Lookup(ctx context.Context, req *pb.LookupRequest) (*pb.LookupResponse, error) {
 	na, err := o.cache.Get(ctx, req.NodeId)
@@ -58,12 +75,18 @@ func (o *Server) Lookup(ctx context.Context, req *pb.LookupRequest) (*pb.LookupR
 	}, nil
 }
 
-// BulkLookup finds the addresses of nodes in our overlay network
+// BulkLookup finds the addresses of nodes in our overlay network. The nodes
+// marked offline are reported missing.
 func (o *Server) BulkLookup(ctx context.Context, reqs *pb.LookupRequests) (*pb.LookupResponses, error) {
 	ns, err := o.cache.GetAll(ctx, lookupRequestsToNodeIDs(reqs))
 	if err != nil {
 		return nil, ServerError.New("could not get nodes requested %s\n", err)
 	}
+	for i, n := range ns {
+		if n != nil && o.cache.IsOffline(n.Id) {
+			ns[i] = nil
+		}
+	}
 	return nodesToLookupResponses(ns), nil
 }
 
@@ -77,7 +100,7 @@ func (o *Server) FindStorageNodes(ctx context.Context, req *pb.FindStorageNodesR
 
 	excluded := opts.ExcludedNodes
 	restrictions := opts.GetRestrictions()
-	reputation := o.nodeStats
+	reputation := o.minimumNodeStats()
 
 	var startID storj.NodeID
 	result := []*pb.Node{}
@@ -181,7 +204,8 @@ func (o *Server) populate(ctx context.Context, startID storj.NodeID, maxNodes in
 			nodeReputation.GetUptimeCount() < minReputation.GetUptimeCount() ||
 			nodeReputation.GetAuditSuccessRatio() < minReputation.GetAuditSuccessRatio() ||
 			nodeReputation.GetAuditCount() < minReputation.GetAuditCount() ||
-			contains(excluded, v.Id) {
+			contains(excluded, v.Id) ||
+			o.cache.IsOffline(v.Id) {
 			continue
 		}
 		result = append(result, v)
@@ -676,6 +676,123 @@ func (m *LookupNodeResponse) GetMeta() *NodeMetadata {
 	return nil
 }
 
+// GetCrawlStats
+type GetCrawlStatsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetCrawlStatsRequest) Reset()         { *m = GetCrawlStatsRequest{} }
+func (m *GetCrawlStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetCrawlStatsRequest) ProtoMessage()    {}
+func (*GetCrawlStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_inspector_efccc2bc3c2c22f4, []int{16}
+}
+func (m *GetCrawlStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCrawlStatsRequest.Unmarshal(m, b)
+}
+func (m *GetCrawlStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCrawlStatsRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetCrawlStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCrawlStatsRequest.Merge(dst, src)
+}
+func (m *GetCrawlStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetCrawlStatsRequest.Size(m)
+}
+func (m *GetCrawlStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCrawlStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCrawlStatsRequest proto.InternalMessageInfo
+
+type GetCrawlStatsResponse struct {
+	Crawls               int64    `protobuf:"varint,1,opt,name=crawls,proto3" json:"crawls,omitempty"`
+	LastCrawlUnixSec     int64    `protobuf:"varint,2,opt,name=last_crawl_unix_sec,json=lastCrawlUnixSec,proto3" json:"last_crawl_unix_sec,omitempty"`
+	NodesQueried         int64    `protobuf:"varint,3,opt,name=nodes_queried,json=nodesQueried,proto3" json:"nodes_queried,omitempty"`
+	QueriesFailed        int64    `protobuf:"varint,4,opt,name=queries_failed,json=queriesFailed,proto3" json:"queries_failed,omitempty"`
+	NodesFound           int64    `protobuf:"varint,5,opt,name=nodes_found,json=nodesFound,proto3" json:"nodes_found,omitempty"`
+	NodesRefreshed       int64    `protobuf:"varint,6,opt,name=nodes_refreshed,json=nodesRefreshed,proto3" json:"nodes_refreshed,omitempty"`
+	NodesEvicted         int64    `protobuf:"varint,7,opt,name=nodes_evicted,json=nodesEvicted,proto3" json:"nodes_evicted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetCrawlStatsResponse) Reset()         { *m = GetCrawlStatsResponse{} }
+func (m *GetCrawlStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetCrawlStatsResponse) ProtoMessage()    {}
+func (*GetCrawlStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_inspector_efccc2bc3c2c22f4, []int{17}
+}
+func (m *GetCrawlStatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCrawlStatsResponse.Unmarshal(m, b)
+}
+func (m *GetCrawlStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCrawlStatsResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetCrawlStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCrawlStatsResponse.Merge(dst, src)
+}
+func (m *GetCrawlStatsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetCrawlStatsResponse.Size(m)
+}
+func (m *GetCrawlStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCrawlStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCrawlStatsResponse proto.InternalMessageInfo
+
+func (m *GetCrawlStatsResponse) GetCrawls() int64 {
+	if m != nil {
+		return m.Crawls
+	}
+	return 0
+}
+
+func (m *GetCrawlStatsResponse) GetLastCrawlUnixSec() int64 {
+	if m != nil {
+		return m.LastCrawlUnixSec
+	}
+	return 0
+}
+
+func (m *GetCrawlStatsResponse) GetNodesQueried() int64 {
+	if m != nil {
+		return m.NodesQueried
+	}
+	return 0
+}
+
+func (m *GetCrawlStatsResponse) GetQueriesFailed() int64 {
+	if m != nil {
+		return m.QueriesFailed
+	}
+	return 0
+}
+
+func (m *GetCrawlStatsResponse) GetNodesFound() int64 {
+	if m != nil {
+		return m.NodesFound
+	}
+	return 0
+}
+
+func (m *GetCrawlStatsResponse) GetNodesRefreshed() int64 {
+	if m != nil {
+		return m.NodesRefreshed
+	}
+	return 0
+}
+
+func (m *GetCrawlStatsResponse) GetNodesEvicted() int64 {
+	if m != nil {
+		return m.NodesEvicted
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*GetStatsRequest)(nil), "inspector.GetStatsRequest")
 	proto.RegisterType((*GetStatsResponse)(nil), "inspector.GetStatsResponse")
@@ -693,6 +810,8 @@ func init() {
 	proto.RegisterType((*PingNodeResponse)(nil), "inspector.PingNodeResponse")
 	proto.RegisterType((*LookupNodeRequest)(nil), "inspector.LookupNodeRequest")
 	proto.RegisterType((*LookupNodeResponse)(nil), "inspector.LookupNodeResponse")
+	proto.RegisterType((*GetCrawlStatsRequest)(nil), "inspector.GetCrawlStatsRequest")
+	proto.RegisterType((*GetCrawlStatsResponse)(nil), "inspector.GetCrawlStatsResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -718,6 +837,8 @@ type InspectorClient interface {
 	PingNode(ctx context.Context, in *PingNodeRequest, opts ...grpc.CallOption) (*PingNodeResponse, error)
 	// LookupNode triggers a Kademlia FindNode and returns the response
 	LookupNode(ctx context.Context, in *LookupNodeRequest, opts ...grpc.CallOption) (*LookupNodeResponse, error)
+	// GetCrawlStats returns the statistics of the crawls of the network by discovery
+	GetCrawlStats(ctx context.Context, in *GetCrawlStatsRequest, opts ...grpc.CallOption) (*GetCrawlStatsResponse, error)
 	// StatDB commands:
 	// GetStats returns the stats for a particular node ID
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
@@ -778,6 +899,15 @@ func (c *inspectorClient) LookupNode(ctx context.Context, in *LookupNodeRequest,
 	return out, nil
 }
 
+func (c *inspectorClient) GetCrawlStats(ctx context.Context, in *GetCrawlStatsRequest, opts ...grpc.CallOption) (*GetCrawlStatsResponse, error) {
+	out := new(GetCrawlStatsResponse)
+	err := c.cc.Invoke(ctx, "/inspector.Inspector/GetCrawlStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *inspectorClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
 	out := new(GetStatsResponse)
 	err := c.cc.Invoke(ctx, "/inspector.Inspector/GetStats", in, out, opts...)
@@ -809,6 +939,8 @@ type InspectorServer interface {
 	PingNode(context.Context, *PingNodeRequest) (*PingNodeResponse, error)
 	// LookupNode triggers a Kademlia FindNode and returns the response
 	LookupNode(context.Context, *LookupNodeRequest) (*LookupNodeResponse, error)
+	// GetCrawlStats returns the statistics of the crawls of the network by discovery
+	GetCrawlStats(context.Context, *GetCrawlStatsRequest) (*GetCrawlStatsResponse, error)
 	// StatDB commands:
 	// GetStats returns the stats for a particular node ID
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
@@ -910,6 +1042,24 @@ func _Inspector_LookupNode_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Inspector_GetCrawlStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCrawlStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InspectorServer).GetCrawlStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/inspector.Inspector/GetCrawlStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InspectorServer).GetCrawlStats(ctx, req.(*GetCrawlStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Inspector_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetStatsRequest)
 	if err := dec(in); err != nil {
@@ -970,6 +1120,10 @@ var _Inspector_serviceDesc = grpc.ServiceDesc{
 			MethodName: "LookupNode",
 			Handler:    _Inspector_LookupNode_Handler,
 		},
+		{
+			MethodName: "GetCrawlStats",
+			Handler:    _Inspector_GetCrawlStats_Handler,
+		},
 		{
 			MethodName: "GetStats",
 			Handler:    _Inspector_GetStats_Handler,
@@ -986,45 +1140,55 @@ var _Inspector_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("inspector.proto", fileDescriptor_inspector_efccc2bc3c2c22f4) }
 
 var fileDescriptor_inspector_efccc2bc3c2c22f4 = []byte{
-	// 636 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xdd, 0x8e, 0xd2, 0x40,
-	0x18, 0xb5, 0xa5, 0xcb, 0xc2, 0x07, 0x81, 0x65, 0x58, 0x13, 0x52, 0x70, 0xc1, 0xb9, 0x50, 0xe2,
-	0x05, 0x31, 0x78, 0x67, 0xe2, 0x0d, 0x18, 0x57, 0x14, 0x8d, 0xe9, 0xc6, 0x1b, 0x63, 0xb2, 0x99,
-	0xa5, 0x13, 0xd2, 0xf0, 0x33, 0xb5, 0x33, 0x35, 0xf1, 0x1d, 0x7c, 0x02, 0x9f, 0xc8, 0x67, 0xf0,
-	0x62, 0x6f, 0x7c, 0x11, 0xd3, 0x99, 0xe9, 0x1f, 0x2d, 0xd9, 0x8d, 0x77, 0xcc, 0x77, 0xce, 0x9c,
-	0xf9, 0xce, 0x9c, 0x8f, 0x29, 0xb4, 0xbd, 0x3d, 0xf7, 0xe9, 0x4a, 0xb0, 0x60, 0xe2, 0x07, 0x4c,
-	0x30, 0x54, 0x4f, 0x0a, 0x36, 0xac, 0xd9, 0x9a, 0xa9, 0xb2, 0x0d, 0x7b, 0xe6, 0x52, 0xf5, 0x1b,
-	0xbf, 0x84, 0xf6, 0x25, 0x15, 0x57, 0x82, 0x08, 0xee, 0xd0, 0x6f, 0x21, 0xe5, 0x02, 0x3d, 0x85,
-	0xd3, 0x88, 0x70, 0xed, 0xb9, 0x3d, 0x63, 0x64, 0x8c, 0x9b, 0xb3, 0xd6, 0xef, 0xdb, 0xe1, 0x83,
-	0x3f, 0xb7, 0xc3, 0xea, 0x47, 0xe6, 0xd2, 0xc5, 0x6b, 0xa7, 0x1a, 0xc1, 0x0b, 0x17, 0xff, 0x32,
-	0xe0, 0x2c, 0xdd, 0xcc, 0x7d, 0xb6, 0xe7, 0x14, 0x0d, 0xa1, 0x41, 0x42, 0xd7, 0x13, 0xd7, 0x2b,
-	0x16, 0xee, 0x85, 0x54, 0xa8, 0x38, 0x20, 0x4b, 0xf3, 0xa8, 0x92, 0x12, 0x02, 0x22, 0x3c, 0xd6,
-	0x33, 0x47, 0xc6, 0xd8, 0xd0, 0x04, 0x27, 0xaa, 0xa0, 0xc7, 0xd0, 0x0c, 0x7d, 0xe1, 0xed, 0xa8,
-	0x96, 0xa8, 0x48, 0x89, 0x86, 0xaa, 0x29, 0x8d, 0x94, 0xa2, 0x44, 0x2c, 0x29, 0xa2, 0x29, 0x52,
-	0x05, 0xff, 0x35, 0x00, 0xcd, 0x03, 0x4a, 0x04, 0xfd, 0x2f, 0x73, 0x87, 0x3e, 0xcc, 0x82, 0x8f,
-	0x09, 0x74, 0x15, 0x81, 0x87, 0xab, 0x15, 0xe5, 0x3c, 0xd7, 0x6d, 0x47, 0x42, 0x57, 0x0a, 0x39,
-	0xec, 0x59, 0x11, 0xad, 0xa2, 0xad, 0xe7, 0x70, 0xae, 0x29, 0x79, 0xcd, 0x13, 0x49, 0x45, 0x0a,
-	0xcb, 0x8a, 0xe2, 0x87, 0xd0, 0xcd, 0x99, 0x54, 0x21, 0xe0, 0x77, 0x80, 0x24, 0x1e, 0x79, 0x4a,
-	0xa3, 0xb1, 0xa1, 0xb6, 0x21, 0x2e, 0xdd, 0x6d, 0x3d, 0xa2, 0x73, 0x49, 0xd6, 0xa8, 0x07, 0xa7,
-	0xec, 0x3b, 0x0d, 0xb6, 0xe4, 0x87, 0xb6, 0x1a, 0x2f, 0x71, 0x17, 0x3a, 0x59, 0x2d, 0x79, 0x8d,
-	0x51, 0xf1, 0x92, 0x8a, 0x59, 0xb8, 0xda, 0xd0, 0xe4, 0x6e, 0xf1, 0x5b, 0x40, 0xd9, 0xa2, 0x3e,
-	0xf5, 0x1c, 0x4e, 0x04, 0x13, 0x64, 0xab, 0x8f, 0x54, 0x0b, 0x34, 0x80, 0x8a, 0xe7, 0xf2, 0x9e,
-	0x39, 0xaa, 0x8c, 0x9b, 0x33, 0xc8, 0xdc, 0x7f, 0x54, 0xc6, 0x53, 0x39, 0x58, 0x4a, 0x29, 0x4e,
-	0xee, 0x02, 0xcc, 0xa3, 0xa1, 0x99, 0x9e, 0x8b, 0x3f, 0x67, 0x5a, 0x4a, 0x0e, 0xbf, 0x63, 0x13,
-	0x1a, 0xc1, 0x49, 0x94, 0xb7, 0x6a, 0xa4, 0x31, 0x85, 0x89, 0xfc, 0x6b, 0x44, 0x04, 0x47, 0x01,
-	0xf8, 0x19, 0x54, 0x95, 0xe6, 0x3d, 0xb8, 0x13, 0x00, 0xc5, 0x5d, 0x7a, 0x3c, 0xc3, 0x37, 0x8e,
-	0xf1, 0xdf, 0x43, 0xfb, 0x93, 0xb7, 0x5f, 0xcb, 0xd2, 0xfd, 0x5c, 0x46, 0x39, 0x11, 0xd7, 0x0d,
-	0x28, 0xe7, 0x32, 0xa7, 0xba, 0x13, 0x2f, 0x31, 0x86, 0xb3, 0x54, 0x4c, 0xdb, 0x6f, 0x81, 0xc9,
-	0x36, 0x52, 0xad, 0xe6, 0x98, 0x6c, 0x83, 0x5f, 0x41, 0x67, 0xc9, 0xd8, 0x26, 0xf4, 0xb3, 0x47,
-	0xb6, 0x92, 0x23, 0xeb, 0x77, 0x1c, 0xf1, 0x15, 0x50, 0x76, 0x7b, 0x72, 0xc7, 0x56, 0x64, 0x47,
-	0x2a, 0xe4, 0x6d, 0xca, 0x3a, 0x7a, 0x02, 0xd6, 0x8e, 0x0a, 0x22, 0xc5, 0x1a, 0x53, 0x94, 0xe2,
-	0x1f, 0xa8, 0x20, 0x2e, 0x11, 0xc4, 0x91, 0xf8, 0xf4, 0xa7, 0x05, 0xf5, 0x45, 0xfc, 0x60, 0xa1,
-	0x05, 0x40, 0x3a, 0x76, 0x68, 0x30, 0x49, 0xdf, 0xb6, 0xc2, 0x34, 0xda, 0x8f, 0x8e, 0xa0, 0xba,
-	0xc1, 0x05, 0x40, 0x3a, 0x97, 0x39, 0xa9, 0xc2, 0x0c, 0xe7, 0xa4, 0x4a, 0x86, 0xf9, 0x0d, 0xd4,
-	0x93, 0x2a, 0xea, 0x97, 0x71, 0x63, 0xa1, 0x41, 0x39, 0xa8, 0x75, 0xe6, 0x50, 0x8b, 0xc3, 0x42,
-	0x76, 0x86, 0x79, 0x30, 0x0e, 0x76, 0xbf, 0x14, 0x4b, 0x7d, 0xa5, 0x71, 0xe4, 0x7c, 0x15, 0x42,
-	0xce, 0xf9, 0x2a, 0xc9, 0x70, 0x0e, 0xb5, 0xf8, 0x25, 0xcf, 0xf5, 0x73, 0xf0, 0x6d, 0xb0, 0xfb,
-	0xa5, 0x98, 0x16, 0x59, 0x42, 0x23, 0xf3, 0x18, 0xa1, 0x5c, 0x2a, 0x85, 0x97, 0xd8, 0xbe, 0x38,
-	0x06, 0x2b, 0xb5, 0x99, 0xf5, 0xc5, 0xf4, 0x6f, 0x6e, 0xaa, 0xf2, 0x33, 0xf5, 0xe2, 0x5f, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x1b, 0x61, 0x84, 0xdb, 0xdc, 0x06, 0x00, 0x00,
+	// 797 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xdb, 0x6e, 0x13, 0x3b,
+	0x14, 0x3d, 0x99, 0xa4, 0x69, 0xb3, 0x93, 0x26, 0xa9, 0xd3, 0x56, 0xd1, 0xf4, 0x92, 0x9c, 0x39,
+	0x3a, 0xa7, 0xd5, 0x91, 0x88, 0x50, 0x78, 0x43, 0xe2, 0x25, 0x81, 0x96, 0x40, 0x41, 0x30, 0x55,
+	0x5f, 0x10, 0x52, 0xe4, 0x8e, 0xdd, 0x32, 0x4a, 0x3a, 0x4e, 0xc7, 0x9e, 0x52, 0x3e, 0x05, 0xbe,
+	0x88, 0x6f, 0xe0, 0xa1, 0x42, 0xe2, 0x47, 0x90, 0x2f, 0x99, 0x4b, 0x2e, 0x6a, 0xc5, 0x5b, 0xbc,
+	0xd6, 0x9a, 0x65, 0x2f, 0x6f, 0x6f, 0x3b, 0x50, 0xf3, 0x03, 0x3e, 0xa1, 0x9e, 0x60, 0x61, 0x67,
+	0x12, 0x32, 0xc1, 0x50, 0x29, 0x06, 0x6c, 0xb8, 0x64, 0x97, 0x4c, 0xc3, 0x36, 0x04, 0x8c, 0x50,
+	0xfd, 0xdb, 0x79, 0x0a, 0xb5, 0x63, 0x2a, 0x4e, 0x05, 0x16, 0xdc, 0xa5, 0xd7, 0x11, 0xe5, 0x02,
+	0x1d, 0xc0, 0xaa, 0x14, 0x0c, 0x7d, 0xd2, 0xcc, 0xb5, 0x73, 0x87, 0x95, 0x5e, 0xf5, 0xfb, 0x5d,
+	0xeb, 0xaf, 0x1f, 0x77, 0xad, 0xe2, 0x5b, 0x46, 0xe8, 0xe0, 0xb9, 0x5b, 0x94, 0xf4, 0x80, 0x38,
+	0xdf, 0x72, 0x50, 0x4f, 0x3e, 0xe6, 0x13, 0x16, 0x70, 0x8a, 0x5a, 0x50, 0xc6, 0x11, 0xf1, 0xc5,
+	0xd0, 0x63, 0x51, 0x20, 0x94, 0x43, 0xde, 0x05, 0x05, 0xf5, 0x25, 0x92, 0x08, 0x42, 0x2c, 0x7c,
+	0xd6, 0xb4, 0xda, 0xb9, 0xc3, 0x9c, 0x11, 0xb8, 0x12, 0x41, 0x7f, 0x43, 0x25, 0x9a, 0x08, 0xff,
+	0x8a, 0x1a, 0x8b, 0xbc, 0xb2, 0x28, 0x6b, 0x4c, 0x7b, 0x24, 0x12, 0x6d, 0x52, 0x50, 0x26, 0x46,
+	0xa2, 0x5c, 0x9c, 0x5f, 0x39, 0x40, 0xfd, 0x90, 0x62, 0x41, 0xff, 0x28, 0xdc, 0x6c, 0x0e, 0x6b,
+	0x2e, 0x47, 0x07, 0x1a, 0x5a, 0xc0, 0x23, 0xcf, 0xa3, 0x9c, 0x67, 0x56, 0xbb, 0xa1, 0xa8, 0x53,
+	0xcd, 0xcc, 0xae, 0x59, 0x0b, 0x0b, 0xf3, 0xb1, 0x1e, 0xc3, 0xa6, 0x91, 0x64, 0x3d, 0x57, 0x94,
+	0x14, 0x69, 0x2e, 0x6d, 0xea, 0x6c, 0x41, 0x23, 0x13, 0x52, 0x17, 0xc1, 0x79, 0x05, 0x48, 0xf1,
+	0x32, 0x53, 0x52, 0x1a, 0x1b, 0xd6, 0x46, 0x98, 0xd0, 0xab, 0xb1, 0x8f, 0x4d, 0x5d, 0xe2, 0x31,
+	0x6a, 0xc2, 0x2a, 0xbb, 0xa1, 0xe1, 0x18, 0x7f, 0x31, 0x51, 0xa7, 0x43, 0xa7, 0x01, 0x1b, 0x69,
+	0x2f, 0xb5, 0x8d, 0x12, 0x3c, 0xa6, 0xa2, 0x17, 0x79, 0x23, 0x1a, 0xef, 0xad, 0xf3, 0x12, 0x50,
+	0x1a, 0x34, 0xb3, 0x6e, 0xc2, 0x8a, 0x60, 0x02, 0x8f, 0xcd, 0x94, 0x7a, 0x80, 0x76, 0x21, 0xef,
+	0x13, 0xde, 0xb4, 0xda, 0xf9, 0xc3, 0x4a, 0x0f, 0x52, 0xfb, 0x2f, 0x61, 0xa7, 0x0b, 0xf5, 0xd8,
+	0x69, 0x5a, 0xb9, 0x7d, 0xb0, 0x96, 0x16, 0xcd, 0xf2, 0x89, 0x73, 0x96, 0x5a, 0x52, 0x3c, 0xf9,
+	0x3d, 0x1f, 0xa1, 0x36, 0xac, 0xc8, 0x7a, 0xeb, 0x85, 0x94, 0xbb, 0xd0, 0x91, 0xa3, 0x8e, 0x14,
+	0xb8, 0x9a, 0x70, 0xfe, 0x87, 0xa2, 0xf6, 0x7c, 0x80, 0xb6, 0x03, 0xa0, 0xb5, 0x27, 0x3e, 0x4f,
+	0xe9, 0x73, 0xcb, 0xf4, 0xaf, 0xa1, 0xf6, 0xce, 0x0f, 0x2e, 0x15, 0xf4, 0xb0, 0x94, 0xb2, 0x4e,
+	0x98, 0x90, 0x90, 0x72, 0xae, 0xea, 0x54, 0x72, 0xa7, 0x43, 0xc7, 0x81, 0x7a, 0x62, 0x66, 0xe2,
+	0x57, 0xc1, 0x62, 0x23, 0xe5, 0xb6, 0xe6, 0x5a, 0x6c, 0xe4, 0x3c, 0x83, 0x8d, 0x13, 0xc6, 0x46,
+	0xd1, 0x24, 0x3d, 0x65, 0x35, 0x9e, 0xb2, 0x74, 0xcf, 0x14, 0x1f, 0x01, 0xa5, 0x3f, 0x8f, 0xf7,
+	0xb8, 0x20, 0xe3, 0x28, 0x87, 0x6c, 0x4c, 0x85, 0xa3, 0xff, 0xa0, 0x70, 0x45, 0x05, 0x56, 0x66,
+	0xe5, 0x2e, 0x4a, 0xf8, 0x37, 0x54, 0x60, 0x82, 0x05, 0x76, 0x15, 0xef, 0x6c, 0xc3, 0xe6, 0x31,
+	0x15, 0xfd, 0x10, 0x7f, 0x1e, 0xa7, 0x5b, 0xd6, 0xf9, 0x6a, 0xc1, 0xd6, 0x0c, 0x61, 0x66, 0xde,
+	0x86, 0xa2, 0x27, 0x51, 0x6e, 0xce, 0x96, 0x19, 0xa1, 0x47, 0xd0, 0x18, 0x63, 0x2e, 0x86, 0x6a,
+	0x38, 0x8c, 0x02, 0xff, 0x76, 0xc8, 0xa9, 0x67, 0x0e, 0x76, 0x5d, 0x52, 0xca, 0xec, 0x2c, 0xf0,
+	0x6f, 0x4f, 0xa9, 0x87, 0xfe, 0x81, 0x75, 0x55, 0x8f, 0xe1, 0x75, 0x44, 0x43, 0x9f, 0x12, 0xd3,
+	0xc3, 0x15, 0x05, 0xbe, 0xd7, 0x18, 0xfa, 0x17, 0xaa, 0x9a, 0xe6, 0xc3, 0x0b, 0xec, 0x8f, 0x29,
+	0x31, 0x0d, 0xbc, 0x6e, 0xd0, 0x23, 0x05, 0xca, 0x6b, 0x43, 0x7b, 0x5d, 0xb0, 0x28, 0x20, 0xa6,
+	0x73, 0xd5, 0x85, 0xcb, 0x8f, 0x24, 0x82, 0x0e, 0xa0, 0xa6, 0x05, 0x21, 0xbd, 0x08, 0x29, 0xff,
+	0x44, 0x49, 0xb3, 0xa8, 0x44, 0xd5, 0x40, 0x37, 0x98, 0x41, 0x93, 0x55, 0xd1, 0x1b, 0xdf, 0x13,
+	0x94, 0x34, 0x57, 0x53, 0xab, 0x7a, 0xa1, 0xb1, 0xee, 0xcf, 0x02, 0x94, 0x06, 0xd3, 0x4b, 0x1e,
+	0x0d, 0x00, 0x92, 0x56, 0x45, 0xbb, 0x9d, 0xe4, 0x3d, 0x98, 0xeb, 0x60, 0x7b, 0x6f, 0x09, 0x6b,
+	0xb6, 0x76, 0x00, 0x90, 0xf4, 0x72, 0xc6, 0x6a, 0xae, 0xef, 0xed, 0xbd, 0x25, 0xac, 0xb1, 0x3a,
+	0x82, 0x52, 0x8c, 0xa2, 0x9d, 0x45, 0xda, 0xa9, 0xd1, 0xee, 0x62, 0xd2, 0xf8, 0xf4, 0x61, 0x6d,
+	0x7a, 0xc0, 0x91, 0x9d, 0x52, 0xce, 0xb4, 0x90, 0xbd, 0xb3, 0x90, 0x4b, 0x72, 0x25, 0x47, 0x38,
+	0x93, 0x6b, 0xae, 0x31, 0xec, 0xbd, 0x25, 0xac, 0xb1, 0x72, 0x61, 0x3d, 0x73, 0x2c, 0x51, 0x2b,
+	0xbb, 0xfc, 0xb9, 0x93, 0x6c, 0xb7, 0x97, 0x0b, 0x92, 0x8c, 0xd3, 0x17, 0x35, 0x93, 0x71, 0xe6,
+	0x8d, 0xb6, 0x77, 0x16, 0x72, 0xc6, 0xe4, 0x04, 0xca, 0xa9, 0x47, 0x01, 0x65, 0x2a, 0x3d, 0xf7,
+	0x22, 0xda, 0xfb, 0xcb, 0x68, 0xed, 0xd6, 0x2b, 0x7c, 0xb0, 0x26, 0xe7, 0xe7, 0x45, 0xf5, 0x77,
+	0xe1, 0xc9, 0xef, 0x01, 0x00, 0xdb, 0x3d, 0xb0, 0x1d, 0x64, 0x08, 0x00, 0x00,
 }
@@ -69,6 +69,12 @@ type StreamInfo struct {
 	SegmentsSize         int64    `protobuf:"varint,2,opt,name=segments_size,json=segmentsSize,proto3" json:"segments_size,omitempty"`
 	LastSegmentSize      int64    `protobuf:"varint,3,opt,name=last_segment_size,json=lastSegmentSize,proto3" json:"last_segment_size,omitempty"`
 	Metadata             []byte   `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Checksum             []byte   `protobuf:"bytes,5,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Etag                 string   `protobuf:"bytes,6,opt,name=etag,proto3" json:"etag,omitempty"`
+	Compression          int32    `protobuf:"varint,7,opt,name=compression,proto3" json:"compression,omitempty"`
+	CompressionFrameSize int64    `protobuf:"varint,8,opt,name=compression_frame_size,json=compressionFrameSize,proto3" json:"compression_frame_size,omitempty"`
+	CompressedFrameSizes []int64  `protobuf:"varint,9,rep,packed,name=compressed_frame_sizes,json=compressedFrameSizes,proto3" json:"compressed_frame_sizes,omitempty"`
+	UncompressedSize     int64    `protobuf:"varint,10,opt,name=uncompressed_size,json=uncompressedSize,proto3" json:"uncompressed_size,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -126,6 +132,48 @@ func (m *StreamInfo) GetMetadata() []byte {
 	return nil
 }
 
+func (m *StreamInfo) GetChecksum() []byte {
+	if m != nil {
+		return m.Checksum
+	}
+	return nil
+}
+
+func (m *StreamInfo) GetEtag() string {
+	if m != nil {
+		return m.Etag
+	}
+	return ""
+}
+
+func (m *StreamInfo) GetCompression() int32 {
+	if m != nil {
+		return m.Compression
+	}
+	return 0
+}
+
+func (m *StreamInfo) GetCompressionFrameSize() int64 {
+	if m != nil {
+		return m.CompressionFrameSize
+	}
+	return 0
+}
+
+func (m *StreamInfo) GetCompressedFrameSizes() []int64 {
+	if m != nil {
+		return m.CompressedFrameSizes
+	}
+	return nil
+}
+
+func (m *StreamInfo) GetUncompressedSize() int64 {
+	if m != nil {
+		return m.UncompressedSize
+	}
+	return 0
+}
+
 type StreamMeta struct {
 	EncryptedStreamInfo  []byte       `protobuf:"bytes,1,opt,name=encrypted_stream_info,json=encryptedStreamInfo,proto3" json:"encrypted_stream_info,omitempty"`
 	EncryptionType       int32        `protobuf:"varint,2,opt,name=encryption_type,json=encryptionType,proto3" json:"encryption_type,omitempty"`
@@ -197,24 +245,30 @@ func init() {
 func init() { proto.RegisterFile("streams.proto", fileDescriptor_streams_c0d9754174b032dc) }
 
 var fileDescriptor_streams_c0d9754174b032dc = []byte{
-	// 304 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x51, 0xcb, 0x4e, 0xc3, 0x30,
-	0x10, 0x54, 0x5f, 0x50, 0xb6, 0x29, 0x05, 0x03, 0x52, 0x04, 0x17, 0x14, 0x0e, 0x20, 0x84, 0x7a,
-	0x28, 0x3f, 0x80, 0x7a, 0x43, 0x08, 0x2a, 0x25, 0x9c, 0xb8, 0x58, 0x4e, 0xba, 0x41, 0x51, 0x1a,
-	0x3b, 0x8a, 0xcd, 0xc1, 0xfd, 0x21, 0x3e, 0x8b, 0x5f, 0x41, 0xb6, 0xf3, 0x82, 0xe3, 0xce, 0x8c,
-	0x66, 0x67, 0x76, 0x61, 0x2e, 0x55, 0x85, 0xac, 0x90, 0xcb, 0xb2, 0x12, 0x4a, 0x90, 0xc3, 0x7a,
-	0x0c, 0x36, 0x30, 0x8b, 0xf0, 0xb3, 0x40, 0xae, 0x5e, 0x51, 0x31, 0x72, 0x03, 0x73, 0xe4, 0x49,
-	0xa5, 0x4b, 0x85, 0x5b, 0x9a, 0xa3, 0xf6, 0x07, 0xd7, 0x83, 0x3b, 0x2f, 0xf4, 0x5a, 0xf0, 0x05,
-	0x35, 0xb9, 0x82, 0xa3, 0x1c, 0x35, 0xe5, 0x82, 0x27, 0xe8, 0x0f, 0xad, 0x60, 0x9a, 0xa3, 0x7e,
-	0x33, 0x73, 0xf0, 0x3d, 0x00, 0x88, 0xac, 0xf9, 0x33, 0x4f, 0x05, 0x79, 0x00, 0xc2, 0xbf, 0x8a,
-	0x18, 0x2b, 0x2a, 0x52, 0x2a, 0xdd, 0x26, 0x69, 0x5d, 0x47, 0xe1, 0x89, 0x63, 0x36, 0x69, 0x9d,
-	0x40, 0x9a, 0xf5, 0x8d, 0x86, 0xca, 0x6c, 0xef, 0xdc, 0x47, 0xa1, 0xd7, 0x80, 0x51, 0xb6, 0x47,
-	0x72, 0x0f, 0xa7, 0x3b, 0x26, 0x55, 0xe3, 0xe6, 0x84, 0x23, 0x2b, 0x5c, 0x18, 0xa2, 0x76, 0xb3,
-	0xda, 0x4b, 0x98, 0x16, 0xa8, 0xd8, 0x96, 0x29, 0xe6, 0x8f, 0x5d, 0xd2, 0x66, 0x0e, 0x7e, 0xda,
-	0xa4, 0xb6, 0xfa, 0x0a, 0x2e, 0xba, 0xea, 0xee, 0x3c, 0x34, 0xe3, 0xa9, 0xa8, 0x4f, 0x70, 0xd6,
-	0x92, 0xbd, 0x76, 0xb7, 0xb0, 0xa8, 0xe1, 0x4c, 0x70, 0xaa, 0x74, 0xe9, 0x12, 0x4f, 0xc2, 0xe3,
-	0x0e, 0x7e, 0xd7, 0x25, 0xf6, 0xcc, 0x8d, 0x30, 0xde, 0x89, 0x24, 0xef, 0x72, 0x4f, 0x5a, 0xf3,
-	0x4c, 0xf0, 0xb5, 0xe1, 0x6c, 0xf6, 0xa7, 0x7f, 0x3d, 0x4d, 0x70, 0x5b, 0x62, 0xb6, 0x3a, 0x5f,
-	0x36, 0xef, 0xec, 0x3d, 0xef, 0x4f, 0x7b, 0x03, 0xac, 0xc7, 0x1f, 0xc3, 0x32, 0x8e, 0x0f, 0xec,
-	0xcb, 0x1f, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0xdf, 0x5e, 0x32, 0x31, 0x03, 0x02, 0x00, 0x00,
+	// 399 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x52, 0xc1, 0xae, 0xd3, 0x30,
+	0x10, 0x54, 0x5e, 0x9a, 0xd7, 0x76, 0xdb, 0x52, 0x6a, 0x0a, 0x8a, 0xe0, 0x12, 0x95, 0x03, 0x15,
+	0xa0, 0x1e, 0x0a, 0x1f, 0x80, 0x7a, 0x40, 0x42, 0x08, 0x2a, 0xa5, 0x9c, 0xb8, 0x44, 0x4e, 0xb2,
+	0x29, 0x51, 0x6a, 0x3b, 0x8a, 0xdd, 0x43, 0xfa, 0x19, 0xfc, 0x20, 0xbf, 0x82, 0x6c, 0x27, 0xa9,
+	0xe1, 0x96, 0x9d, 0x99, 0x9d, 0xdd, 0x78, 0x16, 0x16, 0x52, 0x35, 0x48, 0x99, 0xdc, 0xd5, 0x8d,
+	0x50, 0x82, 0x8c, 0xbb, 0x72, 0x73, 0x84, 0xd9, 0x09, 0xcf, 0x0c, 0xb9, 0xfa, 0x86, 0x8a, 0x92,
+	0xd7, 0xb0, 0x40, 0x9e, 0x35, 0x6d, 0xad, 0x30, 0x4f, 0x2a, 0x6c, 0x43, 0x2f, 0xf2, 0xb6, 0xf3,
+	0x78, 0x3e, 0x80, 0x5f, 0xb1, 0x25, 0xaf, 0x60, 0x5a, 0x61, 0x9b, 0x70, 0xc1, 0x33, 0x0c, 0x1f,
+	0x8c, 0x60, 0x52, 0x61, 0xfb, 0x5d, 0xd7, 0x9b, 0xdf, 0x3e, 0xc0, 0xc9, 0x98, 0x7f, 0xe1, 0x85,
+	0x20, 0xef, 0x81, 0xf0, 0x2b, 0x4b, 0xb1, 0x49, 0x44, 0x91, 0x48, 0x3b, 0x49, 0x1a, 0x57, 0x3f,
+	0x7e, 0x6a, 0x99, 0x63, 0xd1, 0x6d, 0x20, 0xf5, 0xf8, 0x5e, 0x93, 0xc8, 0xf2, 0x66, 0xdd, 0xfd,
+	0x78, 0xde, 0x83, 0xa7, 0xf2, 0x86, 0xe4, 0x2d, 0xac, 0x2e, 0x54, 0xaa, 0xde, 0xcd, 0x0a, 0x7d,
+	0x23, 0x5c, 0x6a, 0xa2, 0x73, 0x33, 0xda, 0x97, 0x30, 0x61, 0xa8, 0x68, 0x4e, 0x15, 0x0d, 0x47,
+	0x76, 0xd3, 0xbe, 0xd6, 0x5c, 0xf6, 0x0b, 0xb3, 0x4a, 0x5e, 0x59, 0x18, 0x58, 0xae, 0xaf, 0x09,
+	0x81, 0x11, 0x2a, 0x7a, 0x0e, 0x1f, 0x23, 0x6f, 0x3b, 0x8d, 0xcd, 0x37, 0x89, 0x60, 0x96, 0x09,
+	0x56, 0x37, 0x28, 0x65, 0x29, 0x78, 0x38, 0x8e, 0xbc, 0x6d, 0x10, 0xbb, 0x10, 0xf9, 0x08, 0x2f,
+	0x9c, 0x32, 0x29, 0x1a, 0xca, 0xd0, 0xae, 0x37, 0x31, 0xeb, 0xad, 0x1d, 0xf6, 0xb3, 0x26, 0xcd,
+	0x8e, 0x4e, 0x17, 0xe6, 0x4e, 0x93, 0x0c, 0xa7, 0x91, 0xef, 0x76, 0x61, 0x3e, 0x34, 0x49, 0xf2,
+	0x0e, 0x56, 0x57, 0xee, 0xf4, 0x99, 0x31, 0x60, 0xdf, 0xd5, 0x25, 0xb4, 0x7a, 0xf3, 0xc7, 0xeb,
+	0x43, 0x31, 0x29, 0xef, 0xe1, 0xf9, 0x3d, 0x65, 0x7b, 0x09, 0x49, 0xc9, 0x0b, 0xd1, 0xa5, 0xfd,
+	0x6c, 0x20, 0x9d, 0x20, 0xdf, 0xc0, 0xb2, 0x83, 0xf5, 0xaf, 0xa9, 0xb6, 0xb6, 0xe1, 0x04, 0xf1,
+	0x93, 0x3b, 0xfc, 0xa3, 0xad, 0xd1, 0x31, 0xd7, 0xc2, 0xf4, 0x22, 0xb2, 0xea, 0x1e, 0x51, 0x30,
+	0x98, 0x97, 0x82, 0x1f, 0x34, 0x67, 0x9e, 0xe0, 0xd3, 0x7f, 0x91, 0x32, 0xec, 0xf2, 0x9a, 0xed,
+	0xd7, 0xbb, 0xfe, 0x72, 0x9d, 0x3b, 0xfd, 0x27, 0x68, 0x0d, 0x1c, 0x46, 0x3f, 0x1f, 0xea, 0x34,
+	0x7d, 0x34, 0xd7, 0xfd, 0xe1, 0xef, 0x00, 0xae, 0x90, 0xea, 0x28, 0xee, 0x02, 0x00, 0x00,
 }
@@ -48,7 +48,7 @@ type Client interface {
 
 // PieceStore -- Struct Info needed for protobuf api calls
 type PieceStore struct {
-	closeFunc        func() error              // function that closes the transport connection
+	closeFunc        func() error              // function that releases the transport connection
 	client           pb.PieceStoreRoutesClient // PieceStore for interacting with Storage Node
 	prikey           crypto.PrivateKey         // Uplink private key
 	bandwidthMsgSize int                       // max bandwidth message size in bytes
@@ -57,11 +57,6 @@ type PieceStore struct {
 
 // NewPSClient initilizes a piecestore client
 func NewPSClient(ctx context.Context, tc transport.Client, n *pb.Node, bandwidthMsgSize int) (Client, error) {
-	conn, err := tc.DialNode(ctx, n)
-	if err != nil {
-		return nil, err
-	}
-
 	if bandwidthMsgSize < 0 || bandwidthMsgSize > *maxBandwidthMsgSize {
 		return nil, ClientError.New("invalid Bandwidth Message Size: %v", bandwidthMsgSize)
 	}
@@ -70,8 +65,14 @@ func NewPSClient(ctx context.Context, tc transport.Client, n *pb.Node, bandwidth
 		bandwidthMsgSize = *defaultBandwidthMsgSize
 	}
 
+	// the connection is shared with the other clients of the node
+	conn, release, err := tc.DialShared(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+
 	return &PieceStore{
-		closeFunc:        conn.Close,
+		closeFunc:        func() error { release(); return nil },
 		client:           pb.NewPieceStoreRoutesClient(conn),
 		bandwidthMsgSize: bandwidthMsgSize,
 		prikey:           tc.Identity().Key,
@@ -14,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sync/atomic"
 	"time"
 
 	"github.com/gtank/cryptopasta"
@@ -30,6 +31,7 @@ import (
 	pstore "storj.io/storj/pkg/piecestore"
 	as "storj.io/storj/pkg/piecestore/psserver/agreementsender"
 	"storj.io/storj/pkg/piecestore/psserver/psdb"
+	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
 )
 
@@ -48,7 +50,7 @@ type Config struct {
 }
 
 // Run implements provider.Responsibility
-func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+func (c *Config) Run(ctx context.Context, server *provider.Provider) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -58,7 +60,7 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 		return ServerError.Wrap(err)
 	}
 
-	s, err := NewEndpoint(zap.L(), c, db, server.Identity().Key)
+	s, err := NewEndpoint(zap.L(), *c, db, server.Identity().Key)
 	if err != nil {
 		return err
 	}
@@ -80,6 +82,15 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 		log.Fatal(s.Stop(ctx))
 	}()
 
+	defer process.OnReload(func(ctx context.Context) error {
+		allocatedDiskSpace, allocatedBandwidth, err := allocation(s.log, *c, s.DB)
+		if err != nil {
+			return err
+		}
+		s.SetAllocation(allocatedDiskSpace, allocatedBandwidth)
+		return nil
+	})()
+
 	s.log.Info("Started Node", zap.String("ID", fmt.Sprint(server.Identity().ID)))
 	return server.Run(ctx)
 }
@@ -115,17 +126,35 @@ type Server struct {
 
 // NewEndpoint -- initializes a new endpoint for a piecestore server
 func NewEndpoint(log *zap.Logger, config Config, db *psdb.DB, pkey crypto.PrivateKey) (*Server, error) {
+	allocatedDiskSpace, allocatedBandwidth, err := allocation(log, config, db)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		log:              log,
+		DataDir:          filepath.Join(config.Path, "piece-store-data"),
+		DB:               db,
+		pkey:             pkey,
+		totalAllocated:   allocatedDiskSpace,
+		totalBwAllocated: allocatedBandwidth,
+		verifier:         auth.NewSignedMessageVerifier(),
+	}, nil
+}
 
+// allocation returns the disk space and bandwidth to allocate from the config,
+// within the limits of the disk
+func allocation(log *zap.Logger, config Config, db *psdb.DB) (allocatedDiskSpace, allocatedBandwidth int64, err error) {
 	// read the allocated disk space from the config file
-	allocatedDiskSpace := config.AllocatedDiskSpace
-	allocatedBandwidth := config.AllocatedBandwidth
+	allocatedDiskSpace = config.AllocatedDiskSpace
+	allocatedBandwidth = config.AllocatedBandwidth
 
 	// get the disk space details
 	// The returned path ends in a slash only if it represents a root directory, such as "/" on Unix or `C:\` on Windows.
 	rootPath := filepath.Dir(filepath.Clean(config.Path))
 	diskSpace, err := disk.Usage(rootPath)
 	if err != nil {
-		return nil, ServerError.Wrap(err)
+		return 0, 0, ServerError.Wrap(err)
 	}
 	freeDiskSpace := int64(diskSpace.Free)
 
@@ -138,7 +167,7 @@ func NewEndpoint(log *zap.Logger, config Config, db *psdb.DB, pkey crypto.Privat
 
 	usedBandwidth, err := db.GetTotalBandwidthBetween(getBeginningOfMonth(), time.Now())
 	if err != nil {
-		return nil, ServerError.Wrap(err)
+		return 0, 0, ServerError.Wrap(err)
 	}
 
 	if usedBandwidth > allocatedBandwidth {
@@ -168,15 +197,7 @@ func NewEndpoint(log *zap.Logger, config Config, db *psdb.DB, pkey crypto.Privat
 		log.Warn("Disk space is less than requested. Allocating space", zap.Int64("bytes", allocatedDiskSpace))
 	}
 
-	return &Server{
-		log:              log,
-		DataDir:          filepath.Join(config.Path, "piece-store-data"),
-		DB:               db,
-		pkey:             pkey,
-		totalAllocated:   allocatedDiskSpace,
-		totalBwAllocated: allocatedBandwidth,
-		verifier:         auth.NewSignedMessageVerifier(),
-	}, nil
+	return allocatedDiskSpace, allocatedBandwidth, nil
 }
 
 // New creates a Server with custom db
@@ -192,6 +213,19 @@ func New(log *zap.Logger, dataDir string, db *psdb.DB, config Config, pkey crypt
 	}
 }
 
+// SetAllocation changes the disk space and bandwidth allocated to the server
+func (s *Server) SetAllocation(allocatedDiskSpace, allocatedBandwidth int64) {
+	atomic.StoreInt64(&s.totalAllocated, allocatedDiskSpace)
+	atomic.StoreInt64(&s.totalBwAllocated, allocatedBandwidth)
+	s.log.Info("Allocation applied", zap.Int64("disk space", allocatedDiskSpace),
+		zap.Int64("bandwidth", allocatedBandwidth))
+}
+
+// allocated returns the disk space and bandwidth allocated to the server
+func (s *Server) allocated() (allocatedDiskSpace, allocatedBandwidth int64) {
+	return atomic.LoadInt64(&s.totalAllocated), atomic.LoadInt64(&s.totalBwAllocated)
+}
+
 // Stop the piececstore node
 func (s *Server) Stop(ctx context.Context) (err error) {
 	return s.DB.Close()
@@ -254,7 +288,9 @@ func (s *Server) Stats(ctx context.Context, in *pb.StatsReq) (*pb.StatSummary, e
 		return nil, err
 	}
 
-	return &pb.StatSummary{UsedSpace: totalUsed, AvailableSpace: (s.totalAllocated - totalUsed), UsedBandwidth: totalUsedBandwidth, AvailableBandwidth: (s.totalBwAllocated - totalUsedBandwidth)}, nil
+	totalAllocated, totalBwAllocated := s.allocated()
+
+	return &pb.StatSummary{UsedSpace: totalUsed, AvailableSpace: (totalAllocated - totalUsed), UsedBandwidth: totalUsedBandwidth, AvailableBandwidth: (totalBwAllocated - totalUsedBandwidth)}, nil
 }
 
 // Delete -- Delete data by Id from piecestore
@@ -102,8 +102,9 @@ func (s *Server) storeData(ctx context.Context, stream pb.PieceStoreRoutes_Store
 	if err != nil {
 		return 0, err
 	}
-	bwLeft := s.totalBwAllocated - bwUsed
-	spaceLeft := s.totalAllocated - spaceUsed
+	totalAllocated, totalBwAllocated := s.allocated()
+	bwLeft := totalBwAllocated - bwUsed
+	spaceLeft := totalAllocated - spaceUsed
 	reader := NewStreamReader(s, stream, bwLeft, spaceLeft)
 
 	total, err = io.Copy(storeFile, reader)
@@ -23,8 +23,10 @@ type CtxKeyPointerdb int
 
 const (
 	// BoltPointerBucket is the string representing the bucket used for `PointerEntries` in BoltDB
-	BoltPointerBucket                 = "pointers"
-	ctxKey            CtxKeyPointerdb = iota
+	BoltPointerBucket = "pointers"
+	// BoltExpirationBucket is the string representing the bucket used for the expiration index in BoltDB
+	BoltExpirationBucket                 = "expirations"
+	ctxKey               CtxKeyPointerdb = iota
 )
 
 // Config is a configuration struct that is everything you need to start a
@@ -36,32 +38,47 @@ type Config struct {
 	Overlay              bool   `default:"true" help:"toggle flag if overlay is enabled"`
 }
 
-func newKeyValueStore(dbURLString string) (db storage.KeyValueStore, err error) {
+// newKeyValueStores opens the store for the pointers and the store for the
+// expiration index
+func newKeyValueStores(dbURLString string) (db, expirations storage.KeyValueStore, err error) {
 	driver, source, err := utils.SplitDBURL(dbURLString)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if driver == "bolt" {
-		db, err = boltdb.New(source, BoltPointerBucket)
+		var clients []*boltdb.Client
+		clients, err = boltdb.NewShared(source, BoltPointerBucket, BoltExpirationBucket)
+		if err != nil {
+			return nil, nil, err
+		}
+		return clients[0], clients[1], nil
 	} else if driver == "postgresql" || driver == "postgres" {
 		db, err = postgreskv.New(source)
-	} else {
-		err = Error.New("unsupported db scheme: %s", driver)
+		if err != nil {
+			return nil, nil, err
+		}
+		expirations, err = postgreskv.NewBucket(source, BoltExpirationBucket)
+		if err != nil {
+			return nil, nil, utils.CombineErrors(err, db.Close())
+		}
+		return db, expirations, nil
 	}
-	return db, err
+	return nil, nil, Error.New("unsupported db scheme: %s", driver)
 }
 
 // Run implements the provider.Responsibility interface
 func (c Config) Run(ctx context.Context, server *provider.Provider) error {
-	db, err := newKeyValueStore(c.DatabaseURL)
+	db, expirations, err := newKeyValueStores(c.DatabaseURL)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = db.Close() }()
+	defer func() { _ = expirations.Close() }()
 
 	cache := overlay.LoadFromContext(ctx)
 	dblogged := storelogger.New(zap.L().Named("pdb"), db)
 	s := NewServer(dblogged, cache, zap.L(), c, server.Identity())
+	s.SetExpirationIndex(NewExpirationIndex(expirations))
 	pb.RegisterPointerDBServer(server.GRPC(), s)
 	// add the server to the context
 	ctx = context.WithValue(ctx, ctxKey, s)
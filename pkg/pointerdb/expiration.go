@@ -0,0 +1,118 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage"
+)
+
+// ExpiredPointer is an entry of the expiration index
+type ExpiredPointer struct {
+	Path       string
+	Expiration time.Time
+}
+
+// ExpirationIndex keeps the paths of expiring pointers ordered by their
+// expiration time, so expired pointers can be found without a full scan
+type ExpirationIndex struct {
+	db storage.KeyValueStore
+}
+
+// NewExpirationIndex creates an expiration index backed by the given store
+func NewExpirationIndex(db storage.KeyValueStore) *ExpirationIndex {
+	return &ExpirationIndex{db: db}
+}
+
+// CheckExpiration returns an error if the expiration can't be indexed. The
+// index keys only sort in expiration order for times after the Unix epoch.
+func CheckExpiration(expiration time.Time) error {
+	if expiration.Unix() < 0 {
+		return Error.New("expiration %v before the Unix epoch", expiration)
+	}
+	return nil
+}
+
+// expirationKey returns the index key for the given path and expiration.
+// The zero padded unix time makes the keys sort in expiration order.
+func expirationKey(path string, expiration time.Time) storage.Key {
+	return storage.Key(fmt.Sprintf("%020d/%s", expiration.Unix(), path))
+}
+
+// parseExpirationKey is the inverse of expirationKey
+func parseExpirationKey(key storage.Key) (ExpiredPointer, error) {
+	parts := strings.SplitN(key.String(), "/", 2)
+	if len(parts) != 2 {
+		return ExpiredPointer{}, Error.New("invalid expiration index key %q", key)
+	}
+	unix, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return ExpiredPointer{}, Error.Wrap(err)
+	}
+	return ExpiredPointer{
+		Path:       parts[1],
+		Expiration: time.Unix(unix, 0).UTC(),
+	}, nil
+}
+
+// Add records that the pointer at path expires at the given time
+func (index *ExpirationIndex) Add(path string, expiration time.Time) error {
+	if err := CheckExpiration(expiration); err != nil {
+		return err
+	}
+	return Error.Wrap(index.db.Put(expirationKey(path, expiration), storage.Value{}))
+}
+
+// Remove deletes the index entry for the pointer at path
+func (index *ExpirationIndex) Remove(path string, expiration time.Time) error {
+	err := index.db.Delete(expirationKey(path, expiration))
+	if storage.ErrKeyNotFound.Has(err) {
+		return nil
+	}
+	return Error.Wrap(err)
+}
+
+// Expired returns up to limit entries which expired before now, oldest first
+func (index *ExpirationIndex) Expired(now time.Time, limit int) (expired []ExpiredPointer, err error) {
+	if limit <= 0 || limit > storage.LookupLimit {
+		limit = storage.LookupLimit
+	}
+
+	err = index.db.Iterate(storage.IterateOptions{Recurse: true},
+		func(it storage.Iterator) error {
+			var item storage.ListItem
+			for len(expired) < limit && it.Next(&item) {
+				entry, err := parseExpirationKey(item.Key)
+				if err != nil {
+					return err
+				}
+				if !entry.Expiration.Before(now) {
+					return nil
+				}
+				expired = append(expired, entry)
+			}
+			return nil
+		},
+	)
+	return expired, err
+}
+
+// IsExpired returns whether the expiration date of the pointer is before now
+func IsExpired(pointer *pb.Pointer, now time.Time) bool {
+	if pointer.GetExpirationDate() == nil {
+		return false
+	}
+	expiration, err := ptypes.Timestamp(pointer.GetExpirationDate())
+	if err != nil {
+		return false
+	}
+	return expiration.Before(now)
+}
@@ -31,11 +31,12 @@ var (
 
 // Server implements the network state RPC service
 type Server struct {
-	DB       storage.KeyValueStore
-	logger   *zap.Logger
-	config   Config
-	cache    *overlay.Cache
-	identity *provider.FullIdentity
+	DB          storage.KeyValueStore
+	logger      *zap.Logger
+	config      Config
+	cache       *overlay.Cache
+	identity    *provider.FullIdentity
+	expirations *ExpirationIndex
 }
 
 // NewServer creates instance of Server
@@ -49,6 +50,16 @@ func NewServer(db storage.KeyValueStore, cache *overlay.Cache, logger *zap.Logge
 	}
 }
 
+// SetExpirationIndex makes the server record expiring pointers in the index
+func (s *Server) SetExpirationIndex(index *ExpirationIndex) {
+	s.expirations = index
+}
+
+// ExpirationIndex returns the index of expiring pointers, or nil
+func (s *Server) ExpirationIndex() *ExpirationIndex {
+	return s.expirations
+}
+
 func (s *Server) validateAuth(ctx context.Context) error {
 	APIKey, ok := auth.GetAPIKey(ctx)
 	if !ok || !pointerdbAuth.ValidateAPIKey(string(APIKey)) {
@@ -83,13 +94,27 @@ func (s *Server) Put(ctx context.Context, req *pb.PutRequest) (resp *pb.PutRespo
 
 	err = s.validateSegment(req)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
 	if err = s.validateAuth(ctx); err != nil {
 		return nil, err
 	}
 
+	var expiration time.Time
+	indexed := s.expirations != nil && req.GetPointer().GetExpirationDate() != nil
+	if indexed {
+		expiration, err = ptypes.Timestamp(req.GetPointer().GetExpirationDate())
+		if err == nil && !expiration.IsZero() {
+			err = CheckExpiration(expiration)
+		}
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		// the zero time means the pointer never expires
+		indexed = !expiration.IsZero()
+	}
+
 	// Update the pointer with the creation date
 	req.GetPointer().CreationDate = ptypes.TimestampNow()
 
@@ -99,6 +124,16 @@ func (s *Server) Put(ctx context.Context, req *pb.PutRequest) (resp *pb.PutRespo
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
+	// the previous pointer is restored if the expiration can't be indexed
+	var previous storage.Value
+	if indexed {
+		previous, err = s.DB.Get([]byte(req.GetPath()))
+		if err != nil && !storage.ErrKeyNotFound.Has(err) {
+			s.logger.Error("err getting pointer", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+	}
+
 	// TODO(kaloyan): make sure that we know we are overwriting the pointer!
 	// In such case we should delete the pieces of the old segment if it was
 	// a remote one.
@@ -107,9 +142,32 @@ func (s *Server) Put(ctx context.Context, req *pb.PutRequest) (resp *pb.PutRespo
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
+	if indexed {
+		if err = s.expirations.Add(req.GetPath(), expiration); err != nil {
+			s.logger.Error("err indexing pointer expiration", zap.Error(err))
+			if rollbackErr := s.rollback(req.GetPath(), previous); rollbackErr != nil {
+				s.logger.Error("err rolling back pointer", zap.Error(rollbackErr))
+			}
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+	}
+
 	return &pb.PutResponse{}, nil
 }
 
+// rollback restores the previous pointer at path, or deletes the pointer if
+// there was none
+func (s *Server) rollback(path string, previous storage.Value) error {
+	if previous == nil {
+		err := s.DB.Delete([]byte(path))
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil
+		}
+		return err
+	}
+	return s.DB.Put([]byte(path), previous)
+}
+
 // Get formats and hands off a file path to get from boltdb
 func (s *Server) Get(ctx context.Context, req *pb.GetRequest) (resp *pb.GetResponse, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -10,11 +10,14 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
@@ -65,6 +68,66 @@ func TestServicePut(t *testing.T) {
 	}
 }
 
+func TestServicePutExpiration(t *testing.T) {
+	ctx := auth.WithAPIKey(context.Background(), nil)
+	path := "a/b/c"
+
+	db := teststore.New()
+	expirations := teststore.New()
+	s := Server{DB: db, logger: zap.NewNop()}
+	s.SetExpirationIndex(NewExpirationIndex(expirations))
+
+	put := func(expiration *timestamp.Timestamp) error {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: &pb.Pointer{ExpirationDate: expiration}})
+		return err
+	}
+	stored := func() *pb.Pointer {
+		value, err := db.Get(storage.Key(path))
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil
+		}
+		require.NoError(t, err)
+		pointer := &pb.Pointer{}
+		require.NoError(t, proto.Unmarshal(value, pointer))
+		return pointer
+	}
+
+	// the invalid expirations are rejected before the pointer is stored
+	for _, expiration := range []*timestamp.Timestamp{
+		{Seconds: 1, Nanos: -1},
+		{Seconds: -1},
+	} {
+		err := put(expiration)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), expiration.String())
+		assert.Nil(t, stored())
+	}
+
+	// a new pointer is deleted if its expiration can't be indexed
+	expirations.ForceError++
+	assert.Equal(t, codes.Internal, status.Code(put(&timestamp.Timestamp{Seconds: 100})))
+	assert.Nil(t, stored())
+
+	require.NoError(t, put(&timestamp.Timestamp{Seconds: 100}))
+	expired, err := s.ExpirationIndex().Expired(time.Unix(200, 0), 0)
+	require.NoError(t, err)
+	assert.Equal(t, []ExpiredPointer{{Path: path, Expiration: time.Unix(100, 0).UTC()}}, expired)
+
+	// an overwritten pointer is restored if the expiration can't be indexed
+	expirations.ForceError++
+	assert.Equal(t, codes.Internal, status.Code(put(&timestamp.Timestamp{Seconds: 300})))
+	if pointer := stored(); assert.NotNil(t, pointer) {
+		assert.Equal(t, int64(100), pointer.GetExpirationDate().GetSeconds())
+	}
+
+	// the zero time is stored without an index entry
+	never, err := ptypes.TimestampProto(time.Time{})
+	require.NoError(t, err)
+	require.NoError(t, put(never))
+	expired, err = s.ExpirationIndex().Expired(time.Now(), 0)
+	require.NoError(t, err)
+	assert.Len(t, expired, 1)
+}
+
 func TestServiceGet(t *testing.T) {
 	ctx := context.Background()
 	ca, err := testidentity.NewTestCA(ctx)
@@ -0,0 +1,245 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"storj.io/storj/pkg/cfgstruct"
+)
+
+// The sources of the configuration values, from the lowest to the highest
+// precedence
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// loadedConfig describes where the configuration of a command came from
+type loadedConfig struct {
+	// file is the config file read, if any
+	file string
+	// sources are the sources of the flag values
+	sources map[string]string
+	// brokenKeys are the keys of the config file that aren't flags
+	brokenKeys []string
+	// brokenVals are the flags that failed to be set
+	brokenVals []string
+}
+
+// commandLine returns the names of the flags of cmd set on the command line.
+// It has to be called before the configuration is loaded.
+func commandLine(cmd *cobra.Command) map[string]bool {
+	cmdline := make(map[string]bool)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		cmdline[f.Name] = true
+	})
+	return cmdline
+}
+
+// loadConfig sets the flags of cmd that aren't in cmdline, the flags set on
+// the command line, from the environment variables named by
+// cfgstruct.EnvName, or else from the config.yaml file in the directory of the
+// config-dir flag, or else to their defaults. The precedence is defaults <
+// config file < environment variables < flags. loadConfig can be called again
+// to reload the configuration, and only sets the flags whose value changed.
+func loadConfig(cmd *cobra.Command, cmdline map[string]bool) (config loadedConfig, err error) {
+	flags := cmd.Flags()
+	config.sources = make(map[string]string)
+
+	// values are the values of the flags not set on the command line
+	values := make(map[string]string)
+	flags.VisitAll(func(f *pflag.Flag) {
+		if cmdline[f.Name] {
+			config.sources[f.Name] = SourceFlag
+			return
+		}
+		config.sources[f.Name] = SourceDefault
+		values[f.Name] = f.DefValue
+	})
+
+	lookupEnv := func(name string) {
+		if config.sources[name] == SourceFlag {
+			return
+		}
+		if value, ok := os.LookupEnv(cfgstruct.EnvName(name)); ok {
+			values[name] = value
+			config.sources[name] = SourceEnv
+		}
+	}
+
+	set := func(name string) {
+		value, ok := values[name]
+		if !ok {
+			return
+		}
+		f := flags.Lookup(name)
+		if f.Value.String() == value {
+			return
+		}
+		if config.sources[name] == SourceDefault && isList(f) {
+			// setting a list appends to it, so a list can't be reset to
+			// its default
+			return
+		}
+		if err := flags.Set(name, value); err != nil {
+			// flag couldn't be set
+			config.brokenVals = append(config.brokenVals, name)
+			config.sources[name] = SourceDefault
+		}
+	}
+
+	// the config directory itself may come from the environment
+	cfgFlag := flags.Lookup("config-dir")
+	if cfgFlag != nil {
+		lookupEnv(cfgFlag.Name)
+		set(cfgFlag.Name)
+	}
+
+	if cfgFlag != nil && cfgFlag.Value.String() != "" {
+		path := filepath.Join(os.ExpandEnv(cfgFlag.Value.String()), "config.yaml")
+		if cmd.Annotations["type"] != "setup" || fileExists(path) {
+			vip := viper.New()
+			vip.SetConfigFile(path)
+			err = vip.ReadInConfig()
+			if err != nil {
+				return config, err
+			}
+			config.file = vip.ConfigFileUsed()
+
+			for _, key := range vip.AllKeys() {
+				if flags.Lookup(key) == nil {
+					// flag couldn't be found
+					config.brokenKeys = append(config.brokenKeys, key)
+					continue
+				}
+				if config.sources[key] != SourceDefault {
+					continue
+				}
+				values[key] = vip.GetString(key)
+				config.sources[key] = SourceFile
+			}
+		}
+	}
+
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f != cfgFlag {
+			lookupEnv(f.Name)
+			set(f.Name)
+		}
+	})
+
+	return config, nil
+}
+
+// isList returns whether f is a slice or array flag
+func isList(f *pflag.Flag) bool {
+	kind := f.Value.Type()
+	return strings.HasSuffix(kind, "Slice") || strings.HasSuffix(kind, "Array")
+}
+
+// configCmd returns the config command of root, which prints the effective
+// configuration of its subcommands
+func configCmd(root *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "dump [command] [flags]",
+		Short: "Print the effective configuration of a command and the source of each value",
+		Long: "Print the effective configuration of a command, run by default, and the source " +
+			"of each value. The values come from the defaults, the config file, the " +
+			cfgstruct.EnvPrefix + "* environment variables and the flags, in increasing precedence. " +
+			"The values of the keys and secrets are redacted.",
+		// the flags are the flags of the dumped command
+		DisableFlagParsing: true,
+		SilenceUsage:       true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return dumpConfig(root, args)
+		},
+	})
+	return cmd
+}
+
+// dumpConfig prints the configuration of the subcommand of root selected by
+// args. The values of the keys and secrets are redacted.
+func dumpConfig(root *cobra.Command, args []string) error {
+	target, rest, err := root.Find(args)
+	if err != nil {
+		return err
+	}
+	if target == root {
+		if run, _, err := root.Find([]string{"run"}); err == nil && run != root {
+			target = run
+		}
+	}
+
+	err = target.ParseFlags(rest)
+	if err != nil {
+		return err
+	}
+
+	config, err := loadConfig(target, commandLine(target))
+	if err != nil {
+		return err
+	}
+
+	if config.file != "" {
+		fmt.Printf("# config file %s\n", config.file)
+	}
+	for _, key := range config.brokenKeys {
+		fmt.Printf("# invalid config file key %s\n", key)
+	}
+	for _, key := range config.brokenVals {
+		fmt.Printf("# invalid value for key %s\n", key)
+	}
+
+	names := make([]string, 0, len(config.sources))
+	for name := range config.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		source := config.sources[name]
+		if source == SourceEnv {
+			source += " " + cfgstruct.EnvName(name)
+		}
+		_, _ = fmt.Fprintf(w, "%s: %q\t# %s\n", name, dumpValue(target.Flags().Lookup(name)), source)
+	}
+	return w.Flush()
+}
+
+// secretFlag matches the names of the flags holding keys, secrets and
+// passwords, like enc.key, minio.secret-key or pointer-db.auth.api-key
+var secretFlag = regexp.MustCompile(`(^|[.-])(key|keys|secret|password)$`)
+
+// dumpValue returns the value of the flag to print, with the secrets redacted.
+// The empty secrets are printed to show they aren't set.
+func dumpValue(flag *pflag.Flag) string {
+	return redactValue(flag.Name, flag.Value.String())
+}
+
+// redactValue returns value to print for the flag name, redacted if the flag
+// is a secret
+func redactValue(name, value string) string {
+	if value != "" && secretFlag.MatchString(name) {
+		return "<redacted>"
+	}
+	return value
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+)
+
+func TestLoadConfig(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dir := ctx.Dir("config")
+	err := ioutil.WriteFile(filepath.Join(dir, "config.yaml"), []byte(
+		"server.address: file\n"+
+			"server.timeout: file\n"+
+			"server.port: file\n"+
+			"unknown.key: file\n"), 0644)
+	require.NoError(t, err)
+
+	for name, value := range map[string]string{
+		"STORJ_SERVER_TIMEOUT": "env",
+		"STORJ_SERVER_PORT":    "env",
+		"STORJ_LOG_NAME":       "env",
+	} {
+		require.NoError(t, os.Setenv(name, value))
+		defer func(name string) { _ = os.Unsetenv(name) }(name)
+	}
+
+	cmd := &cobra.Command{Use: "run"}
+	flags := cmd.Flags()
+	flags.String("config-dir", "", "")
+	address := flags.String("server.address", "default", "")
+	timeout := flags.String("server.timeout", "default", "")
+	port := flags.String("server.port", "default", "")
+	logName := flags.String("log-name", "default", "")
+	other := flags.String("other", "default", "")
+
+	require.NoError(t, cmd.ParseFlags([]string{"--config-dir", dir, "--server.port", "flag"}))
+
+	config, err := loadConfig(cmd, commandLine(cmd))
+	require.NoError(t, err)
+
+	assert.Equal(t, "file", *address)
+	assert.Equal(t, "env", *timeout)
+	assert.Equal(t, "flag", *port)
+	assert.Equal(t, "env", *logName)
+	assert.Equal(t, "default", *other)
+
+	assert.Equal(t, map[string]string{
+		"config-dir":     SourceFlag,
+		"server.address": SourceFile,
+		"server.timeout": SourceEnv,
+		"server.port":    SourceFlag,
+		"log-name":       SourceEnv,
+		"other":          SourceDefault,
+	}, config.sources)
+	assert.Equal(t, []string{"unknown.key"}, config.brokenKeys)
+	assert.Empty(t, config.brokenVals)
+	assert.Equal(t, filepath.Join(dir, "config.yaml"), config.file)
+}
+
+func TestLoadConfigDirFromEnv(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dir := ctx.Dir("config")
+	err := ioutil.WriteFile(filepath.Join(dir, "config.yaml"), []byte("value: file\n"), 0644)
+	require.NoError(t, err)
+
+	require.NoError(t, os.Setenv("STORJ_CONFIG_DIR", dir))
+	defer func() { _ = os.Unsetenv("STORJ_CONFIG_DIR") }()
+
+	cmd := &cobra.Command{Use: "run"}
+	cmd.Flags().String("config-dir", "", "")
+	value := cmd.Flags().String("value", "default", "")
+
+	config, err := loadConfig(cmd, commandLine(cmd))
+	require.NoError(t, err)
+
+	assert.Equal(t, "file", *value)
+	assert.Equal(t, SourceEnv, config.sources["config-dir"])
+}
+
+func TestDumpValue(t *testing.T) {
+	flags := pflag.NewFlagSet("dump", pflag.ContinueOnError)
+	for i, tt := range []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"enc.key", "secret", "<redacted>"},
+		{"enc.bucket-keys", "a=b", "<redacted>"},
+		{"minio.secret-key", "secret", "<redacted>"},
+		{"pointer-db.auth.api-key", "secret", "<redacted>"},
+		{"satellite.repairer.api-key", "secret", "<redacted>"},
+		{"db.password", "secret", "<redacted>"},
+		// the empty secrets are printed
+		{"client.api-key", "", ""},
+		{"identity.key-path", "identity.key", "identity.key"},
+		{"server.address", "localhost:7777", "localhost:7777"},
+		{"monkey", "banana", "banana"},
+	} {
+		flags.String(tt.name, tt.value, "")
+		assert.Equal(t, tt.expected, dumpValue(flags.Lookup(tt.name)), fmt.Sprintf("Test case #%d", i))
+	}
+}
@@ -9,10 +9,14 @@ import (
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"sync/atomic"
 
+	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 	"gopkg.in/spacemonkeygo/monkit.v2/present"
+
+	"storj.io/storj/pkg/storj"
 )
 
 var (
@@ -26,7 +30,17 @@ func init() {
 	*http.DefaultServeMux = http.ServeMux{}
 }
 
-func initDebug(logger *zap.Logger, r *monkit.Registry) (
+// SetNodeID labels the metrics served by the debug server of the running
+// command with the node ID
+func SetNodeID(cmd *cobra.Command, id storj.NodeID) {
+	contextMtx.Lock()
+	defer contextMtx.Unlock()
+	if nodeID := nodeIDs[cmd]; nodeID != nil {
+		nodeID.Store(id.String())
+	}
+}
+
+func initDebug(logger *zap.Logger, r *monkit.Registry, nodeID *atomic.Value) (
 	err error) {
 	var mux http.ServeMux
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
@@ -35,6 +49,7 @@ func initDebug(logger *zap.Logger, r *monkit.Registry) (
 	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	mux.Handle("/mon/", http.StripPrefix("/mon", present.HTTP(r)))
+	mux.Handle("/metrics", prometheusHandler(r, *metricApp+*metricAppSuffix, nodeID))
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = fmt.Fprintln(w, "OK")
 	})
@@ -9,9 +9,8 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"path/filepath"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 
 	"github.com/spf13/cobra"
@@ -29,8 +28,11 @@ func ExecuteWithConfig(cmd *cobra.Command, defaultConfig string) {
 	Exec(cmd)
 }
 
-// Exec runs a Cobra command. If a "config" flag is defined it will be parsed
-// and loaded using viper.
+// Exec runs a Cobra command. The flags that aren't set on the command line
+// are set from the environment variables named by cfgstruct.EnvName, or else
+// from the config.yaml file in the directory of the "config-dir" flag, so the
+// precedence is defaults < config file < environment variables < flags. Exec
+// adds a "config dump" command printing the effective configuration.
 func Exec(cmd *cobra.Command) {
 	exe, err := os.Executable()
 	if err == nil {
@@ -39,6 +41,7 @@ func Exec(cmd *cobra.Command) {
 
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	cleanup(cmd)
+	cmd.AddCommand(configCmd(cmd))
 	_ = cmd.Execute()
 }
 
@@ -47,6 +50,7 @@ var (
 
 	contextMtx sync.Mutex
 	contexts   = map[*cobra.Command]context.Context{}
+	nodeIDs    = map[*cobra.Command]*atomic.Value{}
 )
 
 // SaveConfig will save all flags with default values to outfilewith specific
@@ -113,60 +117,30 @@ func cleanup(cmd *cobra.Command) {
 		ctx := context.Background()
 		defer mon.TaskNamed("root")(&ctx)(&err)
 
-		vip := viper.New()
-		err = vip.BindPFlags(cmd.Flags())
+		logger, err := newLogger()
 		if err != nil {
 			return err
 		}
-		vip.SetEnvPrefix("storj")
-		vip.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
-		vip.AutomaticEnv()
-
-		logger, err := newLogger()
-
-		cfgFlag := cmd.Flags().Lookup("config-dir")
-		if cfgFlag != nil && cfgFlag.Value.String() != "" {
-			path := filepath.Join(os.ExpandEnv(cfgFlag.Value.String()), "config.yaml")
-			if cmd.Annotations["type"] != "setup" || fileExists(path) {
-				vip.SetConfigFile(path)
-				err = vip.ReadInConfig()
-				if err != nil {
-					return err
-				}
-
-				logger.Sugar().Debug("Configuration loaded from: ", vip.ConfigFileUsed())
-			}
-		}
-
-		// go back and propagate changed config values to appropriate flags
-		var brokenKeys []string
-		var brokenVals []string
-		for _, key := range vip.AllKeys() {
-			if cmd.Flags().Lookup(key) == nil {
-				// flag couldn't be found
-				brokenKeys = append(brokenKeys, key)
-			} else {
-				err := cmd.Flags().Set(key, vip.GetString(key))
-				if err != nil {
-					// flag couldn't be set
-					brokenVals = append(brokenVals, key)
-				}
-			}
-		}
 
+		cmdline := commandLine(cmd)
+		config, err := loadConfig(cmd, cmdline)
 		if err != nil {
 			return err
 		}
+		if config.file != "" {
+			logger.Sugar().Debug("Configuration loaded from: ", config.file)
+		}
+
 		defer func() { _ = logger.Sync() }()
 		defer zap.ReplaceGlobals(logger)()
 		defer zap.RedirectStdLog(logger)()
 
 		// okay now that logging is working, inform about the broken keys
-		for _, key := range brokenKeys {
+		for _, key := range config.brokenKeys {
 			logger.Sugar().Infof("Invalid configuration file key: %s", key)
 		}
-		for _, key := range brokenVals {
-			logger.Sugar().Infof("Invalid configuration file value for key: %s", key)
+		for _, key := range config.brokenVals {
+			logger.Sugar().Infof("Invalid configuration value for key: %s", key)
 		}
 
 		err = initMetrics(ctx, monkit.Default,
@@ -175,20 +149,25 @@ func cleanup(cmd *cobra.Command) {
 			logger.Error("failed to configure telemetry", zap.Error(err))
 		}
 
-		err = initDebug(logger, monkit.Default)
+		var nodeID atomic.Value
+		err = initDebug(logger, monkit.Default, &nodeID)
 		if err != nil {
 			logger.Error("failed to start debug endpoints", zap.Error(err))
 		}
 
 		contextMtx.Lock()
 		contexts[cmd] = ctx
+		nodeIDs[cmd] = &nodeID
 		contextMtx.Unlock()
 		defer func() {
 			contextMtx.Lock()
 			delete(contexts, cmd)
+			delete(nodeIDs, cmd)
 			contextMtx.Unlock()
 		}()
 
+		defer watchReload(ctx, logger, cmd, cmdline)()
+
 		err = internalRun(cmd, args)
 		if err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
@@ -0,0 +1,192 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+// quantiles maps the reservoir stats of the distributions to quantiles
+var quantiles = map[string]string{
+	"rmin": "0",
+	"r50":  "0.5",
+	"r90":  "0.9",
+	"rmax": "1",
+}
+
+// counters are the stats only ever increasing
+var counters = map[string]bool{
+	"total":     true,
+	"success":   true,
+	"successes": true,
+	"errors":    true,
+	"panics":    true,
+	"failures":  true,
+	"error":     true,
+}
+
+// sample is a value of a metric with its labels
+type sample struct {
+	name   string
+	labels []string
+	value  float64
+}
+
+// family is a metric with its type and samples
+type family struct {
+	typ     string
+	samples []sample
+}
+
+// prometheusHandler serves the stats of the registry in the Prometheus text
+// exposition format.
+//
+// A stat of a function is exposed as function_<stat> with the function name in
+// the name label. Any other stat is exposed as <series>_<stat>. All the
+// metrics are labeled with their scope, the application and the node ID
+// stored in nodeID. The distributions are exposed as summaries, with their
+// quantiles as a quantile label, the totals and the numbers of events as
+// counters and any other stat as a gauge.
+func prometheusHandler(r *monkit.Registry, app string, nodeID *atomic.Value) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		id, _ := nodeID.Load().(string)
+
+		metrics := make(map[string]*family)
+		add := func(name, typ, suffix string, s sample) {
+			f, ok := metrics[name]
+			if !ok {
+				f = &family{typ: typ}
+				metrics[name] = f
+			}
+			s.name = name + suffix
+			f.samples = append(f.samples, s)
+		}
+
+		r.Scopes(func(scope *monkit.Scope) {
+			var funcs []string
+			scope.Funcs(func(f *monkit.Func) {
+				funcs = append(funcs, f.ShortName())
+			})
+
+			scope.Stats(func(name string, value float64) {
+				labels := []string{"scope", scope.Name(), "app", app, "node_id", id}
+
+				var series, stat string
+				for _, f := range funcs {
+					if strings.HasPrefix(name, f+".") {
+						series, stat = "function", name[len(f)+1:]
+						labels = append(labels, "name", f)
+						break
+					}
+				}
+				if series == "" {
+					i := strings.Index(name, ".")
+					if i < 0 {
+						return
+					}
+					series, stat = name[:i], name[i+1:]
+				}
+
+				words := strings.Fields(stat)
+				if strings.HasPrefix(stat, "error ") {
+					// the errors of a function by name
+					words = words[:1]
+					labels = append(labels, "error", stat[len("error "):])
+				}
+				if len(words) == 0 {
+					return
+				}
+				last := words[len(words)-1]
+				parts := append([]string{series}, words[:len(words)-1]...)
+				metric := sample{labels: labels, value: value}
+
+				switch quantile, ok := quantiles[last]; {
+				case ok:
+					metric.labels = append(metric.labels, "quantile", quantile)
+					add(metricName(parts), "summary", "", metric)
+				case last == "sum" || last == "count":
+					// the sum and the number of values of a distribution
+					// belong to its summary
+					add(metricName(parts), "summary", "_"+last, metric)
+				case counters[last]:
+					add(metricName(append(parts, last)), "counter", "", metric)
+				default:
+					add(metricName(append(parts, last)), "gauge", "", metric)
+				}
+			})
+		})
+
+		names := make([]string, 0, len(metrics))
+		for name := range metrics {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		out := bufio.NewWriter(w)
+		for _, name := range names {
+			f := metrics[name]
+			_, _ = fmt.Fprintf(out, "# TYPE %s %s\n", name, f.typ)
+			for _, sample := range f.samples {
+				_, _ = fmt.Fprintf(out, "%s{%s} %s\n", sample.name, formatLabels(sample.labels), formatValue(sample.value))
+			}
+		}
+		_ = out.Flush()
+	})
+}
+
+// metricName joins the parts into a valid Prometheus metric name
+func metricName(parts []string) string {
+	var name []byte
+	for _, part := range parts {
+		for _, c := range []byte(strings.ToLower(part)) {
+			if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+				name = append(name, c)
+			} else if len(name) > 0 && name[len(name)-1] != '_' {
+				name = append(name, '_')
+			}
+		}
+		if len(name) > 0 && name[len(name)-1] != '_' {
+			name = append(name, '_')
+		}
+	}
+
+	result := strings.TrimSuffix(string(name), "_")
+	if result == "" || (result[0] >= '0' && result[0] <= '9') {
+		result = "_" + result
+	}
+	return result
+}
+
+// formatLabels formats the label name and value pairs
+func formatLabels(labels []string) string {
+	var pairs []string
+	for i := 0; i+1 < len(labels); i += 2 {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[i+1])
+		pairs = append(pairs, labels[i]+`="`+value+`"`)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// formatValue formats a sample value
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+func TestPrometheusHandler(t *testing.T) {
+	registry := monkit.NewRegistry()
+	scope := registry.ScopeNamed("storj.io/storj/pkg/test")
+
+	scope.Counter("piece count").Inc(3)
+	scope.Meter("bytes-sent").Mark(10)
+
+	task := scope.FuncNamed("(*Server).Upload")
+	for _, fail := range []bool{false, false, true} {
+		func() {
+			var err error
+			ctx := context.Background()
+			defer task.Task(&ctx)(&err)
+			if fail {
+				err = errors.New("failed")
+			}
+		}()
+	}
+
+	var nodeID atomic.Value
+	nodeID.Store("node-1")
+
+	server := httptest.NewServer(prometheusHandler(registry, "storagenode", &nodeID))
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL)
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	labels := `scope="storj.io/storj/pkg/test",app="storagenode",node_id="node-1"`
+	lines := strings.Split(string(body), "\n")
+
+	for _, expected := range []string{
+		`# TYPE piece_count_val gauge`,
+		`piece_count_val{` + labels + `} 3`,
+		`# TYPE bytes_sent_total counter`,
+		`bytes_sent_total{` + labels + `} 10`,
+		`# TYPE bytes_sent_rate gauge`,
+		`# TYPE function_successes counter`,
+		`function_successes{` + labels + `,name="(*Server).Upload"} 2`,
+		`function_failures{` + labels + `,name="(*Server).Upload"} 1`,
+		`function_error{` + labels + `,name="(*Server).Upload",error="System Error"} 1`,
+		`# TYPE function_error counter`,
+		`# TYPE function_current gauge`,
+		`# TYPE function_success_times summary`,
+		`function_success_times_count{` + labels + `,name="(*Server).Upload"} 2`,
+	} {
+		assert.Contains(t, lines, expected)
+	}
+
+	for _, quantile := range []string{"0", "0.5", "0.9", "1"} {
+		prefix := `function_success_times{` + labels + `,name="(*Server).Upload",quantile="` + quantile + `"} `
+		found := false
+		for _, line := range lines {
+			found = found || strings.HasPrefix(line, prefix)
+		}
+		assert.True(t, found, prefix)
+	}
+
+	// the sum of the durations belongs to the summary
+	sum := `function_success_times_sum{` + labels + `,name="(*Server).Upload"} `
+	found := false
+	for _, line := range lines {
+		found = found || strings.HasPrefix(line, sum)
+	}
+	assert.True(t, found, sum)
+
+	// the samples of a metric are grouped after its type
+	seen := make(map[string]bool)
+	current := ""
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "# TYPE ") {
+			current = strings.Fields(line)[2]
+			assert.False(t, seen[current], current)
+			seen[current] = true
+			continue
+		}
+		name := line[:strings.Index(line, "{")]
+		assert.Contains(t, []string{current, current + "_sum", current + "_count"}, name)
+	}
+}
+
+func TestMetricName(t *testing.T) {
+	for _, tt := range []struct {
+		parts    []string
+		expected string
+	}{
+		{[]string{"function", "success", "times"}, "function_success_times"},
+		{[]string{"Bytes-Sent", "total"}, "bytes_sent_total"},
+		{[]string{"piece count", "value"}, "piece_count_value"},
+		{[]string{"__a..b__"}, "a_b"},
+		{[]string{"2xx"}, "_2xx"},
+	} {
+		assert.Equal(t, tt.expected, metricName(tt.parts), strings.Join(tt.parts, ","))
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+)
+
+var (
+	reloadMtx   sync.Mutex
+	reloadNext  int
+	reloadHooks = map[int]func(ctx context.Context) error{}
+)
+
+// OnReload registers fn to be called after the configuration was reloaded
+// with changes, which happens when the process receives a SIGHUP. The flags
+// bound with cfgstruct hold the new values when fn is called. The returned
+// function unregisters fn.
+func OnReload(fn func(ctx context.Context) error) (unregister func()) {
+	reloadMtx.Lock()
+	defer reloadMtx.Unlock()
+	id := reloadNext
+	reloadNext++
+	reloadHooks[id] = fn
+	return func() {
+		reloadMtx.Lock()
+		defer reloadMtx.Unlock()
+		delete(reloadHooks, id)
+	}
+}
+
+// watchReload reloads the configuration of cmd whenever the process receives
+// a SIGHUP, until stop is called. cmdline are the flags set on the command
+// line, which keep their values.
+func watchReload(ctx context.Context, log *zap.Logger, cmd *cobra.Command, cmdline map[string]bool) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				reload(ctx, log, cmd, cmdline)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// reload loads the configuration of cmd again, logs the changes and calls the
+// OnReload functions if there are any
+func reload(ctx context.Context, log *zap.Logger, cmd *cobra.Command, cmdline map[string]bool) {
+	log.Info("Reloading configuration")
+
+	before := map[string]string{}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		before[f.Name] = f.Value.String()
+	})
+
+	config, err := loadConfig(cmd, cmdline)
+	if err != nil {
+		log.Error("failed to reload configuration", zap.Error(err))
+		return
+	}
+	for _, key := range config.brokenKeys {
+		log.Sugar().Infof("Invalid configuration file key: %s", key)
+	}
+	for _, key := range config.brokenVals {
+		log.Sugar().Infof("Invalid configuration value for key: %s", key)
+	}
+
+	changed := false
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if value := f.Value.String(); value != before[f.Name] {
+			changed = true
+			log.Info("Configuration changed", zap.String("key", f.Name),
+				zap.String("old", redactValue(f.Name, before[f.Name])),
+				zap.String("new", dumpValue(f)))
+		}
+	})
+	if !changed {
+		log.Info("Configuration unchanged")
+		return
+	}
+
+	reloadMtx.Lock()
+	ids := make([]int, 0, len(reloadHooks))
+	for id := range reloadHooks {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	hooks := make([]func(ctx context.Context) error, 0, len(ids))
+	for _, id := range ids {
+		hooks = append(hooks, reloadHooks[id])
+	}
+	reloadMtx.Unlock()
+
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			log.Error("failed to apply configuration", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"storj.io/storj/internal/testcontext"
+)
+
+func TestReload(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dir := ctx.Dir("config")
+	path := filepath.Join(dir, "config.yaml")
+	err := ioutil.WriteFile(path, []byte(
+		"interval: 1m\n"+
+			"removed: file\n"+
+			"pinned: file\n"), 0644)
+	require.NoError(t, err)
+
+	cmd := &cobra.Command{Use: "run"}
+	flags := cmd.Flags()
+	flags.String("config-dir", "", "")
+	interval := flags.Duration("interval", 0, "")
+	removed := flags.String("removed", "default", "")
+	pinned := flags.String("pinned", "default", "")
+
+	require.NoError(t, cmd.ParseFlags([]string{"--config-dir", dir, "--pinned", "flag"}))
+	cmdline := commandLine(cmd)
+
+	_, err = loadConfig(cmd, cmdline)
+	require.NoError(t, err)
+	assert.Equal(t, "1m0s", interval.String())
+	assert.Equal(t, "file", *removed)
+	assert.Equal(t, "flag", *pinned)
+
+	var calls int
+	unregister := OnReload(func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+	defer unregister()
+
+	// nothing changed
+	reload(ctx, zap.NewNop(), cmd, cmdline)
+	assert.Equal(t, 0, calls)
+
+	err = ioutil.WriteFile(path, []byte(
+		"interval: 5m\n"+
+			"pinned: changed\n"), 0644)
+	require.NoError(t, err)
+
+	reload(ctx, zap.NewNop(), cmd, cmdline)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "5m0s", interval.String())
+	assert.Equal(t, "default", *removed)
+	assert.Equal(t, "flag", *pinned)
+
+	unregister()
+	require.NoError(t, ioutil.WriteFile(path, []byte("interval: 10m\n"), 0644))
+	reload(ctx, zap.NewNop(), cmd, cmdline)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "10m0s", interval.String())
+}
+
+func TestReloadRedacted(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dir := ctx.Dir("config")
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("enc.key: old-secret\n"), 0644))
+
+	cmd := &cobra.Command{Use: "run"}
+	flags := cmd.Flags()
+	flags.String("config-dir", "", "")
+	key := flags.String("enc.key", "", "")
+
+	require.NoError(t, cmd.ParseFlags([]string{"--config-dir", dir}))
+	cmdline := commandLine(cmd)
+
+	_, err := loadConfig(cmd, cmdline)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("enc.key: new-secret\n"), 0644))
+
+	core, logs := observer.New(zap.InfoLevel)
+	reload(ctx, zap.New(core), cmd, cmdline)
+	assert.Equal(t, "new-secret", *key)
+
+	changes := logs.FilterMessage("Configuration changed").All()
+	require.Len(t, changes, 1)
+	fields := changes[0].ContextMap()
+	assert.Equal(t, "enc.key", fields["key"])
+	assert.Equal(t, "<redacted>", fields["old"])
+	assert.Equal(t, "<redacted>", fields["new"])
+}
@@ -6,6 +6,7 @@ package buckets
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"strconv"
 	"time"
 
@@ -25,7 +26,7 @@ var mon = monkit.Package()
 // Store creates an interface for interacting with buckets
 type Store interface {
 	Get(ctx context.Context, bucket string) (meta Meta, err error)
-	Put(ctx context.Context, bucket string, pathCipher storj.Cipher) (meta Meta, err error)
+	Put(ctx context.Context, bucket string, meta Meta) (Meta, error)
 	Delete(ctx context.Context, bucket string) (err error)
 	List(ctx context.Context, startAfter, endBefore string, limit int) (items []ListItem, more bool, err error)
 	GetObjectStore(ctx context.Context, bucketName string) (store objects.Store, err error)
@@ -47,6 +48,16 @@ type BucketStore struct {
 type Meta struct {
 	Created            time.Time
 	PathEncryptionType storj.Cipher
+
+	// defaults for the new objects of the bucket, unset if zero
+	SegmentsSize     int64
+	InlineThreshold  int
+	RedundancyScheme storj.RedundancyScheme
+	EncryptionScheme storj.EncryptionScheme
+	Compression      storj.Compression
+
+	// rules for expiring the new objects of the bucket
+	Lifecycle []storj.LifecycleRule
 }
 
 // NewStore instantiates BucketStore
@@ -95,22 +106,42 @@ func (b *BucketStore) Get(ctx context.Context, bucket string) (meta Meta, err er
 	return convertMeta(objMeta)
 }
 
-// Put calls objects store Put
-func (b *BucketStore) Put(ctx context.Context, bucket string, pathCipher storj.Cipher) (meta Meta, err error) {
+// Put calls objects store Put. A non-zero creation time of meta is kept, so
+// updating the metadata of an existing bucket doesn't change it.
+func (b *BucketStore) Put(ctx context.Context, bucket string, meta Meta) (_ Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	if bucket == "" {
 		return Meta{}, storj.ErrNoBucket.New("")
 	}
 
-	if pathCipher < storj.Unencrypted || pathCipher > storj.SecretBox {
+	pathCipher := meta.PathEncryptionType
+	if pathCipher < storj.Unencrypted || pathCipher > storj.XChaCha20Poly1305 {
 		return Meta{}, encryption.ErrInvalidConfig.New("encryption type %d is not supported", pathCipher)
 	}
 
+	if cipher := meta.EncryptionScheme.Cipher; cipher < storj.Unencrypted || cipher > storj.XChaCha20Poly1305 {
+		return Meta{}, encryption.ErrInvalidConfig.New("encryption type %d is not supported", cipher)
+	}
+
 	r := bytes.NewReader(nil)
 	userMeta := map[string]string{
 		"path-enc-type": strconv.Itoa(int(pathCipher)),
 	}
+	setDefaults(userMeta, meta)
+
+	if !meta.Created.IsZero() {
+		userMeta["created"] = meta.Created.UTC().Format(time.RFC3339Nano)
+	}
+
+	if len(meta.Lifecycle) > 0 {
+		lifecycle, err := json.Marshal(meta.Lifecycle)
+		if err != nil {
+			return Meta{}, err
+		}
+		userMeta["lifecycle"] = string(lifecycle)
+	}
+
 	var exp time.Time
 	m, err := b.store.Put(ctx, bucket, r, pb.SerializableMeta{UserDefined: userMeta}, exp)
 	if err != nil {
@@ -140,7 +171,7 @@ func (b *BucketStore) Delete(ctx context.Context, bucket string) (err error) {
 func (b *BucketStore) List(ctx context.Context, startAfter, endBefore string, limit int) (items []ListItem, more bool, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	objItems, more, err := b.store.List(ctx, "", startAfter, endBefore, false, limit, meta.Modified)
+	objItems, more, err := b.store.List(ctx, "", startAfter, endBefore, false, limit, meta.Modified|meta.UserDefined)
 	if err != nil {
 		return items, more, err
 	}
@@ -179,8 +210,93 @@ func convertMeta(m objects.Meta) (Meta, error) {
 		cipher = storj.Cipher(pet)
 	}
 
-	return Meta{
+	meta := Meta{
 		Created:            m.Modified,
 		PathEncryptionType: cipher,
-	}, nil
+	}
+
+	if created, ok := m.UserDefined["created"]; ok {
+		t, err := time.Parse(time.RFC3339Nano, created)
+		if err != nil {
+			return Meta{}, err
+		}
+		meta.Created = t
+	}
+
+	err := getDefaults(m.UserDefined, &meta)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	if lifecycle, ok := m.UserDefined["lifecycle"]; ok {
+		err = json.Unmarshal([]byte(lifecycle), &meta.Lifecycle)
+		if err != nil {
+			return Meta{}, err
+		}
+	}
+
+	return meta, nil
+}
+
+// setDefaults stores the non-zero defaults for new objects of meta in
+// userMeta
+func setDefaults(userMeta map[string]string, meta Meta) {
+	setInt := func(key string, value int64) {
+		if value != 0 {
+			userMeta[key] = strconv.FormatInt(value, 10)
+		}
+	}
+
+	setInt("default-segment-size", meta.SegmentsSize)
+	setInt("default-inline-threshold", int64(meta.InlineThreshold))
+
+	rs := meta.RedundancyScheme
+	setInt("default-rs-algo", int64(rs.Algorithm))
+	setInt("default-rs-share-size", int64(rs.ShareSize))
+	setInt("default-rs-required", int64(rs.RequiredShares))
+	setInt("default-rs-repair", int64(rs.RepairShares))
+	setInt("default-rs-optimal", int64(rs.OptimalShares))
+	setInt("default-rs-total", int64(rs.TotalShares))
+
+	es := meta.EncryptionScheme
+	setInt("default-enc-type", int64(es.Cipher))
+	setInt("default-enc-block-size", int64(es.BlockSize))
+
+	setInt("default-compression", int64(meta.Compression))
+}
+
+// getDefaults loads the defaults for new objects from userMeta into meta.
+// Buckets created before the defaults were stored have none.
+func getDefaults(userMeta map[string]string, meta *Meta) error {
+	var err error
+	getInt := func(key string, bitSize int) int64 {
+		value, ok := userMeta[key]
+		if !ok || err != nil {
+			return 0
+		}
+		var v int64
+		v, err = strconv.ParseInt(value, 10, bitSize)
+		return v
+	}
+
+	meta.SegmentsSize = getInt("default-segment-size", 64)
+	meta.InlineThreshold = int(getInt("default-inline-threshold", 32))
+
+	meta.RedundancyScheme = storj.RedundancyScheme{
+		Algorithm:      storj.RedundancyAlgorithm(getInt("default-rs-algo", 8)),
+		ShareSize:      int32(getInt("default-rs-share-size", 32)),
+		RequiredShares: int16(getInt("default-rs-required", 16)),
+		RepairShares:   int16(getInt("default-rs-repair", 16)),
+		OptimalShares:  int16(getInt("default-rs-optimal", 16)),
+		TotalShares:    int16(getInt("default-rs-total", 16)),
+	}
+
+	meta.EncryptionScheme = storj.EncryptionScheme{
+		Cipher:    storj.Cipher(getInt("default-enc-type", 8)),
+		BlockSize: int32(getInt("default-enc-block-size", 32)),
+	}
+
+	meta.Compression = storj.Compression(getInt("default-compression", 8))
+
+	return err
 }
@@ -39,6 +39,7 @@ type psClientHelper func(context.Context, *pb.Node) (psclient.Client, error)
 
 type ecClient struct {
 	transport       transport.Client
+	breakers        *transport.Breakers
 	memoryLimit     int
 	newPSClientFunc psClientFunc
 }
@@ -48,6 +49,7 @@ func NewClient(identity *provider.FullIdentity, memoryLimit int) Client {
 	tc := transport.NewClient(identity)
 	return &ecClient{
 		transport:       tc,
+		breakers:        transport.DefaultBreakers(),
 		memoryLimit:     memoryLimit,
 		newPSClientFunc: psclient.NewPSClient,
 	}
@@ -57,6 +59,12 @@ func (ec *ecClient) newPSClient(ctx context.Context, n *pb.Node) (psclient.Clien
 	return ec.newPSClientFunc(ctx, ec.transport, n, 0)
 }
 
+// available returns whether the requests to the node aren't rejected by its
+// circuit breaker
+func (ec *ecClient) available(n *pb.Node) bool {
+	return ec.breakers == nil || ec.breakers.Available(n)
+}
+
 func (ec *ecClient) Put(ctx context.Context, nodes []*pb.Node, rs eestream.RedundancyStrategy,
 	pieceID psclient.PieceID, data io.Reader, expiration time.Time, pba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) (successfulNodes []*pb.Node, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -93,6 +101,12 @@ func (ec *ecClient) Put(ctx context.Context, nodes []*pb.Node, rs eestream.Redun
 				infos <- info{i: i, err: err}
 				return
 			}
+			if !ec.available(n) {
+				zap.S().Debugf("Skipping failing node %s for putting piece %s", n.Id, pieceID)
+				_, _ = io.Copy(ioutil.Discard, readers[i])
+				infos <- info{i: i, err: transport.BreakerError.New("node %s", n.Id)}
+				return
+			}
 			derivedPieceID, err := pieceID.Derive(n.Id.Bytes())
 
 			if err != nil {
@@ -174,7 +188,7 @@ func (ec *ecClient) Get(ctx context.Context, nodes []*pb.Node, es eestream.Erasu
 	ch := make(chan rangerInfo, len(nodes))
 
 	for i, n := range nodes {
-		if n == nil {
+		if n == nil || !ec.available(n) {
 			ch <- rangerInfo{i: i, rr: nil, err: nil}
 			continue
 		}
@@ -305,7 +319,6 @@ func calcPadded(size int64, blockSize int) int64 {
 }
 
 type lazyPieceRanger struct {
-	ranger            ranger.Ranger
 	newPSClientHelper psClientHelper
 	node              *pb.Node
 	id                psclient.PieceID
@@ -319,20 +332,39 @@ func (lr *lazyPieceRanger) Size() int64 {
 	return lr.size
 }
 
-// Range implements Ranger.Range to be lazily connected
-func (lr *lazyPieceRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
-	if lr.ranger == nil {
-		ps, err := lr.newPSClientHelper(ctx, lr.node)
-		if err != nil {
-			return nil, err
-		}
-		ranger, err := ps.Get(ctx, lr.id, lr.size, lr.pba, lr.authorization)
+// Range implements Ranger.Range to be lazily connected. The connection is
+// released when the returned reader is closed.
+func (lr *lazyPieceRanger) Range(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
+	ps, err := lr.newPSClientHelper(ctx, lr.node)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
 		if err != nil {
-			return nil, err
+			utils.LogClose(ps)
 		}
-		lr.ranger = ranger
+	}()
+
+	ranger, err := ps.Get(ctx, lr.id, lr.size, lr.pba, lr.authorization)
+	if err != nil {
+		return nil, err
 	}
-	return lr.ranger.Range(ctx, offset, length)
+	rc, err := ranger.Range(ctx, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return &pieceReadCloser{ReadCloser: rc, ps: ps}, nil
+}
+
+// pieceReadCloser closes the piecestore client after the piece reader
+type pieceReadCloser struct {
+	io.ReadCloser
+	ps psclient.Client
+}
+
+// Close implements io.Closer
+func (r *pieceReadCloser) Close() error {
+	return utils.CombineErrors(r.ReadCloser.Close(), r.ps.Close())
 }
 
 func nonNilCount(nodes []*pb.Node) int {
@@ -116,3 +116,16 @@ func (m *MockStore) List(ctx context.Context, prefix, startAfter, endBefore stor
 func (mr *MockStoreMockRecorder) List(ctx, prefix, startAfter, endBefore, recursive, limit, metaFlags interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockStore)(nil).List), ctx, prefix, startAfter, endBefore, recursive, limit, metaFlags)
 }
+
+// WithRedundancy mocks base method
+func (m *MockStore) WithRedundancy(scheme storj.RedundancyScheme, threshold int) (Store, error) {
+	ret := m.ctrl.Call(m, "WithRedundancy", scheme, threshold)
+	ret0, _ := ret[0].(Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithRedundancy indicates an expected call of WithRedundancy
+func (mr *MockStoreMockRecorder) WithRedundancy(scheme, threshold interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithRedundancy", reflect.TypeOf((*MockStore)(nil).WithRedundancy), scheme, threshold)
+}
@@ -51,6 +51,7 @@ type Store interface {
 	Put(ctx context.Context, data io.Reader, expiration time.Time, segmentInfo func() (storj.Path, []byte, error)) (meta Meta, err error)
 	Delete(ctx context.Context, path storj.Path) (err error)
 	List(ctx context.Context, prefix, startAfter, endBefore storj.Path, recursive bool, limit int, metaFlags uint32) (items []ListItem, more bool, err error)
+	WithRedundancy(scheme storj.RedundancyScheme, threshold int) (Store, error)
 }
 
 type segmentStore struct {
@@ -66,6 +67,32 @@ func NewSegmentStore(oc overlay.Client, ec ecclient.Client, pdb pdbclient.Client
 	return &segmentStore{oc: oc, ec: ec, pdb: pdb, rs: rs, thresholdSize: threshold}
 }
 
+// WithRedundancy returns a store which uploads new remote segments with the
+// given redundancy scheme and keeps segments up to threshold bytes inline.
+// Zero values keep the defaults of the store.
+func (s *segmentStore) WithRedundancy(scheme storj.RedundancyScheme, threshold int) (Store, error) {
+	store := *s
+
+	if !scheme.IsZero() {
+		fc, err := infectious.NewFEC(int(scheme.RequiredShares), int(scheme.TotalShares))
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+
+		rs, err := eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, int(scheme.ShareSize)), int(scheme.RepairShares), int(scheme.OptimalShares))
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		store.rs = rs
+	}
+
+	if threshold > 0 {
+		store.thresholdSize = threshold
+	}
+
+	return &store, nil
+}
+
 // Meta retrieves the metadata of the segment
 func (s *segmentStore) Meta(ctx context.Context, path storj.Path) (meta Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
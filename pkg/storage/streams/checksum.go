@@ -0,0 +1,58 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+)
+
+// ETagger is implemented by readers, which know the S3 compatible ETag of
+// the data read from them, e.g. the ETag of a multipart upload. It is
+// queried after the reader returned io.EOF.
+type ETagger interface {
+	ETag() string
+}
+
+// ChecksumReader computes the checksum and the ETag of the data read so far
+type ChecksumReader struct {
+	reader io.Reader
+	sha256 hash.Hash
+	md5    hash.Hash
+}
+
+// NewChecksumReader computes the checksum and the ETag of the data read from r
+func NewChecksumReader(r io.Reader) *ChecksumReader {
+	return &ChecksumReader{
+		reader: r,
+		sha256: sha256.New(),
+		md5:    md5.New(),
+	}
+}
+
+func (r *ChecksumReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	_, _ = r.sha256.Write(p[:n])
+	_, _ = r.md5.Write(p[:n])
+	return n, err
+}
+
+// Checksum returns the SHA-256 hash of the data read so far
+func (r *ChecksumReader) Checksum() []byte {
+	return r.sha256.Sum(nil)
+}
+
+// ETag returns the ETag provided by the underlying reader, or the hex encoded
+// MD5 hash of the data read so far
+func (r *ChecksumReader) ETag() string {
+	if etagger, ok := r.reader.(ETagger); ok {
+		if etag := etagger.ETag(); etag != "" {
+			return etag
+		}
+	}
+	return hex.EncodeToString(r.md5.Sum(nil))
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/utils"
+)
+
+// compressionFrameSize is the number of uncompressed bytes in a frame. The
+// frames are compressed independently, so a range of the content is read by
+// decompressing only the frames covering it.
+const compressionFrameSize = 1 << 20
+
+// compressReader compresses the data read from reader in frames
+type compressReader struct {
+	reader      io.Reader
+	compression storj.Compression
+	frame       []byte
+	compressed  bytes.Buffer
+	writer      *gzip.Writer
+	frameSizes  []int64
+	size        int64
+	err         error
+}
+
+// newCompressReader returns a reader compressing r with compression in
+// frames of frameSize uncompressed bytes
+func newCompressReader(r io.Reader, compression storj.Compression, frameSize int) *compressReader {
+	reader := &compressReader{
+		reader:      r,
+		compression: compression,
+		frame:       make([]byte, frameSize),
+	}
+	reader.writer = gzip.NewWriter(&reader.compressed)
+	return reader
+}
+
+func (r *compressReader) Read(p []byte) (n int, err error) {
+	for r.compressed.Len() == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.compressFrame()
+	}
+	return r.compressed.Read(p)
+}
+
+// compressFrame compresses the next frame of the data
+func (r *compressReader) compressFrame() error {
+	n, err := io.ReadFull(r.reader, r.frame)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	if n == 0 || (err != nil && err != io.EOF) {
+		return err
+	}
+
+	r.writer.Reset(&r.compressed)
+	_, writeErr := r.writer.Write(r.frame[:n])
+	if writeErr != nil {
+		return writeErr
+	}
+	if closeErr := r.writer.Close(); closeErr != nil {
+		return closeErr
+	}
+
+	r.frameSizes = append(r.frameSizes, int64(r.compressed.Len()))
+	r.size += int64(n)
+	return err
+}
+
+// setInfo records the compression of the data read so far in stream
+func (r *compressReader) setInfo(stream *pb.StreamInfo) {
+	stream.Compression = int32(r.compression)
+	stream.CompressionFrameSize = int64(len(r.frame))
+	stream.CompressedFrameSizes = r.frameSizes
+	stream.UncompressedSize = r.size
+}
+
+// decompressRanger decompresses the frames of a compressed ranger
+type decompressRanger struct {
+	rr        ranger.Ranger
+	frameSize int64
+	// offsets are the offsets of the frames in rr, followed by the size of rr
+	offsets []int64
+	size    int64
+}
+
+// newDecompressRanger returns a ranger of the uncompressed content of rr,
+// which is compressed as described in stream
+func newDecompressRanger(rr ranger.Ranger, stream *pb.StreamInfo) (ranger.Ranger, error) {
+	if storj.Compression(stream.Compression) != storj.Gzip {
+		return nil, errs.New("compression %d is not supported", stream.Compression)
+	}
+	if stream.CompressionFrameSize <= 0 {
+		return nil, errs.New("invalid compression frame size %d", stream.CompressionFrameSize)
+	}
+
+	frames := (stream.UncompressedSize + stream.CompressionFrameSize - 1) / stream.CompressionFrameSize
+	if int64(len(stream.CompressedFrameSizes)) != frames {
+		return nil, errs.New("expected %d compressed frames, got %d", frames, len(stream.CompressedFrameSizes))
+	}
+
+	offsets := make([]int64, 0, frames+1)
+	var offset int64
+	for _, size := range stream.CompressedFrameSizes {
+		offsets = append(offsets, offset)
+		offset += size
+	}
+	offsets = append(offsets, offset)
+
+	if offset != rr.Size() {
+		return nil, errs.New("compressed size %d doesn't match the stream size %d", offset, rr.Size())
+	}
+
+	return &decompressRanger{
+		rr:        rr,
+		frameSize: stream.CompressionFrameSize,
+		offsets:   offsets,
+		size:      stream.UncompressedSize,
+	}, nil
+}
+
+// Size implements Ranger.Size
+func (r *decompressRanger) Size() int64 {
+	return r.size
+}
+
+// Range implements Ranger.Range by decompressing the frames covering the range
+func (r *decompressRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	if offset < 0 {
+		return nil, errs.New("negative offset")
+	}
+	if length < 0 {
+		return nil, errs.New("negative length")
+	}
+	if offset+length > r.size {
+		return nil, errs.New("range beyond end")
+	}
+	if length == 0 {
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	first := offset / r.frameSize
+	last := (offset + length - 1) / r.frameSize
+
+	compressed, err := r.rr.Range(ctx, r.offsets[first], r.offsets[last+1]-r.offsets[first])
+	if err != nil {
+		return nil, err
+	}
+
+	// the gzip reader reads the concatenated frames as a single stream
+	decompressed, err := gzip.NewReader(compressed)
+	if err != nil {
+		return nil, utils.CombineErrors(err, compressed.Close())
+	}
+
+	_, err = io.CopyN(ioutil.Discard, decompressed, offset-first*r.frameSize)
+	if err != nil {
+		return nil, utils.CombineErrors(err, decompressed.Close(), compressed.Close())
+	}
+
+	return &decompressReader{
+		Reader:       io.LimitReader(decompressed, length),
+		decompressed: decompressed,
+		compressed:   compressed,
+	}, nil
+}
+
+// decompressReader closes the decompressor and the compressed data
+type decompressReader struct {
+	io.Reader
+	decompressed io.Closer
+	compressed   io.Closer
+}
+
+// Close implements io.Closer
+func (r *decompressReader) Close() error {
+	return utils.CombineErrors(r.decompressed.Close(), r.compressed.Close())
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/encryption"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestCompression(t *testing.T) {
+	var compressible bytes.Buffer
+	for i := 0; compressible.Len() < 5*compressionFrameSize/2; i++ {
+		fmt.Fprintf(&compressible, `{"line":%d,"level":"info","msg":"request served"}`+"\n", i)
+	}
+
+	random := make([]byte, compressionFrameSize+100)
+	_, err := rand.Read(random)
+	require.NoError(t, err)
+
+	for _, concurrency := range []int{0, 3} {
+		for _, data := range [][]byte{compressible.Bytes(), random, {}} {
+			mem := newMemorySegments()
+
+			streamStore, err := NewStreamStore(mem, 64*1024, encryption.NewKeyStore(new(storj.Key), false), 1024, storj.AESGCM, concurrency, 0)
+			require.NoError(t, err)
+
+			compressed, err := streamStore.WithCompression(storj.Gzip)
+			require.NoError(t, err)
+
+			m, err := compressed.Put(ctx, "bucket/path", storj.AESGCM, bytes.NewReader(data), nil, time.Time{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(data)), m.Size)
+
+			if len(data) == compressible.Len() {
+				var stored int
+				for _, segment := range mem.data {
+					stored += len(segment)
+				}
+				assert.True(t, stored < len(data)/4, "stored %d bytes", stored)
+			}
+
+			m, err = streamStore.Meta(ctx, "bucket/path", storj.AESGCM)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(data)), m.Size)
+
+			// the stream is decompressed regardless of the store's compression
+			rr, _, err := streamStore.Get(ctx, "bucket/path", storj.AESGCM)
+			require.NoError(t, err)
+			require.Equal(t, int64(len(data)), rr.Size())
+
+			size := int64(len(data))
+			for _, tt := range []struct {
+				offset, length int64
+			}{
+				{0, size},
+				{0, 0},
+				{size / 2, size - size/2},
+				{compressionFrameSize - 10, 20},
+				{compressionFrameSize, 10},
+				{3, size/3 + 7},
+			} {
+				if tt.offset+tt.length > size || tt.offset > size {
+					continue
+				}
+				reader, err := rr.Range(ctx, tt.offset, tt.length)
+				require.NoError(t, err)
+				got, err := ioutil.ReadAll(reader)
+				require.NoError(t, err)
+				require.NoError(t, reader.Close())
+				assert.Equal(t, data[tt.offset:tt.offset+tt.length], got, "range %d+%d of %d bytes", tt.offset, tt.length, size)
+			}
+
+			_, err = rr.Range(ctx, 0, size+1)
+			assert.Error(t, err)
+		}
+	}
+}
+
+func TestWithCompressionUnsupported(t *testing.T) {
+	streamStore, err := NewStreamStore(newMemorySegments(), 64, encryption.NewKeyStore(new(storj.Key), false), 32, storj.AESGCM, 0, 0)
+	require.NoError(t, err)
+
+	_, err = streamStore.WithCompression(storj.Compression(100))
+	assert.Error(t, err)
+
+	_, err = streamStore.WithCompression(storj.Uncompressed)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,342 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/encryption"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/segments"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+// memorySegments is an in-memory segments.Store for round trip tests
+type memorySegments struct {
+	mu       sync.Mutex
+	data     map[storj.Path][]byte
+	meta     map[storj.Path][]byte
+	inflight int
+	maxPuts  int
+}
+
+func newMemorySegments() *memorySegments {
+	return &memorySegments{
+		data: map[storj.Path][]byte{},
+		meta: map[storj.Path][]byte{},
+	}
+}
+
+func (m *memorySegments) Meta(ctx context.Context, path storj.Path) (segments.Meta, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.data[path]
+	if !ok {
+		return segments.Meta{}, storage.ErrKeyNotFound.New("%s", path)
+	}
+	return segments.Meta{Size: int64(len(data)), Data: m.meta[path]}, nil
+}
+
+func (m *memorySegments) Get(ctx context.Context, path storj.Path) (ranger.Ranger, segments.Meta, error) {
+	meta, err := m.Meta(ctx, path)
+	if err != nil {
+		return nil, segments.Meta{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return ranger.ByteRanger(m.data[path]), meta, nil
+}
+
+func (m *memorySegments) Put(ctx context.Context, data io.Reader, expiration time.Time, segmentInfo func() (storj.Path, []byte, error)) (segments.Meta, error) {
+	m.mu.Lock()
+	m.inflight++
+	if m.inflight > m.maxPuts {
+		m.maxPuts = m.inflight
+	}
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.inflight--
+		m.mu.Unlock()
+	}()
+
+	content, err := ioutil.ReadAll(data)
+	if err != nil {
+		return segments.Meta{}, err
+	}
+	// give the other uploads a chance to overlap
+	time.Sleep(time.Millisecond)
+
+	path, meta, err := segmentInfo()
+	if err != nil {
+		return segments.Meta{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[path] = content
+	m.meta[path] = meta
+	return segments.Meta{Size: int64(len(content)), Data: meta}, nil
+}
+
+func (m *memorySegments) Delete(ctx context.Context, path storj.Path) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.data[path]; !ok {
+		return storage.ErrKeyNotFound.New("%s", path)
+	}
+	delete(m.data, path)
+	delete(m.meta, path)
+	return nil
+}
+
+func (m *memorySegments) List(ctx context.Context, prefix, startAfter, endBefore storj.Path, recursive bool, limit int, metaFlags uint32) ([]segments.ListItem, bool, error) {
+	return nil, false, nil
+}
+
+func (m *memorySegments) WithRedundancy(scheme storj.RedundancyScheme, threshold int) (segments.Store, error) {
+	return m, nil
+}
+
+func TestParallelUploadPrefetchDownload(t *testing.T) {
+	for _, size := range []int{0, 20, 64, 150, 192, 600} {
+		mem := newMemorySegments()
+
+		streamStore, err := NewStreamStore(mem, 64, encryption.NewKeyStore(new(storj.Key), false), 32, storj.AESGCM, 3, 2)
+		require.NoError(t, err)
+
+		data := make([]byte, size)
+		_, err = rand.Read(data)
+		require.NoError(t, err)
+
+		meta, err := streamStore.Put(ctx, "bucket/path", storj.AESGCM, bytes.NewReader(data), []byte("metadata"), time.Time{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(size), meta.Size)
+
+		expectedSegments := (size + 63) / 64
+		if expectedSegments == 0 {
+			expectedSegments = 1
+		}
+		assert.Len(t, mem.data, expectedSegments)
+		assert.True(t, mem.maxPuts <= 3)
+
+		rr, meta, err := streamStore.Get(ctx, "bucket/path", storj.AESGCM)
+		require.NoError(t, err)
+		assert.Equal(t, int64(size), meta.Size)
+		assert.Equal(t, []byte("metadata"), meta.Data)
+
+		for _, r := range []struct{ offset, length int64 }{
+			{0, int64(size)},
+			{int64(size) / 3, int64(size) / 2},
+			{int64(size), 0},
+		} {
+			reader, err := rr.Range(ctx, r.offset, r.length)
+			require.NoError(t, err)
+			got, err := ioutil.ReadAll(reader)
+			require.NoError(t, err)
+			require.NoError(t, reader.Close())
+			assert.Equal(t, data[r.offset:r.offset+r.length], got, "size %d range %v", size, r)
+		}
+	}
+}
+
+// gatedSegments holds the puts of segments until want of them are in flight
+type gatedSegments struct {
+	*memorySegments
+	want    int
+	mu      sync.Mutex
+	waiting int
+	release chan struct{}
+}
+
+func (g *gatedSegments) Put(ctx context.Context, data io.Reader, expiration time.Time, segmentInfo func() (storj.Path, []byte, error)) (segments.Meta, error) {
+	g.mu.Lock()
+	g.waiting++
+	if g.waiting == g.want {
+		close(g.release)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-g.release:
+	case <-time.After(5 * time.Second):
+	case <-ctx.Done():
+		return segments.Meta{}, ctx.Err()
+	}
+	return g.memorySegments.Put(ctx, data, expiration, segmentInfo)
+}
+
+func TestParallelUploadOverlaps(t *testing.T) {
+	gated := &gatedSegments{
+		memorySegments: newMemorySegments(),
+		want:           3,
+		release:        make(chan struct{}),
+	}
+
+	streamStore, err := NewStreamStore(gated, 64, encryption.NewKeyStore(new(storj.Key), false), 32, storj.AESGCM, 3, 0)
+	require.NoError(t, err)
+
+	// five segments, the first four of which are uploaded in parallel
+	data := make([]byte, 5*64)
+	_, err = rand.Read(data)
+	require.NoError(t, err)
+
+	_, err = streamStore.Put(ctx, "bucket/path", storj.AESGCM, bytes.NewReader(data), nil, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, gated.data, 5)
+
+	// the puts were released by three segments in flight at once, not by the
+	// timeout
+	select {
+	case <-gated.release:
+	default:
+		t.Fatal("segments weren't uploaded in parallel")
+	}
+}
+
+func TestReadSegmentData(t *testing.T) {
+	for _, tt := range []struct {
+		size, segmentSize int64
+		eof               bool
+	}{
+		{0, 64, true},
+		{10, 64, true},
+		{64, 64, false},
+		{100, 64, false},
+		{100 * 1024, 1 << 20, true},
+		{3 * initialSegmentBuffer, 2 * initialSegmentBuffer, false},
+	} {
+		data := make([]byte, tt.size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		buf, eof, err := readSegmentData(bytes.NewReader(data), tt.segmentSize)
+		require.NoError(t, err)
+		assert.Equal(t, tt.eof, eof, "%+v", tt)
+
+		expected := data
+		if int64(len(expected)) > tt.segmentSize {
+			expected = expected[:tt.segmentSize]
+		}
+		assert.Equal(t, expected, buf, "%+v", tt)
+		// the buffer is sized to the data read, not to the segment size
+		assert.True(t, int64(cap(buf)) <= tt.segmentSize, "%+v", tt)
+		if tt.size < initialSegmentBuffer {
+			assert.True(t, cap(buf) <= initialSegmentBuffer, "%+v", tt)
+		}
+	}
+}
+
+func TestWithEncryption(t *testing.T) {
+	mem := newMemorySegments()
+
+	streamStore, err := NewStreamStore(mem, 64, encryption.NewKeyStore(new(storj.Key), false), 32, storj.AESGCM, 0, 0)
+	require.NoError(t, err)
+
+	data := make([]byte, 150)
+	_, err = rand.Read(data)
+	require.NoError(t, err)
+
+	for _, cipher := range []storj.Cipher{storj.Unencrypted, storj.AESGCM, storj.SecretBox, storj.XChaCha20Poly1305} {
+		scheme := storj.EncryptionScheme{Cipher: cipher, BlockSize: 48}
+
+		_, err = streamStore.WithEncryption(scheme).Put(ctx, "bucket/path", storj.AESGCM, bytes.NewReader(data), nil, time.Time{})
+		require.NoError(t, err, cipher)
+
+		// the stream is read with the scheme it was encrypted with
+		rr, _, err := streamStore.Get(ctx, "bucket/path", storj.AESGCM)
+		require.NoError(t, err, cipher)
+
+		reader, err := rr.Range(ctx, 0, rr.Size())
+		require.NoError(t, err, cipher)
+		got, err := ioutil.ReadAll(reader)
+		require.NoError(t, err, cipher)
+		require.NoError(t, reader.Close())
+		assert.Equal(t, data, got, cipher)
+	}
+}
+
+func TestWithRedundancy(t *testing.T) {
+	mem := newMemorySegments()
+
+	streamStore, err := NewStreamStore(mem, 64, encryption.NewKeyStore(new(storj.Key), false), 32, storj.AESGCM, 0, 0)
+	require.NoError(t, err)
+
+	data := make([]byte, 150)
+	_, err = rand.Read(data)
+	require.NoError(t, err)
+
+	withSegmentSize, err := streamStore.WithRedundancy(storj.RedundancyScheme{}, 32, 0)
+	require.NoError(t, err)
+
+	meta, err := withSegmentSize.Put(ctx, "bucket/path", storj.AESGCM, bytes.NewReader(data), nil, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), meta.Size)
+	assert.Len(t, mem.data, 5)
+
+	rr, _, err := streamStore.Get(ctx, "bucket/path", storj.AESGCM)
+	require.NoError(t, err)
+
+	reader, err := rr.Range(ctx, 0, rr.Size())
+	require.NoError(t, err)
+	got, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, data, got)
+}
+
+type etagReader struct {
+	io.Reader
+	etag string
+}
+
+func (reader *etagReader) ETag() string { return reader.etag }
+
+func TestChecksums(t *testing.T) {
+	data := make([]byte, 150)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	checksum := sha256.Sum256(data)
+	md5sum := md5.Sum(data)
+
+	for _, concurrency := range []int{0, 3} {
+		for _, tt := range []struct {
+			reader io.Reader
+			etag   string
+		}{
+			{bytes.NewReader(data), hex.EncodeToString(md5sum[:])},
+			{&etagReader{bytes.NewReader(data), "custom-2"}, "custom-2"},
+		} {
+			streamStore, err := NewStreamStore(newMemorySegments(), 64, encryption.NewKeyStore(new(storj.Key), false), 32, storj.AESGCM, concurrency, 0)
+			require.NoError(t, err)
+
+			meta, err := streamStore.Put(ctx, "bucket/path", storj.AESGCM, tt.reader, nil, time.Time{})
+			require.NoError(t, err)
+			assert.Equal(t, checksum[:], meta.Checksum)
+			assert.Equal(t, tt.etag, meta.ETag)
+
+			// the checksums are stored encrypted with the stream
+			meta, err = streamStore.Meta(ctx, "bucket/path", storj.AESGCM)
+			require.NoError(t, err)
+			assert.Equal(t, checksum[:], meta.Checksum)
+			assert.Equal(t, tt.etag, meta.ETag)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/utils"
+)
+
+// prefetchRanger concatenates segment rangers like ranger.Concat, but its
+// readers download the next segments in the background while the current
+// segment is being read
+type prefetchRanger struct {
+	rangers  []ranger.Ranger
+	size     int64
+	prefetch int
+}
+
+// newPrefetchRanger returns a ranger, which reads up to prefetch segments
+// ahead of the current one
+func newPrefetchRanger(rangers []ranger.Ranger, prefetch int) ranger.Ranger {
+	var size int64
+	for _, rr := range rangers {
+		size += rr.Size()
+	}
+	return &prefetchRanger{
+		rangers:  rangers,
+		size:     size,
+		prefetch: prefetch,
+	}
+}
+
+// Size implements Ranger.Size
+func (pr *prefetchRanger) Size() int64 {
+	return pr.size
+}
+
+// Range implements Ranger.Range
+func (pr *prefetchRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	if offset < 0 {
+		return nil, errs.New("negative offset")
+	}
+	if length < 0 {
+		return nil, errs.New("negative length")
+	}
+	if offset+length > pr.size {
+		return nil, errs.New("range beyond end")
+	}
+
+	var parts []segmentRange
+	for _, rr := range pr.rangers {
+		if length <= 0 {
+			break
+		}
+		size := rr.Size()
+		if offset >= size {
+			offset -= size
+			continue
+		}
+		partLength := size - offset
+		if partLength > length {
+			partLength = length
+		}
+		parts = append(parts, segmentRange{ranger: rr, offset: offset, length: partLength})
+		length -= partLength
+		offset = 0
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	reader := &prefetchReader{
+		ctx:      ctx,
+		cancel:   cancel,
+		parts:    parts,
+		prefetch: pr.prefetch,
+	}
+	reader.fill()
+	return reader, nil
+}
+
+// segmentRange is the part of a segment which needs to be read
+type segmentRange struct {
+	ranger         ranger.Ranger
+	offset, length int64
+}
+
+// prefetchResult is the downloaded data of a segment range
+type prefetchResult struct {
+	data []byte
+	err  error
+}
+
+// prefetchReader reads the segment ranges in order, keeping the downloads
+// of the following segment ranges in flight
+type prefetchReader struct {
+	ctx      context.Context
+	cancel   func()
+	parts    []segmentRange
+	prefetch int
+	pending  []chan prefetchResult
+	current  io.Reader
+}
+
+// fill starts downloading segment ranges until the current one and the
+// prefetch following ones are in flight
+func (reader *prefetchReader) fill() {
+	for len(reader.pending) <= reader.prefetch && len(reader.parts) > 0 {
+		part := reader.parts[0]
+		reader.parts = reader.parts[1:]
+
+		result := make(chan prefetchResult, 1)
+		go func() {
+			rc, err := part.ranger.Range(reader.ctx, part.offset, part.length)
+			if err != nil {
+				result <- prefetchResult{err: err}
+				return
+			}
+			data, err := ioutil.ReadAll(rc)
+			result <- prefetchResult{data: data, err: utils.CombineErrors(err, rc.Close())}
+		}()
+		reader.pending = append(reader.pending, result)
+	}
+}
+
+// Read implements io.Reader
+func (reader *prefetchReader) Read(p []byte) (n int, err error) {
+	for {
+		if reader.current != nil {
+			n, err = reader.current.Read(p)
+			if err != io.EOF {
+				return n, err
+			}
+			reader.current = nil
+			if n > 0 {
+				return n, nil
+			}
+		}
+
+		if len(reader.pending) == 0 {
+			return 0, io.EOF
+		}
+
+		var result prefetchResult
+		select {
+		case result = <-reader.pending[0]:
+		case <-reader.ctx.Done():
+			return 0, reader.ctx.Err()
+		}
+		reader.pending = reader.pending[1:]
+		if result.err != nil {
+			return 0, result.err
+		}
+
+		reader.current = bytes.NewReader(result.data)
+		reader.fill()
+	}
+}
+
+// Close implements io.Closer and cancels the downloads in flight
+func (reader *prefetchReader) Close() error {
+	reader.cancel()
+	return nil
+}
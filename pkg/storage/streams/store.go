@@ -11,6 +11,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -18,6 +19,7 @@ import (
 	"go.uber.org/zap"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
+	"storj.io/storj/internal/sync2"
 	"storj.io/storj/pkg/eestream"
 	"storj.io/storj/pkg/encryption"
 	"storj.io/storj/pkg/pb"
@@ -25,6 +27,7 @@ import (
 	"storj.io/storj/pkg/storage/meta"
 	"storj.io/storj/pkg/storage/segments"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/utils"
 	"storj.io/storj/storage"
 )
 
@@ -36,6 +39,8 @@ type Meta struct {
 	Expiration time.Time
 	Size       int64
 	Data       []byte
+	Checksum   []byte
+	ETag       string
 }
 
 // convertMeta converts segment metadata to stream metadata
@@ -46,11 +51,18 @@ func convertMeta(lastSegmentMeta segments.Meta) (Meta, error) {
 		return Meta{}, err
 	}
 
+	size := ((stream.NumberOfSegments - 1) * stream.SegmentsSize) + stream.LastSegmentSize
+	if storj.Compression(stream.Compression) != storj.Uncompressed {
+		size = stream.UncompressedSize
+	}
+
 	return Meta{
 		Modified:   lastSegmentMeta.Modified,
 		Expiration: lastSegmentMeta.Expiration,
-		Size:       ((stream.NumberOfSegments - 1) * stream.SegmentsSize) + stream.LastSegmentSize,
+		Size:       size,
 		Data:       stream.Metadata,
+		Checksum:   stream.Checksum,
+		ETag:       stream.Etag,
 	}, nil
 }
 
@@ -61,23 +73,33 @@ type Store interface {
 	Put(ctx context.Context, path storj.Path, pathCipher storj.Cipher, data io.Reader, metadata []byte, expiration time.Time) (Meta, error)
 	Delete(ctx context.Context, path storj.Path, pathCipher storj.Cipher) error
 	List(ctx context.Context, prefix, startAfter, endBefore storj.Path, pathCipher storj.Cipher, recursive bool, limit int, metaFlags uint32) (items []ListItem, more bool, err error)
+	WithEncryption(scheme storj.EncryptionScheme) Store
+	WithRedundancy(scheme storj.RedundancyScheme, segmentSize int64, inlineThreshold int) (Store, error)
+	WithCompression(compression storj.Compression) (Store, error)
 }
 
 // streamStore is a store for streams
 type streamStore struct {
-	segments     segments.Store
-	segmentSize  int64
-	rootKey      *storj.Key
-	encBlockSize int
-	cipher       storj.Cipher
+	segments          segments.Store
+	segmentSize       int64
+	keys              *encryption.KeyStore
+	encBlockSize      int
+	cipher            storj.Cipher
+	compression       storj.Compression
+	uploadConcurrency int
+	downloadPrefetch  int
 }
 
-// NewStreamStore stuff
-func NewStreamStore(segments segments.Store, segmentSize int64, rootKey *storj.Key, encBlockSize int, cipher storj.Cipher) (Store, error) {
+// NewStreamStore creates a store which splits streams into segments.
+//
+// uploadConcurrency is the number of segments uploaded in parallel and
+// downloadPrefetch is the number of segments fetched ahead of the one being
+// read. Values lower than 2 and 1 respectively disable the parallelism.
+func NewStreamStore(segments segments.Store, segmentSize int64, keys *encryption.KeyStore, encBlockSize int, cipher storj.Cipher, uploadConcurrency, downloadPrefetch int) (Store, error) {
 	if segmentSize <= 0 {
 		return nil, errs.New("segment size must be larger than 0")
 	}
-	if rootKey == nil {
+	if keys == nil {
 		return nil, errs.New("encryption key must not be empty")
 	}
 	if encBlockSize <= 0 {
@@ -85,14 +107,76 @@ func NewStreamStore(segments segments.Store, segmentSize int64, rootKey *storj.K
 	}
 
 	return &streamStore{
-		segments:     segments,
-		segmentSize:  segmentSize,
-		rootKey:      rootKey,
-		encBlockSize: encBlockSize,
-		cipher:       cipher,
+		segments:          segments,
+		segmentSize:       segmentSize,
+		keys:              keys,
+		encBlockSize:      encBlockSize,
+		cipher:            cipher,
+		uploadConcurrency: uploadConcurrency,
+		downloadPrefetch:  downloadPrefetch,
 	}, nil
 }
 
+// WithEncryption returns a store which encrypts the content of new streams
+// with the given scheme instead of the default one. Existing streams are
+// always decrypted with the scheme they were encrypted with.
+func (s *streamStore) WithEncryption(scheme storj.EncryptionScheme) Store {
+	if scheme.IsZero() {
+		return s
+	}
+
+	store := *s
+	store.cipher = scheme.Cipher
+	if scheme.BlockSize > 0 {
+		store.encBlockSize = int(scheme.BlockSize)
+	}
+	return &store
+}
+
+// WithRedundancy returns a store which uploads new streams with the given
+// redundancy scheme, segment size and inline threshold instead of the default
+// ones. Zero values keep the defaults.
+func (s *streamStore) WithRedundancy(scheme storj.RedundancyScheme, segmentSize int64, inlineThreshold int) (Store, error) {
+	store := *s
+
+	if !scheme.IsZero() || inlineThreshold > 0 {
+		segments, err := s.segments.WithRedundancy(scheme, inlineThreshold)
+		if err != nil {
+			return nil, err
+		}
+		store.segments = segments
+	}
+
+	if segmentSize > 0 {
+		store.segmentSize = segmentSize
+	}
+
+	return &store, nil
+}
+
+// WithCompression returns a store which compresses the content of new
+// streams before encrypting it. Existing streams are always decompressed
+// with the algorithm they were compressed with.
+func (s *streamStore) WithCompression(compression storj.Compression) (Store, error) {
+	if compression != storj.Uncompressed && compression != storj.Gzip {
+		return nil, errs.New("compression %d is not supported", compression)
+	}
+
+	store := *s
+	store.compression = compression
+	return &store, nil
+}
+
+// compress returns the reader of the content to upload, which compresses
+// data unless compression is disabled. The compressor is nil then.
+func (s *streamStore) compress(data io.Reader) (compressor *compressReader, content io.Reader) {
+	if s.compression == storj.Uncompressed {
+		return nil, data
+	}
+	compressor = newCompressReader(data, s.compression, compressionFrameSize)
+	return compressor, compressor
+}
+
 // Put breaks up data as it comes in into s.segmentSize length pieces, then
 // store the first piece at s0/<path>, second piece at s1/<path>, and the
 // *last* piece at l/<path>. Store the given metadata, along with the number
@@ -118,6 +202,10 @@ func (s *streamStore) Put(ctx context.Context, path storj.Path, pathCipher storj
 func (s *streamStore) upload(ctx context.Context, path storj.Path, pathCipher storj.Cipher, data io.Reader, metadata []byte, expiration time.Time) (m Meta, lastSegment int64, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	if s.uploadConcurrency > 1 {
+		return s.uploadParallel(ctx, path, pathCipher, data, metadata, expiration)
+	}
+
 	var currentSegment int64
 	var streamSize int64
 	var putMeta segments.Meta
@@ -130,152 +218,361 @@ func (s *streamStore) upload(ctx context.Context, path storj.Path, pathCipher st
 		}
 	}()
 
-	derivedKey, err := encryption.DeriveContentKey(path, s.rootKey)
+	rootKey, err := s.keys.RootKey(path)
 	if err != nil {
 		return Meta{}, currentSegment, err
 	}
 
-	eofReader := NewEOFReader(data)
+	derivedKey, err := encryption.DeriveContentKey(path, rootKey)
+	if err != nil {
+		return Meta{}, currentSegment, err
+	}
+
+	encPath, err := EncryptAfterBucket(path, pathCipher, rootKey)
+	if err != nil {
+		return Meta{}, currentSegment, err
+	}
+
+	checksumReader := NewChecksumReader(data)
+	compressor, content := s.compress(checksumReader)
+	eofReader := NewEOFReader(content)
 
 	for !eofReader.isEOF() && !eofReader.hasError() {
-		// generate random key for encrypting the segment's content
-		var contentKey storj.Key
-		_, err = rand.Read(contentKey[:])
-		if err != nil {
-			return Meta{}, currentSegment, err
-		}
+		sizeReader := NewSizeReader(eofReader)
+		segmentReader := io.LimitReader(sizeReader, s.segmentSize)
 
-		// Initialize the content nonce with the segment's index incremented by 1.
-		// The increment by 1 is to avoid nonce reuse with the metadata encryption,
-		// which is encrypted with the zero nonce.
-		var contentNonce storj.Nonce
-		_, err := encryption.Increment(&contentNonce, currentSegment+1)
+		segment, err := s.encryptSegment(currentSegment, derivedKey, segmentReader)
 		if err != nil {
 			return Meta{}, currentSegment, err
 		}
 
-		encrypter, err := encryption.NewEncrypter(s.cipher, &contentKey, &contentNonce, s.encBlockSize)
+		putMeta, err = s.segments.Put(ctx, segment.data, expiration, func() (storj.Path, []byte, error) {
+			if !eofReader.isEOF() {
+				return s.segmentInfo(segment, encPath)
+			}
+			return s.lastSegmentInfo(segment, encPath, sizeReader.Size(), metadata, checksumReader, compressor)
+		})
 		if err != nil {
 			return Meta{}, currentSegment, err
 		}
 
-		// generate random nonce for encrypting the content key
-		var keyNonce storj.Nonce
-		_, err = rand.Read(keyNonce[:])
-		if err != nil {
-			return Meta{}, currentSegment, err
-		}
+		currentSegment++
+		streamSize += sizeReader.Size()
+	}
+
+	if eofReader.hasError() {
+		return Meta{}, currentSegment, eofReader.err
+	}
+
+	if compressor != nil {
+		streamSize = compressor.size
+	}
+
+	resultMeta := Meta{
+		Modified:   putMeta.Modified,
+		Expiration: expiration,
+		Size:       streamSize,
+		Data:       metadata,
+		Checksum:   checksumReader.Checksum(),
+		ETag:       checksumReader.ETag(),
+	}
+
+	return resultMeta, currentSegment, nil
+}
+
+// uploadParallel reads the data into segment sized buffers and uploads up to
+// s.uploadConcurrency segments at the same time. The last segment, which
+// carries the stream metadata, is committed only after all other segments
+// have been uploaded successfully.
+func (s *streamStore) uploadParallel(ctx context.Context, path storj.Path, pathCipher storj.Cipher, data io.Reader, metadata []byte, expiration time.Time) (m Meta, lastSegment int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var currentSegment int64
+	var streamSize int64
+
+	rootKey, err := s.keys.RootKey(path)
+	if err != nil {
+		return Meta{}, currentSegment, err
+	}
+
+	derivedKey, err := encryption.DeriveContentKey(path, rootKey)
+	if err != nil {
+		return Meta{}, currentSegment, err
+	}
+
+	encPath, err := EncryptAfterBucket(path, pathCipher, rootKey)
+	if err != nil {
+		return Meta{}, currentSegment, err
+	}
+
+	uploadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	limiter := sync2.NewLimiter(s.uploadConcurrency)
+	var uploadErr utils.ErrorGroup
+	var uploadErrMu sync.Mutex
+
+	checksumReader := NewChecksumReader(data)
+	compressor, content := s.compress(checksumReader)
+
+	// readSegment reads the next segment sized chunk of the data
+	readSegment := func() ([]byte, bool, error) {
+		return readSegmentData(content, s.segmentSize)
+	}
 
-		encryptedKey, err := encryption.EncryptKey(&contentKey, s.cipher, derivedKey, &keyNonce)
+	current, eof, err := readSegment()
+	if err != nil {
+		return Meta{}, currentSegment, err
+	}
+
+	for !eof {
+		// read ahead to find out whether the current segment is the last one
+		next, nextEOF, err := readSegment()
 		if err != nil {
+			cancel()
+			limiter.Wait()
 			return Meta{}, currentSegment, err
 		}
+		if nextEOF && len(next) == 0 {
+			break
+		}
 
-		sizeReader := NewSizeReader(eofReader)
-		segmentReader := io.LimitReader(sizeReader, s.segmentSize)
-		peekReader := segments.NewPeekThresholdReader(segmentReader)
-		largeData, err := peekReader.IsLargerThan(encrypter.InBlockSize())
+		segment, err := s.encryptSegment(currentSegment, derivedKey, bytes.NewReader(current))
 		if err != nil {
+			cancel()
+			limiter.Wait()
 			return Meta{}, currentSegment, err
 		}
-		var transformedReader io.Reader
-		if largeData {
-			paddedReader := eestream.PadReader(ioutil.NopCloser(peekReader), encrypter.InBlockSize())
-			transformedReader = encryption.TransformReader(paddedReader, encrypter, 0)
-		} else {
-			data, err := ioutil.ReadAll(peekReader)
-			if err != nil {
-				return Meta{}, currentSegment, err
-			}
-			cipherData, err := encryption.Encrypt(data, s.cipher, &contentKey, &contentNonce)
+
+		started := limiter.Go(uploadCtx, func() {
+			_, err := s.segments.Put(uploadCtx, segment.data, expiration, func() (storj.Path, []byte, error) {
+				return s.segmentInfo(segment, encPath)
+			})
 			if err != nil {
-				return Meta{}, currentSegment, err
+				uploadErrMu.Lock()
+				uploadErr.Add(err)
+				uploadErrMu.Unlock()
+				cancel()
 			}
-			transformedReader = bytes.NewReader(cipherData)
+		})
+		if !started {
+			break
 		}
 
-		putMeta, err = s.segments.Put(ctx, transformedReader, expiration, func() (storj.Path, []byte, error) {
-			encPath, err := EncryptAfterBucket(path, pathCipher, s.rootKey)
-			if err != nil {
-				return "", nil, err
-			}
+		streamSize += int64(len(current))
+		currentSegment++
+		current, eof = next, nextEOF
+	}
 
-			if !eofReader.isEOF() {
-				segmentPath := getSegmentPath(encPath, currentSegment)
+	limiter.Wait()
+	if err := uploadErr.Finish(); err != nil {
+		return Meta{}, currentSegment, err
+	}
+	if err := ctx.Err(); err != nil {
+		return Meta{}, currentSegment, err
+	}
 
-				if s.cipher == storj.Unencrypted {
-					return segmentPath, nil, nil
-				}
+	segment, err := s.encryptSegment(currentSegment, derivedKey, bytes.NewReader(current))
+	if err != nil {
+		return Meta{}, currentSegment, err
+	}
 
-				segmentMeta, err := proto.Marshal(&pb.SegmentMeta{
-					EncryptedKey: encryptedKey,
-					KeyNonce:     keyNonce[:],
-				})
-				if err != nil {
-					return "", nil, err
-				}
+	putMeta, err := s.segments.Put(ctx, segment.data, expiration, func() (storj.Path, []byte, error) {
+		return s.lastSegmentInfo(segment, encPath, int64(len(current)), metadata, checksumReader, compressor)
+	})
+	if err != nil {
+		return Meta{}, currentSegment, err
+	}
 
-				return segmentPath, segmentMeta, nil
-			}
+	streamSize += int64(len(current))
+	if compressor != nil {
+		streamSize = compressor.size
+	}
 
-			lastSegmentPath := storj.JoinPaths("l", encPath)
+	resultMeta := Meta{
+		Modified:   putMeta.Modified,
+		Expiration: expiration,
+		Size:       streamSize,
+		Data:       metadata,
+		Checksum:   checksumReader.Checksum(),
+		ETag:       checksumReader.ETag(),
+	}
 
-			streamInfo, err := proto.Marshal(&pb.StreamInfo{
-				NumberOfSegments: currentSegment + 1,
-				SegmentsSize:     s.segmentSize,
-				LastSegmentSize:  sizeReader.Size(),
-				Metadata:         metadata,
-			})
-			if err != nil {
-				return "", nil, err
-			}
+	return resultMeta, currentSegment + 1, nil
+}
 
-			// encrypt metadata with the content encryption key and zero nonce
-			encryptedStreamInfo, err := encryption.Encrypt(streamInfo, s.cipher, &contentKey, &storj.Nonce{})
-			if err != nil {
-				return "", nil, err
-			}
+// initialSegmentBuffer is the size of the buffer a segment is first read into
+const initialSegmentBuffer = 32 * 1024
 
-			streamMeta := pb.StreamMeta{
-				EncryptedStreamInfo: encryptedStreamInfo,
-				EncryptionType:      int32(s.cipher),
-				EncryptionBlockSize: int32(s.encBlockSize),
+// readSegmentData reads up to size bytes of the data, and returns whether the
+// data ended. The buffer grows as the data comes in, so that small streams
+// don't hold a full segment in memory.
+func readSegmentData(data io.Reader, size int64) (buf []byte, eof bool, err error) {
+	capacity := int64(initialSegmentBuffer)
+	if capacity > size {
+		capacity = size
+	}
+	buf = make([]byte, 0, capacity)
+	for int64(len(buf)) < size {
+		if len(buf) == cap(buf) {
+			capacity = 2 * int64(cap(buf))
+			if capacity > size {
+				capacity = size
 			}
+			grown := make([]byte, len(buf), capacity)
+			copy(grown, buf)
+			buf = grown
+		}
+		n, err := data.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if err == io.EOF {
+			return buf, true, nil
+		}
+		if err != nil {
+			return buf, false, err
+		}
+	}
+	return buf, false, nil
+}
 
-			if s.cipher != storj.Unencrypted {
-				streamMeta.LastSegmentMeta = &pb.SegmentMeta{
-					EncryptedKey: encryptedKey,
-					KeyNonce:     keyNonce[:],
-				}
-			}
+// encryptedSegment is a segment prepared for uploading
+type encryptedSegment struct {
+	index        int64
+	contentKey   storj.Key
+	encryptedKey storj.EncryptedPrivateKey
+	keyNonce     storj.Nonce
+	data         io.Reader
+}
 
-			lastSegmentMeta, err := proto.Marshal(&streamMeta)
-			if err != nil {
-				return "", nil, err
-			}
+// encryptSegment generates the keys for the segment with the given index and
+// returns the segment with its data encrypted
+func (s *streamStore) encryptSegment(index int64, derivedKey *storj.Key, plain io.Reader) (*encryptedSegment, error) {
+	segment := &encryptedSegment{index: index}
 
-			return lastSegmentPath, lastSegmentMeta, nil
-		})
+	// generate random key for encrypting the segment's content
+	_, err := rand.Read(segment.contentKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize the content nonce with the segment's index incremented by 1.
+	// The increment by 1 is to avoid nonce reuse with the metadata encryption,
+	// which is encrypted with the zero nonce.
+	var contentNonce storj.Nonce
+	_, err = encryption.Increment(&contentNonce, index+1)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypter, err := encryption.NewEncrypter(s.cipher, &segment.contentKey, &contentNonce, s.encBlockSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// generate random nonce for encrypting the content key
+	_, err = rand.Read(segment.keyNonce[:])
+	if err != nil {
+		return nil, err
+	}
+
+	segment.encryptedKey, err = encryption.EncryptKey(&segment.contentKey, s.cipher, derivedKey, &segment.keyNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	peekReader := segments.NewPeekThresholdReader(plain)
+	largeData, err := peekReader.IsLargerThan(encrypter.InBlockSize())
+	if err != nil {
+		return nil, err
+	}
+	if largeData {
+		paddedReader := eestream.PadReader(ioutil.NopCloser(peekReader), encrypter.InBlockSize())
+		segment.data = encryption.TransformReader(paddedReader, encrypter, 0)
+	} else {
+		data, err := ioutil.ReadAll(peekReader)
 		if err != nil {
-			return Meta{}, currentSegment, err
+			return nil, err
+		}
+		cipherData, err := encryption.Encrypt(data, s.cipher, &segment.contentKey, &contentNonce)
+		if err != nil {
+			return nil, err
 		}
+		segment.data = bytes.NewReader(cipherData)
+	}
 
-		currentSegment++
-		streamSize += sizeReader.Size()
+	return segment, nil
+}
+
+// segmentInfo returns the path and the metadata of a segment, which is not
+// the last segment of the stream
+func (s *streamStore) segmentInfo(segment *encryptedSegment, encPath storj.Path) (storj.Path, []byte, error) {
+	segmentPath := getSegmentPath(encPath, segment.index)
+
+	if s.cipher == storj.Unencrypted {
+		return segmentPath, nil, nil
 	}
 
-	if eofReader.hasError() {
-		return Meta{}, currentSegment, eofReader.err
+	segmentMeta, err := proto.Marshal(&pb.SegmentMeta{
+		EncryptedKey: segment.encryptedKey,
+		KeyNonce:     segment.keyNonce[:],
+	})
+	if err != nil {
+		return "", nil, err
 	}
 
-	resultMeta := Meta{
-		Modified:   putMeta.Modified,
-		Expiration: expiration,
-		Size:       streamSize,
-		Data:       metadata,
+	return segmentPath, segmentMeta, nil
+}
+
+// lastSegmentInfo returns the path and the metadata of the last segment of
+// the stream, which includes the encrypted stream info. The checksums and the
+// compression frames are taken from checksumReader and compressor, which
+// must have read all the data already. compressor is nil for uncompressed
+// streams.
+func (s *streamStore) lastSegmentInfo(segment *encryptedSegment, encPath storj.Path, lastSegmentSize int64, metadata []byte, checksumReader *ChecksumReader, compressor *compressReader) (storj.Path, []byte, error) {
+	lastSegmentPath := storj.JoinPaths("l", encPath)
+
+	stream := pb.StreamInfo{
+		NumberOfSegments: segment.index + 1,
+		SegmentsSize:     s.segmentSize,
+		LastSegmentSize:  lastSegmentSize,
+		Metadata:         metadata,
+		Checksum:         checksumReader.Checksum(),
+		Etag:             checksumReader.ETag(),
+	}
+	if compressor != nil {
+		compressor.setInfo(&stream)
 	}
 
-	return resultMeta, currentSegment, nil
+	streamInfo, err := proto.Marshal(&stream)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// encrypt metadata with the content encryption key and zero nonce
+	encryptedStreamInfo, err := encryption.Encrypt(streamInfo, s.cipher, &segment.contentKey, &storj.Nonce{})
+	if err != nil {
+		return "", nil, err
+	}
+
+	streamMeta := pb.StreamMeta{
+		EncryptedStreamInfo: encryptedStreamInfo,
+		EncryptionType:      int32(s.cipher),
+		EncryptionBlockSize: int32(s.encBlockSize),
+	}
+
+	if s.cipher != storj.Unencrypted {
+		streamMeta.LastSegmentMeta = &pb.SegmentMeta{
+			EncryptedKey: segment.encryptedKey,
+			KeyNonce:     segment.keyNonce[:],
+		}
+	}
+
+	lastSegmentMeta, err := proto.Marshal(&streamMeta)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return lastSegmentPath, lastSegmentMeta, nil
 }
 
 // getSegmentPath returns the unique path for a particular segment
@@ -289,7 +586,12 @@ func getSegmentPath(path storj.Path, segNum int64) storj.Path {
 func (s *streamStore) Get(ctx context.Context, path storj.Path, pathCipher storj.Cipher) (rr ranger.Ranger, meta Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	encPath, err := EncryptAfterBucket(path, pathCipher, s.rootKey)
+	rootKey, err := s.keys.RootKey(path)
+	if err != nil {
+		return nil, Meta{}, err
+	}
+
+	encPath, err := EncryptAfterBucket(path, pathCipher, rootKey)
 	if err != nil {
 		return nil, Meta{}, err
 	}
@@ -299,7 +601,7 @@ func (s *streamStore) Get(ctx context.Context, path storj.Path, pathCipher storj
 		return nil, Meta{}, err
 	}
 
-	streamInfo, err := DecryptStreamInfo(ctx, lastSegmentMeta, path, s.rootKey)
+	streamInfo, err := DecryptStreamInfo(ctx, lastSegmentMeta, path, rootKey)
 	if err != nil {
 		return nil, Meta{}, err
 	}
@@ -316,7 +618,7 @@ func (s *streamStore) Get(ctx context.Context, path storj.Path, pathCipher storj
 		return nil, Meta{}, err
 	}
 
-	derivedKey, err := encryption.DeriveContentKey(path, s.rootKey)
+	derivedKey, err := encryption.DeriveContentKey(path, rootKey)
 	if err != nil {
 		return nil, Meta{}, err
 	}
@@ -364,7 +666,19 @@ func (s *streamStore) Get(ctx context.Context, path storj.Path, pathCipher storj
 	}
 	rangers = append(rangers, decryptedLastSegmentRanger)
 
-	catRangers := ranger.Concat(rangers...)
+	var catRangers ranger.Ranger
+	if s.downloadPrefetch > 0 {
+		catRangers = newPrefetchRanger(rangers, s.downloadPrefetch)
+	} else {
+		catRangers = ranger.Concat(rangers...)
+	}
+
+	if storj.Compression(stream.Compression) != storj.Uncompressed {
+		catRangers, err = newDecompressRanger(catRangers, &stream)
+		if err != nil {
+			return nil, Meta{}, err
+		}
+	}
 
 	lastSegmentMeta.Data = streamInfo
 	meta, err = convertMeta(lastSegmentMeta)
@@ -379,7 +693,12 @@ func (s *streamStore) Get(ctx context.Context, path storj.Path, pathCipher storj
 func (s *streamStore) Meta(ctx context.Context, path storj.Path, pathCipher storj.Cipher) (meta Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	encPath, err := EncryptAfterBucket(path, pathCipher, s.rootKey)
+	rootKey, err := s.keys.RootKey(path)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	encPath, err := EncryptAfterBucket(path, pathCipher, rootKey)
 	if err != nil {
 		return Meta{}, err
 	}
@@ -389,7 +708,7 @@ func (s *streamStore) Meta(ctx context.Context, path storj.Path, pathCipher stor
 		return Meta{}, err
 	}
 
-	streamInfo, err := DecryptStreamInfo(ctx, lastSegmentMeta, path, s.rootKey)
+	streamInfo, err := DecryptStreamInfo(ctx, lastSegmentMeta, path, rootKey)
 	if err != nil {
 		return Meta{}, err
 	}
@@ -407,7 +726,12 @@ func (s *streamStore) Meta(ctx context.Context, path storj.Path, pathCipher stor
 func (s *streamStore) Delete(ctx context.Context, path storj.Path, pathCipher storj.Cipher) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	encPath, err := EncryptAfterBucket(path, pathCipher, s.rootKey)
+	rootKey, err := s.keys.RootKey(path)
+	if err != nil {
+		return err
+	}
+
+	encPath, err := EncryptAfterBucket(path, pathCipher, rootKey)
 	if err != nil {
 		return err
 	}
@@ -416,7 +740,7 @@ func (s *streamStore) Delete(ctx context.Context, path storj.Path, pathCipher st
 		return err
 	}
 
-	streamInfo, err := DecryptStreamInfo(ctx, lastSegmentMeta, path, s.rootKey)
+	streamInfo, err := DecryptStreamInfo(ctx, lastSegmentMeta, path, rootKey)
 	if err != nil {
 		return err
 	}
@@ -428,7 +752,7 @@ func (s *streamStore) Delete(ctx context.Context, path storj.Path, pathCipher st
 	}
 
 	for i := 0; i < int(stream.NumberOfSegments-1); i++ {
-		encPath, err = EncryptAfterBucket(path, pathCipher, s.rootKey)
+		encPath, err = EncryptAfterBucket(path, pathCipher, rootKey)
 		if err != nil {
 			return err
 		}
@@ -461,22 +785,27 @@ func (s *streamStore) List(ctx context.Context, prefix, startAfter, endBefore st
 
 	prefix = strings.TrimSuffix(prefix, "/")
 
-	encPrefix, err := EncryptAfterBucket(prefix, pathCipher, s.rootKey)
+	rootKey, err := s.keys.RootKey(prefix)
 	if err != nil {
 		return nil, false, err
 	}
 
-	prefixKey, err := encryption.DerivePathKey(prefix, s.rootKey, len(storj.SplitPath(prefix)))
+	encPrefix, err := EncryptAfterBucket(prefix, pathCipher, rootKey)
 	if err != nil {
 		return nil, false, err
 	}
 
-	encStartAfter, err := s.encryptMarker(startAfter, pathCipher, prefixKey)
+	prefixKey, err := encryption.DerivePathKey(prefix, rootKey, len(storj.SplitPath(prefix)))
 	if err != nil {
 		return nil, false, err
 	}
 
-	encEndBefore, err := s.encryptMarker(endBefore, pathCipher, prefixKey)
+	encStartAfter, err := s.encryptMarker(startAfter, pathCipher, rootKey, prefixKey)
+	if err != nil {
+		return nil, false, err
+	}
+
+	encEndBefore, err := s.encryptMarker(endBefore, pathCipher, rootKey, prefixKey)
 	if err != nil {
 		return nil, false, err
 	}
@@ -488,12 +817,22 @@ func (s *streamStore) List(ctx context.Context, prefix, startAfter, endBefore st
 
 	items = make([]ListItem, len(segments))
 	for i, item := range segments {
-		path, err := s.decryptMarker(item.Path, pathCipher, prefixKey)
+		path, err := s.decryptMarker(item.Path, pathCipher, rootKey, prefixKey)
+		if err != nil {
+			return nil, false, err
+		}
+
+		fullPath := path
+		if prefix != "" {
+			fullPath = storj.JoinPaths(prefix, path)
+		}
+
+		itemKey, err := s.keys.RootKey(fullPath)
 		if err != nil {
 			return nil, false, err
 		}
 
-		streamInfo, err := DecryptStreamInfo(ctx, item.Meta, storj.JoinPaths(prefix, path), s.rootKey)
+		streamInfo, err := DecryptStreamInfo(ctx, item.Meta, fullPath, itemKey)
 		if err != nil {
 			return nil, false, err
 		}
@@ -511,17 +850,17 @@ func (s *streamStore) List(ctx context.Context, prefix, startAfter, endBefore st
 }
 
 // encryptMarker is a helper method for encrypting startAfter and endBefore markers
-func (s *streamStore) encryptMarker(marker storj.Path, pathCipher storj.Cipher, prefixKey *storj.Key) (storj.Path, error) {
-	if bytes.Equal(s.rootKey[:], prefixKey[:]) { // empty prefix
-		return EncryptAfterBucket(marker, pathCipher, s.rootKey)
+func (s *streamStore) encryptMarker(marker storj.Path, pathCipher storj.Cipher, rootKey, prefixKey *storj.Key) (storj.Path, error) {
+	if bytes.Equal(rootKey[:], prefixKey[:]) { // empty prefix
+		return EncryptAfterBucket(marker, pathCipher, rootKey)
 	}
 	return encryption.EncryptPath(marker, pathCipher, prefixKey)
 }
 
 // decryptMarker is a helper method for decrypting listed path markers
-func (s *streamStore) decryptMarker(marker storj.Path, pathCipher storj.Cipher, prefixKey *storj.Key) (storj.Path, error) {
-	if bytes.Equal(s.rootKey[:], prefixKey[:]) { // empty prefix
-		return DecryptAfterBucket(marker, pathCipher, s.rootKey)
+func (s *streamStore) decryptMarker(marker storj.Path, pathCipher storj.Cipher, rootKey, prefixKey *storj.Key) (storj.Path, error) {
+	if bytes.Equal(rootKey[:], prefixKey[:]) { // empty prefix
+		return DecryptAfterBucket(marker, pathCipher, rootKey)
 	}
 	return encryption.DecryptPath(marker, pathCipher, prefixKey)
 }
@@ -640,8 +979,14 @@ func DecryptAfterBucket(path storj.Path, cipher storj.Cipher, key *storj.Key) (d
 
 // CancelHandler handles clean up of segments on receiving CTRL+C
 func (s *streamStore) cancelHandler(ctx context.Context, totalSegments int64, path storj.Path, pathCipher storj.Cipher) {
+	rootKey, err := s.keys.RootKey(path)
+	if err != nil {
+		zap.S().Warnf("Failed deleting segments due to encryption key %v", err)
+		return
+	}
+
 	for i := int64(0); i < totalSegments; i++ {
-		encPath, err := EncryptAfterBucket(path, pathCipher, s.rootKey)
+		encPath, err := EncryptAfterBucket(path, pathCipher, rootKey)
 		if err != nil {
 			zap.S().Warnf("Failed deleting a segment due to encryption path %v %v", i, err)
 		}
@@ -5,6 +5,7 @@ package streams
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"storj.io/storj/pkg/encryption"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/ranger"
 	"storj.io/storj/pkg/storage/segments"
@@ -91,7 +93,7 @@ func TestStreamStoreMeta(t *testing.T) {
 			Meta(gomock.Any(), gomock.Any()).
 			Return(test.segmentMeta, test.segmentError)
 
-		streamStore, err := NewStreamStore(mockSegmentStore, 10, new(storj.Key), 10, storj.AESGCM)
+		streamStore, err := NewStreamStore(mockSegmentStore, 10, encryption.NewKeyStore(new(storj.Key), false), 10, storj.AESGCM, 0, 0)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -119,11 +121,16 @@ func TestStreamStorePut(t *testing.T) {
 		Data:       []byte{},
 	}
 
+	// SHA-256 and MD5 hashes of "data"
+	checksum, _ := hex.DecodeString("3a6eb0790f39ac87c94f3856b2dd2c5d110e6811602261a9a923d3bb23adc8b7")
+
 	streamMeta := Meta{
 		Modified:   segmentMeta.Modified,
 		Expiration: segmentMeta.Expiration,
 		Size:       4,
 		Data:       []byte("metadata"),
+		Checksum:   checksum,
+		ETag:       "8d777f385d3dfec8815d20f7496026dc",
 	}
 
 	for i, test := range []struct {
@@ -163,7 +170,7 @@ func TestStreamStorePut(t *testing.T) {
 			Delete(gomock.Any(), gomock.Any()).
 			Return(test.segmentError)
 
-		streamStore, err := NewStreamStore(mockSegmentStore, 10, new(storj.Key), 10, 0)
+		streamStore, err := NewStreamStore(mockSegmentStore, 10, encryption.NewKeyStore(new(storj.Key), false), 10, 0, 0, 0)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -263,7 +270,7 @@ func TestStreamStoreGet(t *testing.T) {
 
 		gomock.InOrder(calls...)
 
-		streamStore, err := NewStreamStore(mockSegmentStore, 10, new(storj.Key), 10, 0)
+		streamStore, err := NewStreamStore(mockSegmentStore, 10, encryption.NewKeyStore(new(storj.Key), false), 10, 0, 0, 0)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -312,7 +319,7 @@ func TestStreamStoreDelete(t *testing.T) {
 			Delete(gomock.Any(), gomock.Any()).
 			Return(test.segmentError)
 
-		streamStore, err := NewStreamStore(mockSegmentStore, 10, new(storj.Key), 10, 0)
+		streamStore, err := NewStreamStore(mockSegmentStore, 10, encryption.NewKeyStore(new(storj.Key), false), 10, 0, 0, 0)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -356,7 +363,7 @@ func TestStreamStoreList(t *testing.T) {
 			List(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(test.segments, test.segmentMore, test.segmentError)
 
-		streamStore, err := NewStreamStore(mockSegmentStore, 10, new(storj.Key), 10, 0)
+		streamStore, err := NewStreamStore(mockSegmentStore, 10, encryption.NewKeyStore(new(storj.Key), false), 10, 0, 0, 0)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -0,0 +1,25 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storj
+
+// Compression specifies a compression algorithm
+type Compression byte
+
+// List of supported compression algorithms
+const (
+	Uncompressed = Compression(iota)
+	Gzip
+)
+
+// String returns the name of the compression algorithm
+func (compression Compression) String() string {
+	switch compression {
+	case Uncompressed:
+		return "none"
+	case Gzip:
+		return "gzip"
+	default:
+		return "unknown"
+	}
+}
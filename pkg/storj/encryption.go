@@ -22,6 +22,7 @@ const (
 	Unencrypted = Cipher(iota)
 	AESGCM
 	SecretBox
+	XChaCha20Poly1305
 )
 
 // Constant definitions for key and nonce sizes
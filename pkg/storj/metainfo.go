@@ -51,6 +51,10 @@ type CreateObject struct {
 
 	RedundancyScheme
 	EncryptionScheme
+	Compression Compression
+	// DisableCompression uploads the object uncompressed even if its bucket
+	// compresses new objects by default
+	DisableCompression bool
 }
 
 // Object converts the CreateObject to an object with unitialized values
@@ -69,6 +73,7 @@ func (create CreateObject) Object(bucket Bucket, path Path) Object {
 
 			RedundancyScheme: create.RedundancyScheme,
 			EncryptionScheme: create.EncryptionScheme,
+			Compression:      create.Compression,
 		},
 	}
 }
@@ -88,6 +93,11 @@ const (
 )
 
 // ListOptions lists objects
+//
+// The paths are collapsed into prefixes at the first Delimiter after Prefix,
+// unless Recursive is set. If Delimiter is zero, Prefix is a directory and the
+// paths collapse on '/'. Otherwise Prefix is matched as a plain string prefix
+// of the paths and the items are listed in lexical order of the paths.
 type ListOptions struct {
 	Prefix    Path
 	Cursor    Path // Cursor is relative to Prefix, full path is Prefix + Cursor
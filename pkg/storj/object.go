@@ -4,6 +4,7 @@
 package storj
 
 import (
+	"strings"
 	"time"
 
 	"github.com/zeebo/errs"
@@ -28,6 +29,45 @@ type Bucket struct {
 	Name       string
 	Created    time.Time
 	PathCipher Cipher
+
+	// SegmentsSize is the default maximum size of the segments of new objects
+	SegmentsSize int64
+	// InlineThreshold is the default maximum size of inline segments of new objects
+	InlineThreshold int
+	// RedundancyScheme is the default redundancy scheme of new objects
+	RedundancyScheme RedundancyScheme
+	// EncryptionScheme is the default encryption scheme of new objects
+	EncryptionScheme EncryptionScheme
+	// Compression is the default compression of new objects
+	Compression Compression
+
+	// Lifecycle are the rules for expiring new objects
+	Lifecycle []LifecycleRule
+}
+
+// LifecycleRule expires the objects under Prefix the given number of Days
+// after their creation
+type LifecycleRule struct {
+	ID     string
+	Prefix Path
+	Days   int
+}
+
+// Expiration returns the expiration time of the object at path created at
+// created according to the lifecycle rules of the bucket. The earliest
+// expiration wins if several rules match and it is zero if none does.
+func (bucket Bucket) Expiration(path Path, created time.Time) time.Time {
+	var expiration time.Time
+	for _, rule := range bucket.Lifecycle {
+		if rule.Days <= 0 || !strings.HasPrefix(path, rule.Prefix) {
+			continue
+		}
+		expires := created.AddDate(0, 0, rule.Days)
+		if expiration.IsZero() || expires.Before(expiration) {
+			expiration = expires
+		}
+	}
+	return expiration
 }
 
 // Object contains information about a specific object
@@ -51,8 +91,10 @@ type Object struct {
 type Stream struct {
 	// Size is the total size of the stream in bytes
 	Size int64
-	// Checksum is the checksum of the segment checksums
+	// Checksum is the SHA-256 hash of the content
 	Checksum []byte
+	// ETag is the S3 compatible entity tag of the content
+	ETag string
 
 	// SegmentCount is the number of segments
 	SegmentCount int64
@@ -64,6 +106,8 @@ type Stream struct {
 	RedundancyScheme
 	// EncryptionScheme specifies encryption strategy used for this stream
 	EncryptionScheme
+	// Compression is the algorithm compressing the content before encryption
+	Compression Compression
 
 	LastSegment LastSegment // TODO: remove
 }
@@ -0,0 +1,40 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storj
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketExpiration(t *testing.T) {
+	created := time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC)
+	bucket := Bucket{
+		Lifecycle: []LifecycleRule{
+			{Prefix: "logs/", Days: 30},
+			{Prefix: "logs/debug/", Days: 7},
+			{Prefix: "tmp/", Days: 1},
+			{Prefix: "", Days: 0},
+		},
+	}
+
+	for i, tt := range []struct {
+		path    Path
+		expires time.Time
+	}{
+		{"data/a", time.Time{}},
+		{"logs/a", created.AddDate(0, 0, 30)},
+		{"logs/debug/a", created.AddDate(0, 0, 7)},
+		{"tmp/a", created.AddDate(0, 0, 1)},
+		{"tmp", time.Time{}},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		assert.Equal(t, tt.expires, bucket.Expiration(tt.path, created), errTag)
+	}
+
+	assert.True(t, Bucket{}.Expiration("a", created).IsZero())
+}
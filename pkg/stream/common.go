@@ -9,3 +9,6 @@ import (
 
 // Error is the errs class of stream errors
 var Error = errs.Class("stream error")
+
+// ErrChecksum is the errs class of content checksum mismatches
+var ErrChecksum = errs.Class("checksum mismatch")
@@ -4,7 +4,10 @@
 package stream
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"hash"
 	"io"
 
 	"storj.io/storj/pkg/storage/streams"
@@ -19,6 +22,9 @@ type Download struct {
 	reader  io.ReadCloser
 	offset  int64
 	closed  bool
+	// checksum hashes the content while it is read from the beginning of
+	// the stream without seeking, nil otherwise
+	checksum hash.Hash
 }
 
 // NewDownload creates new stream download.
@@ -35,6 +41,10 @@ func NewDownload(ctx context.Context, stream storj.ReadOnlyStream, streams strea
 // If this is the first call it will read from the beginning of the stream.
 // Use Seek to change the current offset for the next Read call.
 //
+// When the whole stream is read from its beginning, the checksum of the
+// content is verified and an ErrChecksum error is returned instead of io.EOF
+// on mismatch.
+//
 // See io.Reader for more details.
 func (download *Download) Read(data []byte) (n int, err error) {
 	if download.closed {
@@ -52,9 +62,35 @@ func (download *Download) Read(data []byte) (n int, err error) {
 
 	download.offset += int64(n)
 
+	if download.checksum != nil {
+		_, _ = download.checksum.Write(data[:n])
+		if err == io.EOF {
+			if verifyErr := download.verify(); verifyErr != nil {
+				return n, verifyErr
+			}
+		}
+	}
+
 	return n, err
 }
 
+// verify compares the checksum of the read content with the one stored with
+// the stream. Streams uploaded without a checksum are not verified.
+func (download *Download) verify() error {
+	expected := download.stream.Info().Checksum
+	if len(expected) == 0 {
+		return nil
+	}
+
+	actual := download.checksum.Sum(nil)
+	download.checksum = nil
+
+	if !bytes.Equal(expected, actual) {
+		return ErrChecksum.New("expected %x, got %x", expected, actual)
+	}
+	return nil
+}
+
 // Seek changes the offset for the next Read call.
 //
 // See io.Seeker for more details.
@@ -118,5 +154,10 @@ func (download *Download) resetReader(offset int64) error {
 
 	download.offset = offset
 
+	download.checksum = nil
+	if offset == 0 {
+		download.checksum = sha256.New()
+	}
+
 	return nil
 }
@@ -21,8 +21,9 @@ type Upload struct {
 	ctx      context.Context
 	stream   storj.MutableStream
 	streams  streams.Store
-	writer   io.WriteCloser
+	writer   *io.PipeWriter
 	closed   bool
+	etag     string
 	errgroup errgroup.Group
 }
 
@@ -49,7 +50,32 @@ func NewUpload(ctx context.Context, stream storj.MutableStream, streams streams.
 			return utils.CombineErrors(err, reader.CloseWithError(err))
 		}
 
-		_, err = streams.Put(ctx, storj.JoinPaths(obj.Bucket.Name, obj.Path), obj.Bucket.PathCipher, reader, metadata, obj.Expires)
+		if !obj.EncryptionScheme.IsZero() {
+			streams = streams.WithEncryption(obj.EncryptionScheme)
+		}
+
+		// buckets with a default redundancy scheme upload with the scheme of
+		// the object, otherwise the scheme of the store is used
+		bucket := obj.Bucket
+		if !bucket.RedundancyScheme.IsZero() || bucket.SegmentsSize > 0 || bucket.InlineThreshold > 0 {
+			var redundancy storj.RedundancyScheme
+			if !bucket.RedundancyScheme.IsZero() {
+				redundancy = obj.RedundancyScheme
+			}
+			streams, err = streams.WithRedundancy(redundancy, bucket.SegmentsSize, bucket.InlineThreshold)
+			if err != nil {
+				return utils.CombineErrors(err, reader.CloseWithError(err))
+			}
+		}
+
+		if obj.Compression != storj.Uncompressed {
+			streams, err = streams.WithCompression(obj.Compression)
+			if err != nil {
+				return utils.CombineErrors(err, reader.CloseWithError(err))
+			}
+		}
+
+		_, err = streams.Put(ctx, storj.JoinPaths(obj.Bucket.Name, obj.Path), obj.Bucket.PathCipher, &etagReader{reader, &upload}, metadata, obj.Expires)
 		if err != nil {
 			return utils.CombineErrors(err, reader.CloseWithError(err))
 		}
@@ -71,6 +97,12 @@ func (upload *Upload) Write(data []byte) (n int, err error) {
 	return upload.writer.Write(data)
 }
 
+// SetETag overrides the S3 compatible ETag of the uploaded data, which is the
+// hex encoded MD5 hash of the data by default. It must be called before Close.
+func (upload *Upload) SetETag(etag string) {
+	upload.etag = etag
+}
+
 // Close closes the stream and releases the underlying resources.
 func (upload *Upload) Close() error {
 	if upload.closed {
@@ -84,3 +116,30 @@ func (upload *Upload) Close() error {
 	// Wait for streams.Put to commit the upload to the PointerDB
 	return utils.CombineErrors(err, upload.errgroup.Wait())
 }
+
+// Abort stops the upload without committing the data written so far. The
+// uploaded segments are deleted by streams.Put when it fails with cause.
+func (upload *Upload) Abort(cause error) error {
+	if upload.closed {
+		return Error.New("already closed")
+	}
+
+	upload.closed = true
+
+	err := upload.writer.CloseWithError(cause)
+
+	// streams.Put returns cause, as it can't read the rest of the data
+	_ = upload.errgroup.Wait()
+	return err
+}
+
+// etagReader provides the ETag set on the upload to the streams store
+type etagReader struct {
+	io.Reader
+	upload *Upload
+}
+
+// ETag implements streams.ETagger
+func (reader *etagReader) ETag() string {
+	return reader.upload.etag
+}
@@ -0,0 +1,196 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package transport
+
+import (
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+// BreakerError is the errs class of the requests rejected because the
+// circuit breaker of the node is open
+var BreakerError = errs.Class("circuit breaker open")
+
+// the states of a circuit breaker
+const (
+	// breakerClosed lets the requests to the node through
+	breakerClosed = iota
+	// breakerOpen rejects the requests to the node until the cooldown ends
+	breakerOpen
+	// breakerHalfOpen lets a single request through to probe the node
+	breakerHalfOpen
+)
+
+// Breakers keeps a circuit breaker for every node failing recently. After
+// a number of consecutive failures the breaker of a node opens and the
+// requests to the node are rejected. Once the cooldown ends a single request
+// is let through, which closes the breaker when it succeeds and opens it
+// again when it fails. A node moving to another address gets a new breaker.
+type Breakers struct {
+	failures int
+	cooldown time.Duration
+
+	mu    sync.Mutex
+	nodes map[breakerKey]*breaker
+	open  int
+}
+
+// breakerKey identifies a node by its ID and address
+type breakerKey struct {
+	id      storj.NodeID
+	address string
+}
+
+func keyOf(node *pb.Node) breakerKey {
+	return breakerKey{id: node.Id, address: node.GetAddress().GetAddress()}
+}
+
+type breaker struct {
+	state    int
+	failures int
+	opened   time.Time
+}
+
+var (
+	defaultBreakersOnce sync.Once
+	defaultBreakers     *Breakers
+)
+
+// DefaultBreakers returns the breakers shared by the clients of the process,
+// configured as the process
+func DefaultBreakers() *Breakers {
+	defaultBreakersOnce.Do(func() {
+		config := DefaultConfig().Breaker
+		defaultBreakers = NewBreakers(config.Failures, config.Cooldown)
+	})
+	return defaultBreakers
+}
+
+// NewBreakers returns Breakers opening after failures consecutive failures
+// of a node, for cooldown
+func NewBreakers(failures int, cooldown time.Duration) *Breakers {
+	return &Breakers{
+		failures: failures,
+		cooldown: cooldown,
+		nodes:    make(map[breakerKey]*breaker),
+	}
+}
+
+// Available returns whether requests to the node may be let through
+func (breakers *Breakers) Available(node *pb.Node) bool {
+	breakers.mu.Lock()
+	defer breakers.mu.Unlock()
+
+	b, ok := breakers.nodes[keyOf(node)]
+	if !ok {
+		return true
+	}
+	switch b.state {
+	case breakerOpen:
+		return time.Since(b.opened) >= breakers.cooldown
+	case breakerHalfOpen:
+		return false
+	}
+	return true
+}
+
+// Allow returns whether a request to the node is let through. The result of
+// an allowed request has to be reported with Success or Failure.
+func (breakers *Breakers) Allow(node *pb.Node) bool {
+	breakers.mu.Lock()
+	defer breakers.mu.Unlock()
+
+	b, ok := breakers.nodes[keyOf(node)]
+	if !ok {
+		return true
+	}
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.opened) < breakers.cooldown {
+			mon.Event("breaker_rejected")
+			return false
+		}
+		// probe the node
+		b.state = breakerHalfOpen
+		breakers.open--
+		mon.Event("breaker_half_open")
+		mon.IntVal("breaker_open_nodes").Observe(int64(breakers.open))
+		return true
+	case breakerHalfOpen:
+		mon.Event("breaker_rejected")
+		return false
+	}
+	return true
+}
+
+// Success reports a successful request to the node
+func (breakers *Breakers) Success(node *pb.Node) {
+	breakers.mu.Lock()
+	defer breakers.mu.Unlock()
+
+	b, ok := breakers.nodes[keyOf(node)]
+	if !ok {
+		return
+	}
+	if b.state == breakerOpen {
+		breakers.open--
+		mon.IntVal("breaker_open_nodes").Observe(int64(breakers.open))
+	}
+	if b.state != breakerClosed {
+		mon.Event("breaker_closed")
+	}
+	delete(breakers.nodes, keyOf(node))
+}
+
+// Failure reports a failed request to the node
+func (breakers *Breakers) Failure(node *pb.Node) {
+	breakers.mu.Lock()
+	defer breakers.mu.Unlock()
+
+	b, ok := breakers.nodes[keyOf(node)]
+	if !ok {
+		b = &breaker{}
+		breakers.nodes[keyOf(node)] = b
+	}
+	b.failures++
+	mon.Event("breaker_failure")
+
+	switch b.state {
+	case breakerOpen:
+		// a request let through after the cooldown failed
+		b.opened = time.Now()
+		return
+	case breakerClosed:
+		if b.failures < breakers.failures {
+			return
+		}
+	}
+
+	b.state = breakerOpen
+	b.opened = time.Now()
+	breakers.open++
+	mon.Event("breaker_open")
+	mon.IntVal("breaker_open_nodes").Observe(int64(breakers.open))
+}
+
+// Abort reports a request to the node abandoned before it was known whether
+// the node failed
+func (breakers *Breakers) Abort(node *pb.Node) {
+	breakers.mu.Lock()
+	defer breakers.mu.Unlock()
+
+	b, ok := breakers.nodes[keyOf(node)]
+	if !ok || b.state != breakerHalfOpen {
+		return
+	}
+	// let another request probe the node
+	b.state = breakerOpen
+	breakers.open++
+	mon.IntVal("breaker_open_nodes").Observe(int64(breakers.open))
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package transport_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/transport"
+)
+
+func TestBreakers(t *testing.T) {
+	const cooldown = 50 * time.Millisecond
+	breakers := transport.NewBreakers(2, cooldown)
+	failing := &pb.Node{Id: storj.NodeID{1}, Address: &pb.NodeAddress{Address: "127.0.0.1:1"}}
+	other := &pb.Node{Id: storj.NodeID{2}, Address: &pb.NodeAddress{Address: "127.0.0.1:2"}}
+	moved := &pb.Node{Id: failing.Id, Address: &pb.NodeAddress{Address: "127.0.0.1:3"}}
+
+	// a single failure keeps the breaker closed
+	breakers.Failure(failing)
+	assert.True(t, breakers.Allow(failing))
+
+	// a success resets the failures
+	breakers.Success(failing)
+	breakers.Failure(failing)
+	assert.True(t, breakers.Available(failing))
+
+	// consecutive failures open the breaker
+	breakers.Failure(failing)
+	assert.False(t, breakers.Available(failing))
+	assert.False(t, breakers.Allow(failing))
+	assert.True(t, breakers.Allow(other))
+	assert.True(t, breakers.Allow(moved))
+
+	// a single request probes the node after the cooldown
+	time.Sleep(cooldown)
+	assert.True(t, breakers.Available(failing))
+	assert.True(t, breakers.Allow(failing))
+	assert.False(t, breakers.Allow(failing))
+
+	// a failed probe opens the breaker again
+	breakers.Failure(failing)
+	assert.False(t, breakers.Allow(failing))
+
+	// an abandoned probe lets another request probe the node
+	time.Sleep(cooldown)
+	assert.True(t, breakers.Allow(failing))
+	breakers.Abort(failing)
+	assert.True(t, breakers.Allow(failing))
+
+	// a successful probe closes the breaker
+	breakers.Success(failing)
+	assert.True(t, breakers.Allow(failing))
+	assert.True(t, breakers.Allow(failing))
+}
+
+func TestDialNodeBreakerOpen(t *testing.T) {
+	breakers := transport.NewBreakers(1, time.Hour)
+	client := transport.NewCustomClient(nil, transport.DefaultConfig(), breakers, nil)
+
+	node := &pb.Node{Id: storj.NodeID{1}, Address: &pb.NodeAddress{
+		Transport: pb.NodeTransport_TCP_TLS_GRPC,
+		Address:   "127.0.0.1:100",
+	}}
+	breakers.Failure(node)
+
+	conn, err := client.DialNode(context.Background(), node)
+	assert.True(t, transport.BreakerError.Has(err))
+	assert.Nil(t, conn)
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package transport
+
+import (
+	"flag"
+	"time"
+
+	"storj.io/storj/pkg/cfgstruct"
+)
+
+// Config is the configuration of the connections to nodes
+type Config struct {
+	DialTimeout    time.Duration `help:"time to wait for a connection to be established" default:"20s"`
+	RequestTimeout time.Duration `help:"time to wait for a response to a request to a node, 0 waits forever" default:"1m"`
+	Retries        int           `help:"how many times failed idempotent requests to a node are retried" default:"3"`
+	RetryBackoff   time.Duration `help:"time to wait before the first retry, doubling for every following retry" default:"100ms"`
+	Breaker        BreakerConfig
+	Pool           PoolConfig
+}
+
+// BreakerConfig is the configuration of the circuit breakers of the nodes
+type BreakerConfig struct {
+	Failures int           `help:"consecutive failures of a node after which the requests to it are rejected" default:"5"`
+	Cooldown time.Duration `help:"time the requests to a failing node are rejected before probing it again" default:"1m"`
+}
+
+// PoolConfig is the configuration of the pool of connections to nodes
+type PoolConfig struct {
+	Capacity    int           `help:"maximum number of connections to nodes kept open" default:"100"`
+	IdleTimeout time.Duration `help:"time after which an unused connection to a node is closed" default:"5m"`
+}
+
+// defaultConfig is the configuration of the clients, breakers and pool of
+// the process, holding the defaults until Configure is called
+var defaultConfig Config
+
+func init() {
+	cfgstruct.Bind(flag.NewFlagSet("transport", flag.PanicOnError), &defaultConfig)
+}
+
+// Configure sets the configuration of the clients, breakers and pool of the
+// process. It has to be called before any of them is used.
+func Configure(config Config) {
+	defaultConfig = config
+}
+
+// DefaultConfig returns the configuration of the clients, breakers and pool
+// of the process
+func DefaultConfig() Config {
+	return defaultConfig
+}
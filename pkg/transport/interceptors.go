@@ -0,0 +1,198 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package transport
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+)
+
+// idempotent are the methods of the node services that are safe to retry
+var idempotent = map[string]bool{
+	"/overlay.Nodes/Query":                      true,
+	"/overlay.Nodes/Ping":                       true,
+	"/piecestoreroutes.PieceStoreRoutes/Piece":  true,
+	"/piecestoreroutes.PieceStoreRoutes/Delete": true,
+	"/piecestoreroutes.PieceStoreRoutes/Stats":  true,
+}
+
+// unaryInterceptor bounds the requests to the node with the request timeout,
+// retries the idempotent ones and reports their results to the breakers
+func (transport *Transport) unaryInterceptor(node *pb.Node) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) (err error) {
+		attempts := 1
+		if idempotent[method] {
+			attempts += transport.config.Retries
+		}
+
+		backoff := transport.config.RetryBackoff
+		for attempt := 0; attempt < attempts; attempt++ {
+			if attempt > 0 {
+				mon.Event("request_retry")
+				select {
+				case <-time.After(backoff):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				backoff *= 2
+			}
+
+			if !transport.breakers.Allow(node) {
+				return BreakerError.New("node %s", node.Id)
+			}
+
+			callCtx, cancel := ctx, func() {}
+			if transport.config.RequestTimeout > 0 {
+				callCtx, cancel = context.WithTimeout(ctx, transport.config.RequestTimeout)
+			}
+			err = invoker(callCtx, method, req, reply, cc, opts...)
+			cancel()
+
+			if !transport.report(ctx, node, err) {
+				return err
+			}
+		}
+		return err
+	}
+}
+
+// streamInterceptor reports the results of opening streams to the node to
+// the breakers, and cancels the streams the node doesn't respond to within
+// the request timeout
+func (transport *Transport) streamInterceptor(node *pb.Node) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if !transport.breakers.Allow(node) {
+			return nil, BreakerError.New("node %s", node.Id)
+		}
+		if transport.config.RequestTimeout <= 0 {
+			stream, err := streamer(ctx, desc, cc, method, opts...)
+			transport.report(ctx, node, err)
+			return stream, err
+		}
+
+		streamCtx, cancel := context.WithCancel(ctx)
+		timed := &timedStream{
+			timeout:       transport.config.RequestTimeout,
+			serverStreams: desc.ServerStreams,
+		}
+		timed.timer = time.AfterFunc(timed.timeout, func() {
+			if atomic.CompareAndSwapInt32(&timed.state, streamWaiting, streamTimedOut) {
+				transport.breakers.Failure(node)
+				cancel()
+			}
+		})
+
+		stream, err := streamer(streamCtx, desc, cc, method, opts...)
+		if err != nil {
+			timed.stop()
+			cancel()
+			if timed.timedOut() {
+				return nil, errTimedOut
+			}
+			transport.report(ctx, node, err)
+			return nil, err
+		}
+		transport.report(ctx, node, nil)
+
+		timed.ClientStream = stream
+		timed.cancel = cancel
+		return timed, nil
+	}
+}
+
+// the states of a timedStream
+const (
+	// streamWaiting waits for the node to respond
+	streamWaiting = iota
+	// streamResponded has received the first message from the node
+	streamResponded
+	// streamTimedOut was canceled because the node didn't respond in time
+	streamTimedOut
+)
+
+// errTimedOut is the error of the streams canceled by the request timeout
+var errTimedOut = status.Error(codes.DeadlineExceeded, "node did not respond within the request timeout")
+
+// timedStream is a stream canceled when the node doesn't respond within the
+// timeout, until the first message from the node arrives. Every message sent
+// restarts the timeout, so that long uploads waiting for a single response
+// at the end aren't canceled while the node keeps accepting them.
+type timedStream struct {
+	grpc.ClientStream
+	timeout       time.Duration
+	serverStreams bool
+	timer         *time.Timer
+	cancel        func()
+	state         int32
+}
+
+// stop stops the timer once the node responded
+func (stream *timedStream) stop() {
+	if atomic.CompareAndSwapInt32(&stream.state, streamWaiting, streamResponded) {
+		stream.timer.Stop()
+	}
+}
+
+// timedOut returns whether the stream was canceled by the timeout
+func (stream *timedStream) timedOut() bool {
+	return atomic.LoadInt32(&stream.state) == streamTimedOut
+}
+
+// SendMsg sends m to the node, restarting the timeout
+func (stream *timedStream) SendMsg(m interface{}) error {
+	err := stream.ClientStream.SendMsg(m)
+	if stream.timedOut() {
+		return errTimedOut
+	}
+	if err == nil && atomic.LoadInt32(&stream.state) == streamWaiting {
+		stream.timer.Reset(stream.timeout)
+	}
+	return err
+}
+
+// RecvMsg receives a message from the node into m, stopping the timeout
+func (stream *timedStream) RecvMsg(m interface{}) error {
+	err := stream.ClientStream.RecvMsg(m)
+	if stream.timedOut() {
+		return errTimedOut
+	}
+	stream.stop()
+	if err != nil || !stream.serverStreams {
+		// the stream ended
+		stream.cancel()
+	}
+	return err
+}
+
+// report reports the result of a request to the node to the breakers, and
+// returns whether it failed because of the node
+func (transport *Transport) report(ctx context.Context, node *pb.Node, err error) (failed bool) {
+	if err == nil {
+		transport.breakers.Success(node)
+		return false
+	}
+	if ctx.Err() != nil {
+		// the caller gave up, the node may be fine
+		transport.breakers.Abort(node)
+		return false
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		transport.breakers.Failure(node)
+		return true
+	}
+	// the node responded
+	transport.breakers.Success(node)
+	return false
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+// silentStream is a stream to a node accepting the messages sent to it
+// without ever responding
+type silentStream struct {
+	grpc.ClientStream
+	ctx context.Context
+}
+
+func (stream *silentStream) SendMsg(m interface{}) error { return stream.ctx.Err() }
+
+func (stream *silentStream) RecvMsg(m interface{}) error {
+	<-stream.ctx.Done()
+	return status.Error(codes.Canceled, stream.ctx.Err().Error())
+}
+
+func TestStreamRequestTimeout(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	const timeout = 100 * time.Millisecond
+	config := DefaultConfig()
+	config.RequestTimeout = timeout
+	breakers := NewBreakers(1, time.Hour)
+	transport := &Transport{config: config, breakers: breakers}
+
+	node := &pb.Node{Id: storj.NodeID{1}, Address: &pb.NodeAddress{Address: "127.0.0.1:1"}}
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &silentStream{ctx: ctx}, nil
+	}
+	desc := &grpc.StreamDesc{ClientStreams: true}
+
+	stream, err := transport.streamInterceptor(node)(ctx, desc, nil, "/test", streamer)
+	require.NoError(t, err)
+
+	// sending restarts the timeout
+	for i := 0; i < 4; i++ {
+		time.Sleep(timeout / 2)
+		require.NoError(t, stream.SendMsg(nil))
+	}
+
+	// the node never responds
+	start := time.Now()
+	err = stream.RecvMsg(nil)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.True(t, time.Since(start) < 10*timeout)
+	assert.False(t, breakers.Available(node))
+
+	// the caller can still cancel the stream
+	transport.breakers = NewBreakers(1, time.Hour)
+	canceled, cancel := context.WithCancel(ctx)
+	stream, err = transport.streamInterceptor(node)(canceled, desc, nil, "/test", streamer)
+	require.NoError(t, err)
+	cancel()
+	err = stream.RecvMsg(nil)
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
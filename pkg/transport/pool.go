@@ -0,0 +1,260 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package transport
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/utils"
+)
+
+// Pool keeps the connections to nodes open to share them between clients.
+// The connections not used by any client are closed after the idle timeout,
+// or when the pool is over capacity, least recently used first. Connections
+// in a failed state are replaced on the next dial.
+type Pool struct {
+	capacity    int
+	idleTimeout time.Duration
+
+	mu    sync.Mutex
+	conns map[poolKey]*pooledConn
+	// idle are the connections not used by any client, least recently used
+	// first
+	idle *list.List
+}
+
+// poolKey identifies a connection by the identities on both ends, as the
+// identity of the dialer is used in the TLS handshake, and by the address of
+// the node, which may move
+type poolKey struct {
+	local, remote storj.NodeID
+	address       string
+}
+
+type pooledConn struct {
+	key      poolKey
+	conn     *grpc.ClientConn
+	refs     int
+	lastUsed time.Time
+	// element is the position in the idle list of an unused connection
+	element *list.Element
+	// removed is set once the connection was removed from the pool, the
+	// last client closes it
+	removed bool
+}
+
+var (
+	defaultPoolOnce sync.Once
+	defaultPool     *Pool
+)
+
+// DefaultPool returns the pool shared by the clients of the process,
+// configured as the process
+func DefaultPool() *Pool {
+	defaultPoolOnce.Do(func() {
+		config := DefaultConfig().Pool
+		defaultPool = NewPool(config.Capacity, config.IdleTimeout)
+	})
+	return defaultPool
+}
+
+// NewPool returns a Pool keeping at most capacity connections open, and
+// closing the unused connections after idleTimeout
+func NewPool(capacity int, idleTimeout time.Duration) *Pool {
+	return &Pool{
+		capacity:    capacity,
+		idleTimeout: idleTimeout,
+		conns:       make(map[poolKey]*pooledConn),
+		idle:        list.New(),
+	}
+}
+
+// Dial returns a connection to the node shared with the other clients of the
+// pool, dialing it with transport if needed. The connection has to be
+// released with release instead of closed.
+func (pool *Pool) Dial(ctx context.Context, transport *Transport, node *pb.Node) (conn *grpc.ClientConn, release func(), err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	key := poolKey{
+		local:   transport.identity.ID,
+		remote:  node.Id,
+		address: node.GetAddress().GetAddress(),
+	}
+
+	pool.mu.Lock()
+	pool.expire(time.Now())
+	if pc, ok := pool.conns[key]; ok {
+		if healthy(pc.conn) {
+			mon.Event("pool_hit")
+			pool.acquire(pc)
+			pool.mu.Unlock()
+			return pc.conn, pool.releaser(pc), nil
+		}
+		mon.Event("pool_reconnect")
+		_ = pool.remove(pc)
+	}
+	pool.mu.Unlock()
+
+	mon.Event("pool_miss")
+	conn, err = transport.DialNode(ctx, node)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if pc, ok := pool.conns[key]; ok && healthy(pc.conn) {
+		// dialed concurrently by another client
+		pool.acquire(pc)
+		_ = conn.Close()
+		return pc.conn, pool.releaser(pc), nil
+	}
+
+	pc := &pooledConn{key: key, conn: conn}
+	pool.conns[key] = pc
+	pool.acquire(pc)
+	pool.evict()
+	return conn, pool.releaser(pc), nil
+}
+
+// Disconnect closes the connections to the node
+func (pool *Pool) Disconnect(id storj.NodeID) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	var errs []error
+	for key, pc := range pool.conns {
+		if key.remote == id {
+			errs = append(errs, pool.remove(pc))
+		}
+	}
+	return utils.CombineErrors(errs...)
+}
+
+// DisconnectLocal closes the connections dialed with the identity local,
+// leaving the connections of the other identities sharing the pool open
+func (pool *Pool) DisconnectLocal(local storj.NodeID) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	var errs []error
+	for key, pc := range pool.conns {
+		if key.local == local {
+			errs = append(errs, pool.remove(pc))
+		}
+	}
+	return utils.CombineErrors(errs...)
+}
+
+// Close closes the connections of the pool. The connections in use are
+// closed once they're released.
+func (pool *Pool) Close() error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	var errs []error
+	for _, pc := range pool.conns {
+		errs = append(errs, pool.remove(pc))
+	}
+	return utils.CombineErrors(errs...)
+}
+
+// acquire marks the connection in use. pool.mu must be held.
+func (pool *Pool) acquire(pc *pooledConn) {
+	pc.refs++
+	if pc.element != nil {
+		pool.idle.Remove(pc.element)
+		pc.element = nil
+	}
+}
+
+// releaser returns the function releasing the connection, which does nothing
+// when called again
+func (pool *Pool) releaser(pc *pooledConn) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			pool.mu.Lock()
+			defer pool.mu.Unlock()
+
+			pc.refs--
+			pc.lastUsed = time.Now()
+			if pc.refs > 0 {
+				return
+			}
+			if pc.removed {
+				_ = pc.conn.Close()
+				return
+			}
+			pc.element = pool.idle.PushBack(pc)
+			pool.evict()
+		})
+	}
+}
+
+// remove removes the connection from the pool, closing it if it's unused.
+// pool.mu must be held.
+func (pool *Pool) remove(pc *pooledConn) error {
+	if pool.conns[pc.key] == pc {
+		delete(pool.conns, pc.key)
+	}
+	pc.removed = true
+	if pc.element != nil {
+		pool.idle.Remove(pc.element)
+		pc.element = nil
+	}
+	if pc.refs > 0 {
+		return nil
+	}
+	return pc.conn.Close()
+}
+
+// expire closes the connections unused for longer than the idle timeout.
+// pool.mu must be held.
+func (pool *Pool) expire(now time.Time) {
+	for {
+		front := pool.idle.Front()
+		if front == nil {
+			return
+		}
+		pc := front.Value.(*pooledConn)
+		if now.Sub(pc.lastUsed) < pool.idleTimeout {
+			return
+		}
+		mon.Event("pool_expired")
+		_ = pool.remove(pc)
+	}
+}
+
+// evict closes the least recently used unused connections while the pool is
+// over capacity. pool.mu must be held.
+func (pool *Pool) evict() {
+	for len(pool.conns) > pool.capacity {
+		front := pool.idle.Front()
+		if front == nil {
+			// all the connections are in use
+			return
+		}
+		mon.Event("pool_evicted")
+		_ = pool.remove(front.Value.(*pooledConn))
+	}
+}
+
+// healthy returns whether the connection may be used
+func healthy(conn *grpc.ClientConn) bool {
+	switch conn.GetState() {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package transport_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/connectivity"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/transport"
+)
+
+func TestPool(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 0, 3, 0)
+	require.NoError(t, err)
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	nodes := make([]*pb.Node, 2)
+	for i := range nodes {
+		nodes[i] = &pb.Node{
+			Id: planet.StorageNodes[i+1].ID(),
+			Address: &pb.NodeAddress{
+				Transport: pb.NodeTransport_TCP_TLS_GRPC,
+				Address:   planet.StorageNodes[i+1].Addr(),
+			},
+		}
+	}
+
+	const idleTimeout = 100 * time.Millisecond
+	pool := transport.NewPool(1, idleTimeout)
+	client := transport.NewCustomClient(planet.StorageNodes[0].Identity, transport.DefaultConfig(),
+		transport.NewBreakers(5, time.Minute), pool)
+
+	{ // the connections are shared
+		first, releaseFirst, err := client.DialShared(ctx, nodes[0])
+		require.NoError(t, err)
+		second, releaseSecond, err := client.DialShared(ctx, nodes[0])
+		require.NoError(t, err)
+		assert.True(t, first == second)
+
+		releaseFirst()
+		releaseSecond()
+
+		third, releaseThird, err := client.DialShared(ctx, nodes[0])
+		require.NoError(t, err)
+		assert.True(t, first == third)
+		releaseThird()
+	}
+
+	{ // the least recently used connection is closed over capacity
+		first, releaseFirst, err := client.DialShared(ctx, nodes[0])
+		require.NoError(t, err)
+		releaseFirst()
+
+		second, releaseSecond, err := client.DialShared(ctx, nodes[1])
+		require.NoError(t, err)
+		assert.Equal(t, connectivity.Shutdown, first.GetState())
+		releaseSecond()
+		assert.NotEqual(t, connectivity.Shutdown, second.GetState())
+	}
+
+	{ // the idle connections expire
+		first, releaseFirst, err := client.DialShared(ctx, nodes[1])
+		require.NoError(t, err)
+		releaseFirst()
+
+		time.Sleep(idleTimeout)
+
+		second, releaseSecond, err := client.DialShared(ctx, nodes[1])
+		require.NoError(t, err)
+		assert.Equal(t, connectivity.Shutdown, first.GetState())
+		assert.False(t, first == second)
+		releaseSecond()
+	}
+
+	{ // the connections of an identity are closed without the others
+		other := transport.NewCustomClient(planet.StorageNodes[2].Identity, transport.DefaultConfig(),
+			transport.NewBreakers(5, time.Minute), pool)
+
+		mine, releaseMine, err := client.DialShared(ctx, nodes[0])
+		require.NoError(t, err)
+		theirs, releaseTheirs, err := other.DialShared(ctx, nodes[0])
+		require.NoError(t, err)
+		assert.False(t, mine == theirs)
+
+		require.NoError(t, pool.DisconnectLocal(planet.StorageNodes[0].ID()))
+		releaseMine()
+		releaseTheirs()
+		assert.Equal(t, connectivity.Shutdown, mine.GetState())
+		assert.NotEqual(t, connectivity.Shutdown, theirs.GetState())
+	}
+
+	{ // the connections in use are closed once released
+		conn, release, err := client.DialShared(ctx, nodes[1])
+		require.NoError(t, err)
+		require.NoError(t, pool.Close())
+		assert.NotEqual(t, connectivity.Shutdown, conn.GetState())
+		release()
+		assert.Equal(t, connectivity.Shutdown, conn.GetState())
+	}
+}
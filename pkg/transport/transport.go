@@ -5,6 +5,7 @@ package transport
 
 import (
 	"context"
+	"net"
 	"time"
 
 	"github.com/zeebo/errs"
@@ -20,25 +21,35 @@ var (
 	mon = monkit.Package()
 	//Error is the errs class of standard Transport Client errors
 	Error = errs.Class("transport error")
-	// default time to wait for a connection to be established
-	timeout = 20 * time.Second
 )
 
 // Client defines the interface to an transport client.
 type Client interface {
 	DialNode(ctx context.Context, node *pb.Node, opts ...grpc.DialOption) (*grpc.ClientConn, error)
 	DialAddress(ctx context.Context, address string, opts ...grpc.DialOption) (*grpc.ClientConn, error)
+	DialShared(ctx context.Context, node *pb.Node) (conn *grpc.ClientConn, release func(), err error)
 	Identity() *provider.FullIdentity
 }
 
 // Transport interface structure
 type Transport struct {
 	identity *provider.FullIdentity
+	config   Config
+	breakers *Breakers
+	pool     *Pool
 }
 
-// NewClient returns a newly instantiated Transport Client
+// NewClient returns a newly instantiated Transport Client, configured as
+// the process
 func NewClient(identity *provider.FullIdentity) Client {
-	return &Transport{identity: identity}
+	return NewCustomClient(identity, DefaultConfig(), DefaultBreakers(), DefaultPool())
+}
+
+// NewCustomClient returns a newly instantiated Transport Client configured
+// with config, keeping track of the failing nodes with breakers and sharing
+// the connections of pool
+func NewCustomClient(identity *provider.FullIdentity, config Config, breakers *Breakers, pool *Pool) Client {
+	return &Transport{identity: identity, config: config, breakers: breakers, pool: pool}
 }
 
 // DialNode returns a grpc connection with tls to a node
@@ -48,6 +59,9 @@ func (transport *Transport) DialNode(ctx context.Context, node *pb.Node, opts ..
 	if node.Address == nil || node.Address.Address == "" {
 		return nil, Error.New("no address")
 	}
+	if !transport.breakers.Available(node) {
+		return nil, BreakerError.New("node %s", node.Id)
+	}
 
 	// add ID of node we are wanting to connect to
 	dialOpt, err := transport.identity.DialOption(node.Id)
@@ -55,14 +69,46 @@ func (transport *Transport) DialNode(ctx context.Context, node *pb.Node, opts ..
 		return nil, err
 	}
 
-	options := append([]grpc.DialOption{dialOpt}, opts...)
-
-	ctx, cf := context.WithTimeout(ctx, timeout)
+	// block until the connection is established, to fail within the dial
+	// timeout
+	options := append([]grpc.DialOption{
+		dialOpt,
+		grpc.WithBlock(),
+		grpc.FailOnNonTempDialError(true),
+		grpc.WithUnaryInterceptor(transport.unaryInterceptor(node)),
+		grpc.WithStreamInterceptor(transport.streamInterceptor(node)),
+	}, opts...)
+
+	dialCtx, cf := context.WithTimeout(ctx, transport.config.DialTimeout)
 	defer cf()
-	return grpc.DialContext(ctx, node.GetAddress().Address, options...)
+
+	conn, err = grpc.DialContext(dialCtx, node.GetAddress().Address, options...)
+	if err != nil && ctx.Err() == nil {
+		// the node failed, not the caller
+		transport.breakers.Failure(node)
+	}
+	return conn, err
+}
+
+// DialShared returns a grpc connection with tls to a node from the pool of
+// the transport, shared with its other users. The connection has to be
+// released with release instead of closed.
+func (transport *Transport) DialShared(ctx context.Context, node *pb.Node) (conn *grpc.ClientConn, release func(), err error) {
+	if transport.pool == nil {
+		conn, err = transport.DialNode(ctx, node)
+		if err != nil {
+			return nil, nil, err
+		}
+		return conn, func() { _ = conn.Close() }, nil
+	}
+	return transport.pool.Dial(ctx, transport, node)
 }
 
-// DialAddress returns a grpc connection with tls to an IP address
+// DialAddress returns a grpc connection with tls to an IP address. The
+// connection is established in the background and reestablished when it
+// breaks, as the clients dialing addresses are kept for the lifetime of the
+// process and may be created before the server is up. Every attempt to
+// connect is bounded by the dial timeout.
 func (transport *Transport) DialAddress(ctx context.Context, address string, opts ...grpc.DialOption) (conn *grpc.ClientConn, err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -71,8 +117,18 @@ func (transport *Transport) DialAddress(ctx context.Context, address string, opt
 		return nil, err
 	}
 
-	options := append([]grpc.DialOption{dialOpt}, opts...)
-	return grpc.Dial(address, options...)
+	dialTimeout := transport.config.DialTimeout
+	options := append([]grpc.DialOption{
+		dialOpt,
+		grpc.WithDialer(func(address string, timeout time.Duration) (net.Conn, error) {
+			if timeout <= 0 || timeout > dialTimeout {
+				timeout = dialTimeout
+			}
+			return net.DialTimeout("tcp", address, timeout)
+		}),
+	}, opts...)
+
+	return grpc.DialContext(ctx, address, options...)
 }
 
 // Identity is a getter for the transport's identity
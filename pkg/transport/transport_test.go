@@ -5,10 +5,12 @@ package transport_test
 import (
 	"context"
 	"fmt"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 
 	"storj.io/storj/internal/testcontext"
@@ -83,3 +85,36 @@ func TestDialNode(t *testing.T) {
 		assert.NoError(t, conn.Close())
 	}
 }
+
+func TestDialTimeout(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	// the connections are established, but the node never answers the
+	// handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ctx.Check(listener.Close)
+
+	identity, err := testplanet.PregeneratedIdentity(0)
+	require.NoError(t, err)
+
+	const dialTimeout = 100 * time.Millisecond
+	config := transport.DefaultConfig()
+	config.DialTimeout = dialTimeout
+	breakers := transport.NewBreakers(1, time.Hour)
+	client := transport.NewCustomClient(identity, config, breakers, nil)
+
+	node := &pb.Node{Id: storj.NodeID{1}, Address: &pb.NodeAddress{
+		Transport: pb.NodeTransport_TCP_TLS_GRPC,
+		Address:   listener.Addr().String(),
+	}}
+
+	start := time.Now()
+	conn, err := client.DialNode(ctx, node)
+	assert.Error(t, err)
+	assert.Nil(t, conn)
+	assert.True(t, time.Since(start) < 10*dialTimeout)
+	// the node failed
+	assert.False(t, breakers.Available(node))
+}
@@ -0,0 +1,238 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/stream"
+	"storj.io/storj/pkg/utils"
+)
+
+// Bucket gives access to the objects of a bucket
+type Bucket struct {
+	project *Project
+	info    storj.Bucket
+}
+
+// UploadOptions are the optional parameters of uploading an object
+type UploadOptions struct {
+	// ContentType is the MIME type of the object
+	ContentType string
+	// Metadata is the custom metadata of the object
+	Metadata map[string]string
+	// Expires is the time when the object is deleted, never if zero
+	Expires time.Time
+
+	// Redundancy overrides the default redundancy scheme of the Config
+	Redundancy storj.RedundancyScheme
+	// Encryption overrides the default encryption scheme of the Config
+	Encryption storj.EncryptionScheme
+	// Compression compresses the object before encryption, the default
+	// compression of the bucket applies if it is Uncompressed
+	Compression storj.Compression
+	// DisableCompression uploads the object uncompressed even if the bucket
+	// compresses its objects by default
+	DisableCompression bool
+}
+
+// ListOptions are the parameters of listing objects
+type ListOptions struct {
+	// Prefix lists only the objects under the prefix
+	Prefix storj.Path
+	// Recursive lists the objects of the nested prefixes instead of
+	// collapsing them into prefixes
+	Recursive bool
+	// Cursor lists only the objects after the cursor
+	Cursor storj.Path
+}
+
+// Info returns the information about the bucket
+func (bucket *Bucket) Info() storj.Bucket {
+	return bucket.info
+}
+
+// UploadObject uploads the data as an object at path, replacing any
+// existing object at path
+func (bucket *Bucket) UploadObject(ctx context.Context, path storj.Path, data io.Reader, opts *UploadOptions) (_ storj.Object, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+
+	createInfo := storj.CreateObject{
+		ContentType:        opts.ContentType,
+		Metadata:           opts.Metadata,
+		Expires:            opts.Expires,
+		RedundancyScheme:   opts.Redundancy,
+		EncryptionScheme:   opts.Encryption,
+		Compression:        opts.Compression,
+		DisableCompression: opts.DisableCompression,
+	}
+
+	// uploads apply the encryption scheme of the object, but the redundancy
+	// scheme only for buckets with defaults, so an explicit redundancy
+	// scheme needs its own store
+	streams := bucket.project.streams
+	if !opts.Redundancy.IsZero() {
+		streams, err = streams.WithRedundancy(opts.Redundancy, 0, 0)
+		if err != nil {
+			return storj.Object{}, err
+		}
+	}
+
+	object, err := bucket.project.metainfo.CreateObject(ctx, bucket.info.Name, path, &createInfo)
+	if err != nil {
+		return storj.Object{}, err
+	}
+
+	mutableStream, err := object.CreateStream(ctx)
+	if err != nil {
+		return storj.Object{}, err
+	}
+
+	upload := stream.NewUpload(ctx, mutableStream, streams)
+
+	_, err = io.Copy(upload, data)
+	err = utils.CombineErrors(err, upload.Close())
+	if err != nil {
+		return storj.Object{}, err
+	}
+
+	err = object.Commit(ctx)
+	if err != nil {
+		return storj.Object{}, err
+	}
+
+	return bucket.project.metainfo.GetObject(ctx, bucket.info.Name, path)
+}
+
+// DownloadObject returns a reader of length bytes of the object at path
+// starting from offset. A negative length reads until the end of the
+// object. Reading the whole object verifies its checksum.
+func (bucket *Bucket) DownloadObject(ctx context.Context, path storj.Path, offset, length int64) (_ io.ReadCloser, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if offset < 0 {
+		return nil, Error.New("negative offset")
+	}
+
+	if offset == 0 && length < 0 {
+		readOnlyStream, err := bucket.project.metainfo.GetObjectStream(ctx, bucket.info.Name, path)
+		if err != nil {
+			return nil, err
+		}
+		return stream.NewDownload(ctx, readOnlyStream, bucket.project.streams), nil
+	}
+
+	// check the object exists to return the same error as above
+	_, err = bucket.project.metainfo.GetObject(ctx, bucket.info.Name, path)
+	if err != nil {
+		return nil, err
+	}
+
+	rr, _, err := bucket.project.streams.Get(ctx, storj.JoinPaths(bucket.info.Name, path), bucket.info.PathCipher)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset > rr.Size() {
+		return nil, Error.New("offset %d beyond the object size %d", offset, rr.Size())
+	}
+	if length < 0 || offset+length > rr.Size() {
+		length = rr.Size() - offset
+	}
+
+	return rr.Range(ctx, offset, length)
+}
+
+// GetObject returns the information about the object at path
+func (bucket *Bucket) GetObject(ctx context.Context, path storj.Path) (_ storj.Object, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return bucket.project.metainfo.GetObject(ctx, bucket.info.Name, path)
+}
+
+// DeleteObject deletes the object at path
+func (bucket *Bucket) DeleteObject(ctx context.Context, path storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return bucket.project.metainfo.DeleteObject(ctx, bucket.info.Name, path)
+}
+
+// ListObjects returns an iterator over the objects of the bucket
+func (bucket *Bucket) ListObjects(ctx context.Context, opts *ListOptions) *ObjectIterator {
+	if opts == nil {
+		opts = &ListOptions{}
+	}
+	return &ObjectIterator{
+		ctx:    ctx,
+		bucket: bucket,
+		opts: storj.ListOptions{
+			Prefix:    opts.Prefix,
+			Cursor:    opts.Cursor,
+			Recursive: opts.Recursive,
+			Direction: storj.After,
+		},
+		more: true,
+	}
+}
+
+// ObjectIterator iterates over the objects of a bucket, fetching them
+// page by page
+type ObjectIterator struct {
+	ctx    context.Context
+	bucket *Bucket
+	opts   storj.ListOptions
+
+	items []storj.Object
+	item  storj.Object
+	more  bool
+	err   error
+}
+
+// Next advances to the next object and returns false when there are no
+// more objects or an error occurred
+func (it *ObjectIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if len(it.items) == 0 {
+		if !it.more {
+			return false
+		}
+
+		list, err := it.bucket.project.metainfo.ListObjects(it.ctx, it.bucket.info.Name, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.items = list.Items
+		it.more = list.More
+		if len(it.items) == 0 {
+			return false
+		}
+		it.opts.Cursor = it.items[len(it.items)-1].Path
+	}
+
+	it.item = it.items[0]
+	it.items = it.items[1:]
+	return true
+}
+
+// Item returns the current object
+func (it *ObjectIterator) Item() storj.Object {
+	return it.item
+}
+
+// Err returns the error which stopped the iteration
+func (it *ObjectIterator) Err() error {
+	return it.err
+}
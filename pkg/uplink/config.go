@@ -0,0 +1,135 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/pkg/encryption"
+	"storj.io/storj/pkg/metainfo/kvmetainfo"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pointerdb/pdbclient"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/storage/buckets"
+	ecclient "storj.io/storj/pkg/storage/ec"
+	"storj.io/storj/pkg/storj"
+)
+
+// Config is the configuration for opening a Project
+type Config struct {
+	// Identity is the identity of the uplink
+	Identity *provider.FullIdentity
+
+	// OverlayAddr is the address of the overlay service of the satellite
+	OverlayAddr string
+	// PointerDBAddr is the address of the pointerdb service of the satellite
+	PointerDBAddr string
+	// APIKey is the API key of the project
+	APIKey string
+
+	// EncryptionKey is the root key for encrypting the data and the paths
+	EncryptionKey storj.Key
+	// DeriveBucketKeys derives a separate root key for every bucket from
+	// the EncryptionKey
+	DeriveBucketKeys bool
+
+	// PathCipher is the cipher for encrypting the paths of new buckets,
+	// AESGCM if zero
+	PathCipher storj.Cipher
+	// Redundancy is the default redundancy scheme of uploaded objects
+	Redundancy storj.RedundancyScheme
+	// Encryption is the default encryption scheme of uploaded objects
+	Encryption storj.EncryptionScheme
+
+	// SegmentSize is the maximum size of the segments of uploaded objects
+	SegmentSize int64
+	// MaxInlineSize is the maximum size of segments stored in the pointerdb
+	MaxInlineSize int
+	// MaxBufferMem is the maximum memory for read buffers of downloads
+	MaxBufferMem int
+	// UploadConcurrency is the number of segments of an object uploaded in
+	// parallel
+	UploadConcurrency int
+	// DownloadPrefetch is the number of segments of an object downloaded
+	// ahead of the one being read. Zero disables the prefetching, and a
+	// negative value selects the default of 1.
+	DownloadPrefetch int
+}
+
+// setDefaults sets the unset values to the defaults of the uplink CLI
+func (config *Config) setDefaults() {
+	if config.PathCipher == storj.Unencrypted {
+		config.PathCipher = storj.AESGCM
+	}
+	if config.Redundancy.IsZero() {
+		config.Redundancy = storj.RedundancyScheme{
+			Algorithm:      storj.ReedSolomon,
+			ShareSize:      1 * memory.KB.Int32(),
+			RequiredShares: 29,
+			RepairShares:   35,
+			OptimalShares:  80,
+			TotalShares:    95,
+		}
+	}
+	if config.Encryption.IsZero() {
+		config.Encryption = storj.EncryptionScheme{
+			Cipher:    storj.AESGCM,
+			BlockSize: 1 * memory.KB.Int32(),
+		}
+	}
+	if config.SegmentSize <= 0 {
+		config.SegmentSize = 64 * memory.MB.Int64()
+	}
+	if config.MaxInlineSize <= 0 {
+		config.MaxInlineSize = 4 * memory.KB.Int()
+	}
+	if config.MaxBufferMem <= 0 {
+		config.MaxBufferMem = 4 * memory.MB.Int()
+	}
+	if config.UploadConcurrency <= 0 {
+		config.UploadConcurrency = 2
+	}
+	if config.DownloadPrefetch < 0 {
+		config.DownloadPrefetch = 1
+	}
+}
+
+// Open connects to the satellite services and returns the Project
+func Open(ctx context.Context, config Config) (_ *Project, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if config.Identity == nil {
+		return nil, Error.New("identity is required")
+	}
+	config.setDefaults()
+
+	oc, err := overlay.NewOverlayClient(config.Identity, config.OverlayAddr)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	pdb, err := pdbclient.NewClient(config.Identity, config.PointerDBAddr, config.APIKey)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	project := &Project{
+		config: config,
+		oc:     oc,
+		ec:     ecclient.NewClient(config.Identity, config.MaxBufferMem),
+		pdb:    pdb,
+		keys:   encryption.NewKeyStore(&config.EncryptionKey, config.DeriveBucketKeys),
+	}
+
+	segments, streams, err := project.stores(config.Redundancy)
+	if err != nil {
+		return nil, err
+	}
+
+	project.streams = streams
+	project.metainfo = kvmetainfo.New(buckets.NewStore(streams), streams, segments, pdb, project.keys).WithDefaults(config.Redundancy, config.Encryption)
+
+	return project, nil
+}
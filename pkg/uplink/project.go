@@ -0,0 +1,110 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+
+	"github.com/vivint/infectious"
+
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/encryption"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pointerdb/pdbclient"
+	ecclient "storj.io/storj/pkg/storage/ec"
+	"storj.io/storj/pkg/storage/segments"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+)
+
+// Project gives access to the buckets of a project
+type Project struct {
+	config   Config
+	oc       overlay.Client
+	ec       ecclient.Client
+	pdb      pdbclient.Client
+	keys     *encryption.KeyStore
+	metainfo storj.Metainfo
+	streams  streams.Store
+}
+
+// CreateBucket creates a new bucket, which paths are encrypted with the path
+// cipher of the Config. The schemes and the segment size of the Config are
+// stored as the defaults for the new objects of the bucket.
+func (project *Project) CreateBucket(ctx context.Context, name string) (_ *Bucket, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	info, err := project.metainfo.CreateBucket(ctx, name, &storj.Bucket{
+		PathCipher:       project.config.PathCipher,
+		SegmentsSize:     project.config.SegmentSize,
+		InlineThreshold:  project.config.MaxInlineSize,
+		RedundancyScheme: project.config.Redundancy,
+		EncryptionScheme: project.config.Encryption,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Bucket{project: project, info: info}, nil
+}
+
+// OpenBucket returns an existing bucket
+func (project *Project) OpenBucket(ctx context.Context, name string) (_ *Bucket, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	info, err := project.metainfo.GetBucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Bucket{project: project, info: info}, nil
+}
+
+// DeleteBucket deletes the bucket
+func (project *Project) DeleteBucket(ctx context.Context, name string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return project.metainfo.DeleteBucket(ctx, name)
+}
+
+// ListBuckets lists the buckets of the project
+func (project *Project) ListBuckets(ctx context.Context, options storj.BucketListOptions) (_ storj.BucketList, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if options.Direction == 0 {
+		options.Direction = storj.After
+	}
+	return project.metainfo.ListBuckets(ctx, options)
+}
+
+// Metainfo returns the underlying storj.Metainfo of the project for the
+// functionality not exposed by this package
+func (project *Project) Metainfo() storj.Metainfo {
+	return project.metainfo
+}
+
+// stores creates the segments and streams stores uploading with the given
+// redundancy scheme
+func (project *Project) stores(scheme storj.RedundancyScheme) (segments.Store, streams.Store, error) {
+	fc, err := infectious.NewFEC(int(scheme.RequiredShares), int(scheme.TotalShares))
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+
+	rs, err := eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, int(scheme.ShareSize)), int(scheme.RepairShares), int(scheme.OptimalShares))
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+
+	if int(scheme.ShareSize)*int(scheme.RequiredShares)%int(project.config.Encryption.BlockSize) != 0 {
+		return nil, nil, Error.New("encryption block size must be a divisor of the stripe size")
+	}
+
+	segments := segments.NewSegmentStore(project.oc, project.ec, project.pdb, rs, project.config.MaxInlineSize)
+
+	streams, err := streams.NewStreamStore(segments, project.config.SegmentSize, project.keys, int(project.config.Encryption.BlockSize), project.config.Encryption.Cipher, project.config.UploadConcurrency, project.config.DownloadPrefetch)
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+
+	return segments, streams, nil
+}
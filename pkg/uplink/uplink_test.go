@@ -0,0 +1,261 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/storj"
+)
+
+const (
+	TestAPIKey = "test-api-key"
+	TestEncKey = "test-encryption-key"
+	TestBucket = "test-bucket"
+)
+
+func TestUploadDownload(t *testing.T) {
+	runTest(t, func(ctx context.Context, project *Project) {
+		bucket, err := project.CreateBucket(ctx, TestBucket)
+		require.NoError(t, err)
+
+		data := []byte("hello storj, hello uplink")
+		object, err := bucket.UploadObject(ctx, "dir/hello.txt", bytes.NewReader(data), &UploadOptions{
+			ContentType: "text/plain",
+			Metadata:    map[string]string{"key": "value"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "dir/hello.txt", object.Path)
+		assert.Equal(t, int64(len(data)), object.Size)
+		assert.Equal(t, "text/plain", object.ContentType)
+		assert.Equal(t, "value", object.Metadata["key"])
+
+		for _, tt := range []struct {
+			offset, length int64
+			expected       []byte
+		}{
+			{0, -1, data},
+			{6, 5, data[6:11]},
+			{13, -1, data[13:]},
+			{20, 100, data[20:]},
+		} {
+			reader, err := bucket.DownloadObject(ctx, "dir/hello.txt", tt.offset, tt.length)
+			require.NoError(t, err)
+			downloaded, err := ioutil.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.NoError(t, reader.Close())
+			assert.Equal(t, tt.expected, downloaded)
+		}
+
+		_, err = bucket.DownloadObject(ctx, "missing.txt", 0, -1)
+		assert.True(t, storj.ErrObjectNotFound.Has(err))
+
+		err = bucket.DeleteObject(ctx, "dir/hello.txt")
+		assert.NoError(t, err)
+
+		_, err = bucket.GetObject(ctx, "dir/hello.txt")
+		assert.True(t, storj.ErrObjectNotFound.Has(err))
+	})
+}
+
+func TestUploadOptions(t *testing.T) {
+	runTest(t, func(ctx context.Context, project *Project) {
+		bucket, err := project.CreateBucket(ctx, TestBucket)
+		require.NoError(t, err)
+
+		redundancy := storj.RedundancyScheme{
+			Algorithm:      storj.ReedSolomon,
+			RequiredShares: 1,
+			RepairShares:   2,
+			OptimalShares:  3,
+			TotalShares:    4,
+			ShareSize:      2 * memory.KB.Int32(),
+		}
+		encryption := storj.EncryptionScheme{
+			Cipher:    storj.SecretBox,
+			BlockSize: 2 * memory.KB.Int32(),
+		}
+
+		data := bytes.Repeat([]byte("x"), 10*memory.KB.Int())
+		object, err := bucket.UploadObject(ctx, "remote", bytes.NewReader(data), &UploadOptions{
+			Redundancy: redundancy,
+			Encryption: encryption,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, redundancy, object.RedundancyScheme)
+		assert.Equal(t, encryption, object.EncryptionScheme)
+
+		reader, err := bucket.DownloadObject(ctx, "remote", 0, -1)
+		require.NoError(t, err)
+		downloaded, err := ioutil.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.NoError(t, reader.Close())
+		assert.Equal(t, data, downloaded)
+	})
+}
+
+func TestUploadCompressed(t *testing.T) {
+	runTest(t, func(ctx context.Context, project *Project) {
+		bucket, err := project.CreateBucket(ctx, TestBucket)
+		require.NoError(t, err)
+
+		var data []byte
+		for i := 0; len(data) < 3*memory.MB.Int(); i++ {
+			data = append(data, fmt.Sprintf("line %d of a compressible log\n", i)...)
+		}
+
+		object, err := bucket.UploadObject(ctx, "log", bytes.NewReader(data), &UploadOptions{
+			Compression: storj.Gzip,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), object.Size)
+		assert.Equal(t, storj.Gzip, object.Compression)
+
+		reader, err := bucket.DownloadObject(ctx, "log", 0, -1)
+		require.NoError(t, err)
+		downloaded, err := ioutil.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.NoError(t, reader.Close())
+		assert.Equal(t, data, downloaded)
+
+		// ranges are read across the compressed frames
+		offset := int64(memory.MB.Int() - 100)
+		reader, err = bucket.DownloadObject(ctx, "log", offset, 200)
+		require.NoError(t, err)
+		downloaded, err = ioutil.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.NoError(t, reader.Close())
+		assert.Equal(t, data[offset:offset+200], downloaded)
+
+		// an object can opt out of the default compression of its bucket
+		_, err = project.metainfo.CreateBucket(ctx, TestBucket+"-compressed", &storj.Bucket{Compression: storj.Gzip})
+		require.NoError(t, err)
+		compressed, err := project.OpenBucket(ctx, TestBucket+"-compressed")
+		require.NoError(t, err)
+
+		object, err = compressed.UploadObject(ctx, "log", bytes.NewReader(data), nil)
+		require.NoError(t, err)
+		assert.Equal(t, storj.Gzip, object.Compression)
+
+		object, err = compressed.UploadObject(ctx, "log", bytes.NewReader(data), &UploadOptions{
+			DisableCompression: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, storj.Uncompressed, object.Compression)
+	})
+}
+
+func TestListObjects(t *testing.T) {
+	runTest(t, func(ctx context.Context, project *Project) {
+		bucket, err := project.CreateBucket(ctx, TestBucket)
+		require.NoError(t, err)
+
+		for _, path := range []storj.Path{"a", "b/c", "b/d", "e"} {
+			_, err = bucket.UploadObject(ctx, path, bytes.NewReader([]byte(path)), nil)
+			require.NoError(t, err)
+		}
+
+		list := func(opts *ListOptions) []storj.Path {
+			var paths []storj.Path
+			it := bucket.ListObjects(ctx, opts)
+			for it.Next() {
+				paths = append(paths, it.Item().Path)
+			}
+			assert.NoError(t, it.Err())
+			return paths
+		}
+
+		// the paths are encrypted so the order of the listing isn't lexical
+		assert.ElementsMatch(t, []storj.Path{"a", "b/", "e"}, list(nil))
+		assert.ElementsMatch(t, []storj.Path{"c", "d"}, list(&ListOptions{Prefix: "b/"}))
+
+		all := list(&ListOptions{Recursive: true})
+		assert.ElementsMatch(t, []storj.Path{"a", "b/c", "b/d", "e"}, all)
+		if len(all) == 4 {
+			assert.Equal(t, all[2:], list(&ListOptions{Recursive: true, Cursor: all[1]}))
+		}
+
+		buckets, err := project.ListBuckets(ctx, storj.BucketListOptions{})
+		if assert.NoError(t, err) && assert.Len(t, buckets.Items, 1) {
+			assert.Equal(t, TestBucket, buckets.Items[0].Name)
+		}
+	})
+}
+
+func runTest(t *testing.T, test func(context.Context, *Project)) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 4, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	// wait for the storage nodes to be discovered by the satellite
+	time.Sleep(2 * time.Second)
+
+	// TODO(kaloyan): We should have a better way for configuring the Satellite's API Key
+	err = flag.Set("pointer-db.auth.api-key", TestAPIKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	config := Config{
+		Identity:      planet.Uplinks[0].Identity,
+		OverlayAddr:   planet.Satellites[0].Addr(),
+		PointerDBAddr: planet.Satellites[0].Addr(),
+		APIKey:        TestAPIKey,
+		Redundancy: storj.RedundancyScheme{
+			Algorithm:      storj.ReedSolomon,
+			RequiredShares: 2,
+			RepairShares:   3,
+			OptimalShares:  4,
+			TotalShares:    4,
+			ShareSize:      1 * memory.KB.Int32(),
+		},
+	}
+	copy(config.EncryptionKey[:], TestEncKey)
+
+	project, err := Open(ctx, config)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	test(ctx, project)
+}
+
+func TestConfigTransferDefaults(t *testing.T) {
+	for i, tt := range []struct {
+		concurrency, prefetch       int
+		expConcurrency, expPrefetch int
+	}{
+		{0, -1, 2, 1},
+		{-1, -5, 2, 1},
+		// zero disables the prefetching
+		{0, 0, 2, 0},
+		{4, 3, 4, 3},
+	} {
+		config := Config{UploadConcurrency: tt.concurrency, DownloadPrefetch: tt.prefetch}
+		config.setDefaults()
+		errTag := fmt.Sprintf("Test case #%d", i)
+		assert.Equal(t, tt.expConcurrency, config.UploadConcurrency, errTag)
+		assert.Equal(t, tt.expPrefetch, config.DownloadPrefetch, errTag)
+	}
+}
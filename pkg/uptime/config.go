@@ -0,0 +1,63 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uptime
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/kademlia"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/statdb"
+)
+
+// Config contains configurable values for the uptime service
+type Config struct {
+	Interval    time.Duration `help:"how frequently the nodes are pinged" default:"1h"`
+	Concurrency int           `help:"number of nodes pinged concurrently" default:"10"`
+	Jitter      time.Duration `help:"maximum random delay before pinging a node" default:"1s"`
+	BatchSize   int           `help:"number of ping results recorded in statdb at once" default:"100"`
+}
+
+// Run runs the uptime service with the configured values
+func (c *Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cache := overlay.LoadFromContext(ctx)
+	if cache == nil {
+		return Error.New("failed to load overlay cache from context")
+	}
+	kad := kademlia.LoadFromContext(ctx)
+	if kad == nil {
+		return Error.New("failed to load kademlia from context")
+	}
+	sdb, ok := ctx.Value("masterdb").(interface {
+		StatDB() statdb.DB
+	})
+	if !ok {
+		return Error.New("unable to get master db instance")
+	}
+
+	service := NewService(zap.L(), cache, sdb.StatDB(), kad, c.Interval, c.Concurrency, c.Jitter, c.BatchSize)
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		if err := service.Run(ctx); err != nil {
+			defer cancel()
+			zap.L().Error("Error running uptime service", zap.Error(err))
+		}
+	}()
+
+	defer process.OnReload(func(ctx context.Context) error {
+		service.SetInterval(c.Interval)
+		zap.L().Info("uptime interval applied", zap.Duration("interval", c.Interval))
+		return nil
+	})()
+
+	return server.Run(ctx)
+}
@@ -0,0 +1,180 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uptime
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/statdb"
+)
+
+// Pinger checks that a node is reachable
+type Pinger interface {
+	Ping(ctx context.Context, node pb.Node) (pb.Node, error)
+}
+
+// Service periodically pings the nodes of the overlay cache, records the
+// results in statdb and marks the nodes failing to respond offline
+type Service struct {
+	log         *zap.Logger
+	cache       *overlay.Cache
+	statdb      statdb.DB
+	pinger      Pinger
+	concurrency int
+	jitter      time.Duration
+	batchSize   int
+	ticker      *sync2.Ticker
+}
+
+// NewService returns a Service pinging the nodes every interval, at most
+// concurrency nodes at a time, each after a random delay up to jitter. The
+// results are recorded in statdb batchSize nodes at a time.
+func NewService(log *zap.Logger, cache *overlay.Cache, sdb statdb.DB, pinger Pinger, interval time.Duration, concurrency int, jitter time.Duration, batchSize int) *Service {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &Service{
+		log:         log,
+		cache:       cache,
+		statdb:      sdb,
+		pinger:      pinger,
+		concurrency: concurrency,
+		jitter:      jitter,
+		batchSize:   batchSize,
+		ticker:      sync2.NewTicker(interval),
+	}
+}
+
+// Run checks the nodes every interval until the context is canceled
+func (service *Service) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	defer service.ticker.Stop()
+
+	for {
+		err = service.Check(ctx)
+		if err != nil {
+			service.log.Error("Uptime check failed", zap.Error(err))
+		}
+
+		if err := service.ticker.Wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// SetInterval changes how frequently the nodes are checked
+func (service *Service) SetInterval(interval time.Duration) {
+	service.ticker.SetInterval(interval)
+}
+
+// Check pings every node of the overlay cache once
+func (service *Service) Check(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	nodes, err := service.cache.List(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	var mu sync.Mutex
+	var updates []*statdb.UpdateRequest
+	offline := 0
+
+	limiter := sync2.NewLimiter(service.concurrency)
+	for _, node := range nodes {
+		node := node
+		started := limiter.Go(ctx, func() {
+			isUp := service.ping(ctx, node)
+			if ctx.Err() != nil {
+				// the ping was interrupted, the node may be fine
+				return
+			}
+
+			if isUp {
+				service.cache.MarkOnline(node.Id)
+			} else {
+				service.cache.MarkOffline(node.Id)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			updates = append(updates, &statdb.UpdateRequest{
+				Node:         node.Id,
+				UpdateUptime: true,
+				IsUp:         isUp,
+			})
+			if !isUp {
+				offline++
+			}
+		})
+		if !started {
+			break
+		}
+	}
+	limiter.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	mon.IntVal("checked_nodes").Observe(int64(len(updates)))
+	mon.IntVal("offline_nodes").Observe(int64(offline))
+
+	return service.record(ctx, updates)
+}
+
+// ping pings the node after a random delay, and returns whether it responded
+func (service *Service) ping(ctx context.Context, node *pb.Node) bool {
+	if service.jitter > 0 {
+		delay := time.Duration(rand.Int63n(int64(service.jitter)))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	_, err := service.pinger.Ping(ctx, *node)
+	if err != nil {
+		service.log.Debug("node failed uptime check", zap.Stringer("node", node.Id), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// record records the ping results in statdb in batches
+func (service *Service) record(ctx context.Context, updates []*statdb.UpdateRequest) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	batchSize := service.batchSize
+	if batchSize <= 0 {
+		batchSize = len(updates)
+	}
+
+	for len(updates) > 0 {
+		n := batchSize
+		if n > len(updates) {
+			n = len(updates)
+		}
+		batch := updates[:n]
+		updates = updates[n:]
+
+		res, err := service.statdb.UpdateBatch(ctx, &statdb.UpdateBatchRequest{NodeList: batch})
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		for _, failed := range res.GetFailedNodes() {
+			service.log.Warn("failed to record uptime", zap.Stringer("node", failed.Node))
+		}
+	}
+	return nil
+}
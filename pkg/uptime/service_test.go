@@ -0,0 +1,89 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uptime_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/statdb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/uptime"
+)
+
+// failingPinger fails to ping the down nodes and pings the others
+type failingPinger struct {
+	uptime.Pinger
+	down map[storj.NodeID]bool
+}
+
+func (pinger *failingPinger) Ping(ctx context.Context, node pb.Node) (pb.Node, error) {
+	if pinger.down[node.Id] {
+		return pb.Node{}, errors.New("node is down")
+	}
+	return pinger.Pinger.Ping(ctx, node)
+}
+
+func TestCheck(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 4, 0)
+	require.NoError(t, err)
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	satellite := planet.Satellites[0]
+	for _, node := range planet.StorageNodes {
+		require.NoError(t, satellite.Overlay.Put(ctx, node.ID(), node.Info))
+	}
+
+	down := planet.StorageNodes[0].ID()
+	var wentOffline []storj.NodeID
+	satellite.Overlay.OnOffline(func(id storj.NodeID) {
+		wentOffline = append(wentOffline, id)
+	})
+
+	pinger := &failingPinger{Pinger: satellite.Kademlia, down: map[storj.NodeID]bool{down: true}}
+	service := uptime.NewService(zaptest.NewLogger(t), satellite.Overlay, satellite.StatDB, pinger,
+		time.Hour, 2, time.Millisecond, 3)
+
+	require.NoError(t, service.Check(ctx))
+
+	assert.Equal(t, []storj.NodeID{down}, wentOffline)
+	for _, node := range planet.StorageNodes {
+		assert.Equal(t, node.ID() == down, satellite.Overlay.IsOffline(node.ID()))
+
+		res, err := satellite.StatDB.Get(ctx, &statdb.GetRequest{Node: node.ID()})
+		require.NoError(t, err)
+		stats := res.Stats
+		assert.EqualValues(t, 1, stats.UptimeCount)
+		if node.ID() == down {
+			assert.EqualValues(t, 0, stats.UptimeSuccessCount)
+		} else {
+			assert.EqualValues(t, 1, stats.UptimeSuccessCount)
+		}
+	}
+
+	// the node is back
+	pinger.down = nil
+	require.NoError(t, service.Check(ctx))
+	assert.False(t, satellite.Overlay.IsOffline(down))
+
+	res, err := satellite.StatDB.Get(ctx, &statdb.GetRequest{Node: down})
+	require.NoError(t, err)
+	stats := res.Stats
+	assert.EqualValues(t, 2, stats.UptimeCount)
+	assert.EqualValues(t, 1, stats.UptimeSuccessCount)
+}
@@ -0,0 +1,290 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package webdav
+
+import (
+	"context"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	dav "golang.org/x/net/webdav"
+
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/stream"
+	"storj.io/storj/pkg/utils"
+)
+
+// fileInfo implements os.FileInfo for objects and prefixes
+type fileInfo struct {
+	object storj.Object
+	dir    bool
+}
+
+// Name implements os.FileInfo
+func (info *fileInfo) Name() string { return path.Base(strings.TrimSuffix(info.object.Path, "/")) }
+
+// Size implements os.FileInfo
+func (info *fileInfo) Size() int64 { return info.object.Size }
+
+// Mode implements os.FileInfo
+func (info *fileInfo) Mode() os.FileMode {
+	if info.dir {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
+
+// ModTime implements os.FileInfo
+func (info *fileInfo) ModTime() time.Time { return info.object.Modified }
+
+// IsDir implements os.FileInfo
+func (info *fileInfo) IsDir() bool { return info.dir }
+
+// Sys implements os.FileInfo
+func (info *fileInfo) Sys() interface{} { return nil }
+
+// ContentType implements webdav.ContentTyper, so the content isn't
+// downloaded for detecting it
+func (info *fileInfo) ContentType(ctx context.Context) (string, error) {
+	if info.object.ContentType != "" {
+		return info.object.ContentType, nil
+	}
+	if contentType := mime.TypeByExtension(path.Ext(info.object.Path)); contentType != "" {
+		return contentType, nil
+	}
+	return "application/octet-stream", nil
+}
+
+// ETag implements webdav.ETager
+func (info *fileInfo) ETag(ctx context.Context) (string, error) {
+	if info.object.ETag == "" {
+		return "", dav.ErrNotImplemented
+	}
+	return `"` + info.object.ETag + `"`, nil
+}
+
+// readFile implements webdav.File for reading objects
+type readFile struct {
+	ctx      context.Context
+	fs       *FileSystem
+	info     *fileInfo
+	download *stream.Download
+	offset   int64
+}
+
+// Read implements io.Reader. The object is downloaded only when it's read.
+func (file *readFile) Read(p []byte) (n int, err error) {
+	if file.download == nil {
+		readOnlyStream, err := file.fs.metainfo.GetObjectStream(file.ctx, file.fs.bucket.Name, file.info.object.Path)
+		if err != nil {
+			return 0, convertError(err)
+		}
+
+		file.download = stream.NewDownload(file.ctx, readOnlyStream, file.fs.streams)
+		if file.offset != 0 {
+			if _, err := file.download.Seek(file.offset, io.SeekStart); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	n, err = file.download.Read(p)
+	file.offset += int64(n)
+	return n, err
+}
+
+// Seek implements io.Seeker
+func (file *readFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += file.offset
+	case io.SeekEnd:
+		offset += file.info.Size()
+	default:
+		return file.offset, Error.New("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return file.offset, Error.New("negative offset %d", offset)
+	}
+
+	if file.download != nil && offset != file.offset {
+		if _, err := file.download.Seek(offset, io.SeekStart); err != nil {
+			return file.offset, err
+		}
+	}
+	file.offset = offset
+	return offset, nil
+}
+
+// Readdir implements http.File
+func (file *readFile) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, Error.New("not a directory")
+}
+
+// Stat implements http.File
+func (file *readFile) Stat() (os.FileInfo, error) { return file.info, nil }
+
+// Write implements io.Writer
+func (file *readFile) Write(p []byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+// Close implements io.Closer
+func (file *readFile) Close() error {
+	if file.download == nil {
+		return nil
+	}
+	return file.download.Close()
+}
+
+// dirFile implements webdav.File for listing directories
+type dirFile struct {
+	ctx     context.Context
+	fs      *FileSystem
+	info    *fileInfo
+	entries []os.FileInfo
+	listed  bool
+}
+
+// Read implements io.Reader
+func (file *dirFile) Read(p []byte) (int, error) {
+	return 0, Error.New("is a directory")
+}
+
+// Seek implements io.Seeker
+func (file *dirFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, Error.New("is a directory")
+}
+
+// Readdir implements http.File
+func (file *dirFile) Readdir(count int) ([]os.FileInfo, error) {
+	if !file.listed {
+		err := file.fs.listObjects(file.ctx, file.info.object.Path, false, func(object storj.Object) error {
+			// skip the object marking the directory itself
+			if object.Path == "" {
+				return nil
+			}
+			if object.IsPrefix {
+				object.Path = strings.TrimSuffix(object.Path, "/")
+			}
+			file.entries = append(file.entries, &fileInfo{object: object, dir: object.IsPrefix})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		file.listed = true
+	}
+
+	if count <= 0 {
+		entries := file.entries
+		file.entries = nil
+		return entries, nil
+	}
+
+	if len(file.entries) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(file.entries) {
+		count = len(file.entries)
+	}
+	entries := file.entries[:count]
+	file.entries = file.entries[count:]
+	return entries, nil
+}
+
+// Stat implements http.File
+func (file *dirFile) Stat() (os.FileInfo, error) { return file.info, nil }
+
+// Write implements io.Writer
+func (file *dirFile) Write(p []byte) (int, error) {
+	return 0, Error.New("is a directory")
+}
+
+// Close implements io.Closer
+func (file *dirFile) Close() error { return nil }
+
+// writeFile implements webdav.File for uploading objects
+type writeFile struct {
+	ctx      context.Context
+	fs       *FileSystem
+	object   storj.MutableObject
+	upload   *stream.Upload
+	body     *putBody
+	size     int64
+	modified time.Time
+	err      error
+}
+
+// Read implements io.Reader
+func (file *writeFile) Read(p []byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+// Seek implements io.Seeker
+func (file *writeFile) Seek(offset int64, whence int) (int64, error) {
+	return file.size, Error.New("seeking not supported while uploading")
+}
+
+// Readdir implements http.File
+func (file *writeFile) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, Error.New("not a directory")
+}
+
+// Stat implements http.File
+func (file *writeFile) Stat() (os.FileInfo, error) {
+	object := file.object.Info()
+	object.Size = file.size
+	object.Modified = file.modified
+	return &fileInfo{object: object}, nil
+}
+
+// Write implements io.Writer
+func (file *writeFile) Write(p []byte) (int, error) {
+	n, err := file.upload.Write(p)
+	file.size += int64(n)
+	if err != nil && file.err == nil {
+		file.err = err
+	}
+	return n, err
+}
+
+// Close commits the upload, or aborts it if not all data was received
+func (file *writeFile) Close() error {
+	if err := file.incomplete(); err != nil {
+		return utils.CombineErrors(err, file.abort())
+	}
+	if err := file.upload.Close(); err != nil {
+		return err
+	}
+	return file.object.Commit(file.ctx)
+}
+
+// incomplete returns an error if writing the data or reading the body of the
+// request failed, or if the body was shorter than its Content-Length
+func (file *writeFile) incomplete() error {
+	if file.err != nil {
+		return file.err
+	}
+	if file.body == nil {
+		return nil
+	}
+	if file.body.err != nil {
+		return file.body.err
+	}
+	if file.body.expected >= 0 && file.size != file.body.expected {
+		return Error.New("received %d of %d bytes", file.size, file.body.expected)
+	}
+	return nil
+}
+
+// abort drops the partially uploaded object
+func (file *writeFile) abort() error {
+	return file.upload.Abort(Error.New("upload aborted"))
+}
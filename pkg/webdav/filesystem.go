@@ -0,0 +1,284 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package webdav
+
+import (
+	"context"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+	dav "golang.org/x/net/webdav"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/stream"
+	"storj.io/storj/pkg/utils"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the errs class of WebDAV server errors
+	Error = errs.Class("WebDAV error")
+)
+
+// directoryContentType is the content type of the objects marking empty
+// directories, the same as the ones created by uplink mount
+const directoryContentType = "application/directory"
+
+// FileSystem implements webdav.FileSystem for a bucket
+type FileSystem struct {
+	metainfo storj.Metainfo
+	streams  streams.Store
+	bucket   storj.Bucket
+}
+
+// NewFileSystem creates a new WebDAV file system serving the bucket
+func NewFileSystem(metainfo storj.Metainfo, streams streams.Store, bucket storj.Bucket) *FileSystem {
+	return &FileSystem{
+		metainfo: metainfo,
+		streams:  streams,
+		bucket:   bucket,
+	}
+}
+
+// objectPath converts the slash separated WebDAV name to an object path
+func objectPath(name string) storj.Path {
+	return strings.Trim(path.Clean("/"+name), "/")
+}
+
+// Mkdir implements webdav.FileSystem
+func (fs *FileSystem) Mkdir(ctx context.Context, name string, perm os.FileMode) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	objPath := objectPath(name)
+	if objPath == "" {
+		return os.ErrExist
+	}
+
+	if _, err := fs.Stat(ctx, name); err == nil {
+		return os.ErrExist
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	parent, err := fs.Stat(ctx, path.Dir(objPath))
+	if err != nil {
+		return err
+	}
+	if !parent.IsDir() {
+		return os.ErrNotExist
+	}
+
+	// the directory is kept by an empty object until something is put in it
+	return fs.upload(ctx, objPath+"/", directoryContentType, strings.NewReader(""))
+}
+
+// OpenFile implements webdav.FileSystem
+func (fs *FileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (_ dav.File, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	objPath := objectPath(name)
+
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC) != 0 {
+		if objPath == "" {
+			return nil, os.ErrPermission
+		}
+		return fs.createFile(ctx, objPath)
+	}
+
+	info, err := fs.stat(ctx, objPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return &dirFile{ctx: ctx, fs: fs, info: info}, nil
+	}
+	return &readFile{ctx: ctx, fs: fs, info: info}, nil
+}
+
+// RemoveAll implements webdav.FileSystem
+func (fs *FileSystem) RemoveAll(ctx context.Context, name string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	objPath := objectPath(name)
+	if objPath == "" {
+		return os.ErrPermission
+	}
+
+	err = fs.metainfo.DeleteObject(ctx, fs.bucket.Name, objPath)
+	if !storj.ErrObjectNotFound.Has(err) {
+		return convertError(err)
+	}
+
+	return fs.listObjects(ctx, objPath, true, func(object storj.Object) error {
+		return fs.metainfo.DeleteObject(ctx, fs.bucket.Name, storj.JoinPaths(objPath, object.Path))
+	})
+}
+
+// Rename implements webdav.FileSystem
+func (fs *FileSystem) Rename(ctx context.Context, oldName, newName string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	oldPath, newPath := objectPath(oldName), objectPath(newName)
+	if oldPath == "" || newPath == "" {
+		return os.ErrPermission
+	}
+
+	info, err := fs.stat(ctx, oldPath)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return fs.move(ctx, oldPath, newPath)
+	}
+
+	return fs.listObjects(ctx, oldPath, true, func(object storj.Object) error {
+		return fs.move(ctx, storj.JoinPaths(oldPath, object.Path), storj.JoinPaths(newPath, object.Path))
+	})
+}
+
+// Stat implements webdav.FileSystem
+func (fs *FileSystem) Stat(ctx context.Context, name string) (_ os.FileInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return fs.stat(ctx, objectPath(name))
+}
+
+func (fs *FileSystem) stat(ctx context.Context, objPath storj.Path) (*fileInfo, error) {
+	if objPath == "" {
+		return &fileInfo{dir: true, object: storj.Object{Created: fs.bucket.Created, Modified: fs.bucket.Created}}, nil
+	}
+
+	object, err := fs.metainfo.GetObject(ctx, fs.bucket.Name, objPath)
+	if err == nil {
+		return &fileInfo{object: object}, nil
+	}
+	if !storj.ErrObjectNotFound.Has(err) {
+		return nil, err
+	}
+
+	// object not found so maybe it's a prefix/directory
+	list, err := fs.metainfo.ListObjects(ctx, fs.bucket.Name, storj.ListOptions{Direction: storj.After, Prefix: objPath, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, os.ErrNotExist
+	}
+
+	return &fileInfo{dir: true, object: storj.Object{Path: objPath}}, nil
+}
+
+// move copies the object to the new path and deletes the old one
+func (fs *FileSystem) move(ctx context.Context, oldPath, newPath storj.Path) error {
+	readOnlyStream, err := fs.metainfo.GetObjectStream(ctx, fs.bucket.Name, oldPath)
+	if err != nil {
+		return convertError(err)
+	}
+
+	download := stream.NewDownload(ctx, readOnlyStream, fs.streams)
+	err = fs.upload(ctx, newPath, readOnlyStream.Info().ContentType, download)
+	err = utils.CombineErrors(err, download.Close())
+	if err != nil {
+		return err
+	}
+
+	return fs.metainfo.DeleteObject(ctx, fs.bucket.Name, oldPath)
+}
+
+// upload uploads the data as the object at objPath
+func (fs *FileSystem) upload(ctx context.Context, objPath storj.Path, contentType string, data io.Reader) error {
+	file, err := fs.createObject(ctx, objPath, contentType)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, data); err != nil {
+		return utils.CombineErrors(err, file.abort())
+	}
+
+	return file.Close()
+}
+
+// createFile creates a file which uploads the data written to it to the
+// object at objPath
+func (fs *FileSystem) createFile(ctx context.Context, objPath storj.Path) (*writeFile, error) {
+	contentType := mime.TypeByExtension(path.Ext(objPath))
+	file, err := fs.createObject(ctx, objPath, contentType)
+	if err != nil {
+		return nil, err
+	}
+	file.body, _ = ctx.Value(putBodyKey{}).(*putBody)
+	return file, nil
+}
+
+func (fs *FileSystem) createObject(ctx context.Context, objPath storj.Path, contentType string) (*writeFile, error) {
+	createInfo := storj.CreateObject{
+		ContentType: contentType,
+	}
+	object, err := fs.metainfo.CreateObject(ctx, fs.bucket.Name, objPath, &createInfo)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	mutableStream, err := object.CreateStream(ctx)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &writeFile{
+		ctx:      ctx,
+		fs:       fs,
+		object:   object,
+		upload:   stream.NewUpload(ctx, mutableStream, fs.streams),
+		modified: time.Now(),
+	}, nil
+}
+
+// listObjects calls handler for every object listed under prefix
+func (fs *FileSystem) listObjects(ctx context.Context, prefix storj.Path, recursive bool, handler func(storj.Object) error) error {
+	startAfter := ""
+
+	for {
+		list, err := fs.metainfo.ListObjects(ctx, fs.bucket.Name, storj.ListOptions{
+			Direction: storj.After,
+			Cursor:    startAfter,
+			Prefix:    prefix,
+			Recursive: recursive,
+		})
+		if err != nil {
+			return convertError(err)
+		}
+
+		for _, object := range list.Items {
+			if err := handler(object); err != nil {
+				return convertError(err)
+			}
+		}
+
+		if !list.More {
+			return nil
+		}
+
+		startAfter = list.Items[len(list.Items)-1].Path
+	}
+}
+
+// convertError converts the not found errors to os.ErrNotExist, which the
+// WebDAV handler maps to the corresponding status codes
+func convertError(err error) error {
+	if storj.ErrObjectNotFound.Has(err) || storj.ErrBucketNotFound.Has(err) {
+		return os.ErrNotExist
+	}
+	return err
+}
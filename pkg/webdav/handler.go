@@ -0,0 +1,116 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package webdav
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+
+	"go.uber.org/zap"
+	dav "golang.org/x/net/webdav"
+
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storj"
+)
+
+// Handler serves a bucket over WebDAV
+type Handler struct {
+	fs  *FileSystem
+	dav *dav.Handler
+	log *zap.Logger
+}
+
+// NewHandler creates a new http.Handler serving the file system over WebDAV
+func NewHandler(fs *FileSystem, log *zap.Logger) *Handler {
+	return &Handler{
+		fs: fs,
+		dav: &dav.Handler{
+			FileSystem: fs,
+			LockSystem: dav.NewMemLS(),
+			Logger: func(r *http.Request, err error) {
+				if err != nil {
+					log.Debug("request failed", zap.String("method", r.Method), zap.String("path", r.URL.Path), zap.Error(err))
+				}
+			},
+		},
+		log: log,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		handler.serveContent(w, r)
+	case http.MethodPut:
+		// the WebDAV handler closes the file even if copying the body fails
+		body := &putBody{ReadCloser: r.Body, expected: r.ContentLength}
+		r.Body = body
+		handler.dav.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), putBodyKey{}, body)))
+	default:
+		handler.dav.ServeHTTP(w, r)
+	}
+}
+
+// putBody tracks the body of a PUT request, so the upload of a truncated body
+// isn't committed
+type putBody struct {
+	io.ReadCloser
+	expected int64
+	err      error
+}
+
+// putBodyKey is the context key of the putBody of a request
+type putBodyKey struct{}
+
+// Read implements io.Reader
+func (body *putBody) Read(p []byte) (int, error) {
+	n, err := body.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && body.err == nil {
+		body.err = err
+	}
+	return n, err
+}
+
+// serveContent serves the objects with support for range requests without
+// downloading the parts of the object which weren't requested
+func (handler *Handler) serveContent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	objPath := objectPath(r.URL.Path)
+
+	info, err := handler.fs.stat(ctx, objPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.NotFound(w, r)
+			return
+		}
+		handler.log.Debug("stat failed", zap.String("path", objPath), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if info.IsDir() {
+		// let the WebDAV handler respond to the requests of directories
+		handler.dav.ServeHTTP(w, r)
+		return
+	}
+
+	rr, _, err := handler.fs.streams.Get(ctx, storj.JoinPaths(handler.fs.bucket.Name, objPath), handler.fs.bucket.PathCipher)
+	if err != nil {
+		handler.log.Debug("get failed", zap.String("path", objPath), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	contentType, _ := info.ContentType(ctx)
+	w.Header().Set("Content-Type", contentType)
+	if etag, err := info.ETag(ctx); err == nil {
+		w.Header().Set("ETag", etag)
+	}
+
+	ranger.ServeContent(ctx, w, r, info.Name(), info.ModTime(), rr)
+}
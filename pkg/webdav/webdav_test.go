@@ -0,0 +1,224 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package webdav
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vivint/infectious"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/encryption"
+	"storj.io/storj/pkg/metainfo/kvmetainfo"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/ec"
+	"storj.io/storj/pkg/storage/segments"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+)
+
+const (
+	TestAPIKey = "test-api-key"
+	TestEncKey = "test-encryption-key"
+	TestBucket = "test-bucket"
+)
+
+func TestWebDAV(t *testing.T) {
+	runTest(t, func(ctx context.Context, server *httptest.Server, metainfo storj.Metainfo) {
+		do := func(method, path string, body string, header map[string]string) (int, string) {
+			request, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+			require.NoError(t, err)
+			for key, value := range header {
+				request.Header.Set(key, value)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			defer func() { assert.NoError(t, response.Body.Close()) }()
+
+			data, err := ioutil.ReadAll(response.Body)
+			require.NoError(t, err)
+			return response.StatusCode, string(data)
+		}
+
+		status, _ := do("MKCOL", "/dir", "", nil)
+		assert.Equal(t, http.StatusCreated, status)
+
+		status, _ = do("MKCOL", "/missing/dir", "", nil)
+		assert.Equal(t, http.StatusConflict, status)
+
+		status, _ = do(http.MethodPut, "/dir/file.txt", "hello world", nil)
+		assert.Equal(t, http.StatusCreated, status)
+
+		object, err := metainfo.GetObject(ctx, TestBucket, "dir/file.txt")
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(len("hello world")), object.Size)
+			assert.True(t, strings.HasPrefix(object.ContentType, "text/plain"))
+		}
+
+		status, body := do(http.MethodGet, "/dir/file.txt", "", nil)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "hello world", body)
+
+		status, body = do(http.MethodGet, "/dir/file.txt", "", map[string]string{"Range": "bytes=6-10"})
+		assert.Equal(t, http.StatusPartialContent, status)
+		assert.Equal(t, "world", body)
+
+		status, _ = do(http.MethodGet, "/dir/missing.txt", "", nil)
+		assert.Equal(t, http.StatusNotFound, status)
+
+		status, body = do("PROPFIND", "/dir/", "", map[string]string{"Depth": "1"})
+		assert.Equal(t, http.StatusMultiStatus, status)
+		assert.Contains(t, body, "/dir/file.txt")
+		assert.Contains(t, body, "<D:getcontentlength>11</D:getcontentlength>")
+
+		status, _ = do("MOVE", "/dir/file.txt", "", map[string]string{"Destination": server.URL + "/moved.txt"})
+		assert.Equal(t, http.StatusCreated, status)
+
+		status, _ = do(http.MethodGet, "/dir/file.txt", "", nil)
+		assert.Equal(t, http.StatusNotFound, status)
+
+		status, body = do(http.MethodGet, "/moved.txt", "", nil)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "hello world", body)
+
+		status, _ = do(http.MethodDelete, "/dir", "", nil)
+		assert.Equal(t, http.StatusNoContent, status)
+
+		status, body = do("PROPFIND", "/", "", map[string]string{"Depth": "1"})
+		assert.Equal(t, http.StatusMultiStatus, status)
+		assert.Contains(t, body, "/moved.txt")
+		assert.NotContains(t, body, "/dir")
+	})
+}
+
+func TestWebDAVTruncatedPut(t *testing.T) {
+	runTest(t, func(ctx context.Context, server *httptest.Server, metainfo storj.Metainfo) {
+		for i, tt := range []struct {
+			body   io.Reader
+			length int64
+		}{
+			// the body is shorter than its Content-Length
+			{strings.NewReader("hello"), 20},
+			// reading the body fails
+			{io.MultiReader(strings.NewReader("hello"), iotest.TimeoutReader(strings.NewReader("world"))), -1},
+		} {
+			errTag := fmt.Sprintf("Test case #%d", i)
+
+			request := httptest.NewRequest(http.MethodPut, "/short.txt", tt.body)
+			request.ContentLength = tt.length
+
+			recorder := httptest.NewRecorder()
+			server.Config.Handler.ServeHTTP(recorder, request)
+			assert.NotEqual(t, http.StatusCreated, recorder.Code, errTag)
+
+			_, err := metainfo.GetObject(ctx, TestBucket, "short.txt")
+			assert.True(t, storj.ErrObjectNotFound.Has(err), errTag)
+		}
+	})
+}
+
+func runTest(t *testing.T, test func(context.Context, *httptest.Server, storj.Metainfo)) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 4, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	metainfo, streams, err := initEnv(planet)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	bucket, err := metainfo.CreateBucket(ctx, TestBucket, &storj.Bucket{
+		PathCipher: storj.AESGCM,
+		EncryptionScheme: storj.EncryptionScheme{
+			Cipher:    storj.AESGCM,
+			BlockSize: 1 * memory.KB.Int32(),
+		},
+		RedundancyScheme: storj.RedundancyScheme{
+			Algorithm:      storj.ReedSolomon,
+			RequiredShares: 2,
+			RepairShares:   3,
+			OptimalShares:  4,
+			TotalShares:    4,
+			ShareSize:      1 * memory.KB.Int32(),
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	fs := NewFileSystem(metainfo, streams, bucket)
+
+	server := httptest.NewServer(NewHandler(fs, zaptest.NewLogger(t)))
+	defer server.Close()
+
+	test(ctx, server, metainfo)
+}
+
+func initEnv(planet *testplanet.Planet) (storj.Metainfo, streams.Store, error) {
+	// TODO(kaloyan): We should have a better way for configuring the Satellite's API Key
+	err := flag.Set("pointer-db.auth.api-key", TestAPIKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oc, err := planet.Uplinks[0].DialOverlay(planet.Satellites[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pdb, err := planet.Uplinks[0].DialPointerDB(planet.Satellites[0], TestAPIKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ec := ecclient.NewClient(planet.Uplinks[0].Identity, 0)
+	fc, err := infectious.NewFEC(2, 4)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rs, err := eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, int(1*memory.KB)), 3, 4)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	segments := segments.NewSegmentStore(oc, ec, pdb, rs, int(8*memory.KB))
+
+	key := new(storj.Key)
+	copy(key[:], TestEncKey)
+	keys := encryption.NewKeyStore(key, false)
+
+	streams, err := streams.NewStreamStore(segments, int64(64*memory.MB), keys, int(1*memory.KB), storj.AESGCM, 0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	buckets := buckets.NewStore(streams)
+
+	return kvmetainfo.New(buckets, streams, segments, pdb, keys), streams, nil
+}
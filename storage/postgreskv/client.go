@@ -24,6 +24,7 @@ const (
 type Client struct {
 	URL    string
 	pgConn *sql.DB
+	bucket storage.Key
 }
 
 // New instantiates a new postgreskv client given db URL
@@ -39,12 +40,35 @@ func New(dbURL string) (*Client, error) {
 	return &Client{
 		URL:    dbURL,
 		pgConn: pgConn,
+		bucket: storage.Key(defaultBucket),
 	}, nil
 }
 
+// NewBucket instantiates a new postgreskv client given db URL, which keeps
+// its keys in the given bucket, separate from the keys of the default bucket
+func NewBucket(dbURL string, bucket string) (*Client, error) {
+	client, err := New(dbURL)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
+		INSERT INTO buckets (bucketname, delim)
+			VALUES ($1::BYTEA, ascii('/'))
+			ON CONFLICT (bucketname) DO NOTHING
+	`
+	_, err = client.pgConn.Exec(q, []byte(bucket))
+	if err != nil {
+		return nil, utils.CombineErrors(err, client.Close())
+	}
+
+	client.bucket = storage.Key(bucket)
+	return client, nil
+}
+
 // Put sets the value for the provided key.
 func (client *Client) Put(key storage.Key, value storage.Value) error {
-	return client.PutPath(storage.Key(defaultBucket), key, value)
+	return client.PutPath(client.bucket, key, value)
 }
 
 // PutPath sets the value for the provided key (in the given bucket).
@@ -63,7 +87,7 @@ func (client *Client) PutPath(bucket, key storage.Key, value storage.Value) erro
 
 // Get looks up the provided key and returns its value (or an error).
 func (client *Client) Get(key storage.Key) (storage.Value, error) {
-	return client.GetPath(storage.Key(defaultBucket), key)
+	return client.GetPath(client.bucket, key)
 }
 
 // GetPath looks up the provided key (in the given bucket) and returns its value (or an error).
@@ -87,7 +111,7 @@ func (client *Client) GetPath(bucket, key storage.Key) (storage.Value, error) {
 
 // Delete deletes the given key and its associated value.
 func (client *Client) Delete(key storage.Key) error {
-	return client.DeletePath(storage.Key(defaultBucket), key)
+	return client.DeletePath(client.bucket, key)
 }
 
 // DeletePath deletes the given key (in the given bucket) and its associated value.
@@ -130,7 +154,7 @@ func (client *Client) Close() error {
 // GetAll finds all values for the provided keys (up to storage.LookupLimit).
 // If more keys are provided than the maximum, an error will be returned.
 func (client *Client) GetAll(keys storage.Keys) (storage.Values, error) {
-	return client.GetAllPath(storage.Key(defaultBucket), keys)
+	return client.GetAllPath(client.bucket, keys)
 }
 
 // GetAllPath finds all values for the provided keys (up to storage.LookupLimit)
@@ -275,7 +299,7 @@ func newOrderedPostgresIterator(pgClient *Client, opts storage.IterateOptions, b
 	opi := &orderedPostgresIterator{
 		client:    pgClient,
 		opts:      &opts,
-		bucket:    storage.Key(defaultBucket),
+		bucket:    pgClient.bucket,
 		delimiter: byte('/'),
 		batchSize: batchSize,
 		curIndex:  0,